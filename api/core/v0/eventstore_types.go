@@ -38,8 +38,14 @@ type EventStoreAutoScaling struct {
 
 // EventStoreStatus defines the observed state of EventStore
 type EventStoreStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Generation of the EventStore observed by the last reconciliation
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// URL of the event store KService
+	ServiceURL string `json:"serviceURL,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,245 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v0
+
+import (
+	"encoding/json"
+
+	dtdv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// Annotation of the v1 TwinInstance with the v0 spec fields that v1 keeps in the status. The status subresource drops
+// the status written on create and update, so they are restored from the annotation while the status does not have them.
+const twinInstanceSpecDataAnnotation = "dtd.ktwin/v0-spec-data"
+
+// The v0 spec fields that are not in the v1 spec
+type twinInstanceSpecData struct {
+	EndpointSettings *TwinInstanceEndpointSettings `json:"endpointSettings,omitempty"`
+	Telemetries      []TwinInstanceTelemetryData   `json:"telemetries,omitempty"`
+}
+
+// ConvertTo converts this TwinInstance to the Hub version (v1).
+// The endpoint settings and telemetry values are generated data, which v1 keeps in the status.
+func (src *TwinInstance) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*dtdv1.TwinInstance)
+
+	dst.ObjectMeta = src.ObjectMeta
+	if err := setTwinInstanceSpecDataAnnotation(&dst.ObjectMeta, src.Spec); err != nil {
+		return err
+	}
+
+	dst.Spec = dtdv1.TwinInstanceSpec{
+		Interface:        src.Spec.Interface,
+		InterfaceVersion: src.Spec.InterfaceVersion,
+	}
+	dst.Status = dtdv1.TwinInstanceStatus{
+		Status:             dtdv1.TwinInstancePhase(src.Status.Status),
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+		Bindings:           src.Status.Bindings,
+		ResolvedInterface:  src.Status.ResolvedInterface,
+	}
+
+	for _, relationship := range src.Spec.TwinInstanceRelationships {
+		dst.Spec.Relationships = append(dst.Spec.Relationships, dtdv1.TwinInstanceRelationship{
+			Name:      relationship.Name,
+			Interface: relationship.Interface,
+			Instance:  relationship.Instance,
+		})
+	}
+
+	if src.Spec.Data != nil {
+		dst.Spec.Data = &dtdv1.TwinInstanceDataSpec{}
+		for _, property := range src.Spec.Data.Properties {
+			dst.Spec.Data.Properties = append(dst.Spec.Data.Properties, dtdv1.TwinInstancePropertyData{
+				Id:    property.Id,
+				Name:  property.Name,
+				Value: property.Value,
+			})
+		}
+		for _, telemetry := range src.Spec.Data.Telemetries {
+			dst.Status.Telemetries = append(dst.Status.Telemetries, dtdv1.TwinInstanceTelemetryData{
+				Id:    telemetry.Id,
+				Name:  telemetry.Name,
+				Value: telemetry.Value,
+			})
+		}
+	}
+
+	if src.Spec.EndpointSettings != nil {
+		dst.Status.EndpointSettings = &dtdv1.TwinInstanceEndpointSettings{}
+		if src.Spec.EndpointSettings.HttpEndpoint != nil {
+			dst.Status.EndpointSettings.HttpEndpoint = &dtdv1.TwinInstanceHttpEndpointSettings{
+				Url: src.Spec.EndpointSettings.HttpEndpoint.Url,
+			}
+		}
+		if src.Spec.EndpointSettings.MqttEndpoint != nil {
+			dst.Status.EndpointSettings.MqttEndpoint = &dtdv1.TwinInstanceMqttEndpointSettings{
+				Url:             src.Spec.EndpointSettings.MqttEndpoint.Url,
+				PublisherTopic:  src.Spec.EndpointSettings.MqttEndpoint.PublisherTopic,
+				SubscriberTopic: src.Spec.EndpointSettings.MqttEndpoint.SubscriberTopic,
+			}
+		}
+		if src.Spec.EndpointSettings.AmqpEndpoint != nil {
+			dst.Status.EndpointSettings.AmqpEndpoint = &dtdv1.TwinInstanceAmqpEndpointSettings{
+				Url:             src.Spec.EndpointSettings.AmqpEndpoint.Url,
+				PublisherTopic:  src.Spec.EndpointSettings.AmqpEndpoint.PublisherTopic,
+				SubscriberTopic: src.Spec.EndpointSettings.AmqpEndpoint.SubscriberTopic,
+			}
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version.
+// The endpoint settings and telemetry values of the v1 status are moved back to the spec, or restored from the
+// annotation while the status does not have them.
+func (dst *TwinInstance) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*dtdv1.TwinInstance)
+
+	dst.ObjectMeta = src.ObjectMeta
+	specData, err := popTwinInstanceSpecDataAnnotation(&dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec = TwinInstanceSpec{
+		Interface:        src.Spec.Interface,
+		InterfaceVersion: src.Spec.InterfaceVersion,
+	}
+	dst.Status = TwinInstanceStatus{
+		Status:             TwinInstancePhase(src.Status.Status),
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+		Bindings:           src.Status.Bindings,
+		ResolvedInterface:  src.Status.ResolvedInterface,
+	}
+
+	for _, relationship := range src.Spec.Relationships {
+		dst.Spec.TwinInstanceRelationships = append(dst.Spec.TwinInstanceRelationships, TwinInstanceRelationship{
+			Name:      relationship.Name,
+			Interface: relationship.Interface,
+			Instance:  relationship.Instance,
+		})
+	}
+
+	if src.Spec.Data != nil || len(src.Status.Telemetries) > 0 || len(specData.Telemetries) > 0 {
+		dst.Spec.Data = &TwinInstanceDataSpec{}
+		if src.Spec.Data != nil {
+			for _, property := range src.Spec.Data.Properties {
+				dst.Spec.Data.Properties = append(dst.Spec.Data.Properties, TwinInstancePropertyData{
+					Id:    property.Id,
+					Name:  property.Name,
+					Value: property.Value,
+				})
+			}
+		}
+		for _, telemetry := range src.Status.Telemetries {
+			dst.Spec.Data.Telemetries = append(dst.Spec.Data.Telemetries, TwinInstanceTelemetryData{
+				Id:    telemetry.Id,
+				Name:  telemetry.Name,
+				Value: telemetry.Value,
+			})
+		}
+		if len(src.Status.Telemetries) == 0 {
+			dst.Spec.Data.Telemetries = specData.Telemetries
+		}
+	}
+
+	dst.Spec.EndpointSettings = specData.EndpointSettings
+	if src.Status.EndpointSettings != nil {
+		dst.Spec.EndpointSettings = &TwinInstanceEndpointSettings{}
+		if src.Status.EndpointSettings.HttpEndpoint != nil {
+			dst.Spec.EndpointSettings.HttpEndpoint = &TwinInstanceHttpEndpointSettings{
+				Url: src.Status.EndpointSettings.HttpEndpoint.Url,
+			}
+		}
+		if src.Status.EndpointSettings.MqttEndpoint != nil {
+			dst.Spec.EndpointSettings.MqttEndpoint = &TwinInstanceMqttEndpointSettings{
+				Url:             src.Status.EndpointSettings.MqttEndpoint.Url,
+				PublisherTopic:  src.Status.EndpointSettings.MqttEndpoint.PublisherTopic,
+				SubscriberTopic: src.Status.EndpointSettings.MqttEndpoint.SubscriberTopic,
+			}
+		}
+		if src.Status.EndpointSettings.AmqpEndpoint != nil {
+			dst.Spec.EndpointSettings.AmqpEndpoint = &TwinInstanceAmqpEndpointSettings{
+				Url:             src.Status.EndpointSettings.AmqpEndpoint.Url,
+				PublisherTopic:  src.Status.EndpointSettings.AmqpEndpoint.PublisherTopic,
+				SubscriberTopic: src.Status.EndpointSettings.AmqpEndpoint.SubscriberTopic,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Keep the v0 spec fields that v1 has in the status in an annotation, so they survive the status subresource
+func setTwinInstanceSpecDataAnnotation(objectMeta *metav1.ObjectMeta, spec TwinInstanceSpec) error {
+	specData := twinInstanceSpecData{EndpointSettings: spec.EndpointSettings}
+	if spec.Data != nil {
+		specData.Telemetries = spec.Data.Telemetries
+	}
+
+	objectMeta.Annotations = removeAnnotation(objectMeta.Annotations, twinInstanceSpecDataAnnotation)
+	if specData.EndpointSettings == nil && len(specData.Telemetries) == 0 {
+		return nil
+	}
+
+	value, err := json.Marshal(specData)
+	if err != nil {
+		return err
+	}
+
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = map[string]string{}
+	}
+	objectMeta.Annotations[twinInstanceSpecDataAnnotation] = string(value)
+
+	return nil
+}
+
+// Remove the annotation with the v0 spec fields that v1 has in the status and return its fields
+func popTwinInstanceSpecDataAnnotation(objectMeta *metav1.ObjectMeta) (twinInstanceSpecData, error) {
+	specData := twinInstanceSpecData{}
+	value, ok := objectMeta.Annotations[twinInstanceSpecDataAnnotation]
+	if !ok {
+		return specData, nil
+	}
+
+	objectMeta.Annotations = removeAnnotation(objectMeta.Annotations, twinInstanceSpecDataAnnotation)
+	err := json.Unmarshal([]byte(value), &specData)
+	return specData, err
+}
+
+// Return a copy of the annotations without the key, as the converted object shares the map of the source object
+func removeAnnotation(annotations map[string]string, key string) map[string]string {
+	var copiedAnnotations map[string]string
+	for annotationKey, value := range annotations {
+		if annotationKey == key {
+			continue
+		}
+		if copiedAnnotations == nil {
+			copiedAnnotations = map[string]string{}
+		}
+		copiedAnnotations[annotationKey] = value
+	}
+	return copiedAnnotations
+}
@@ -0,0 +1,197 @@
+package v0
+
+import (
+	"testing"
+
+	dtdv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTwinInstance_ConvertTo(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		twinInstance *TwinInstance
+		expected     *dtdv1.TwinInstance
+	}{
+		{
+			name: "TwinInstance without data",
+			twinInstance: &TwinInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "city-001", Namespace: "default"},
+				Spec:       TwinInstanceSpec{Interface: "city"},
+			},
+			expected: &dtdv1.TwinInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "city-001", Namespace: "default"},
+				Spec:       dtdv1.TwinInstanceSpec{Interface: "city"},
+			},
+		},
+		{
+			name: "TwinInstance with generated data",
+			twinInstance: &TwinInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "neighborhood-001", Namespace: "default"},
+				Spec: TwinInstanceSpec{
+					Interface:        "neighborhood",
+					InterfaceVersion: "latest",
+					EndpointSettings: &TwinInstanceEndpointSettings{
+						MqttEndpoint: &TwinInstanceMqttEndpointSettings{PublisherTopic: "ktwin.real.neighborhood.neighborhood-001"},
+					},
+					Data: &TwinInstanceDataSpec{
+						Properties:  []TwinInstancePropertyData{{Name: "name", Value: "Downtown"}},
+						Telemetries: []TwinInstanceTelemetryData{{Name: "temperature", Value: "21.5"}},
+					},
+					TwinInstanceRelationships: []TwinInstanceRelationship{{Name: "refCity", Interface: "city", Instance: "city-001"}},
+				},
+				Status: TwinInstanceStatus{
+					Status:            TwinInstancePhaseRunning,
+					Bindings:          []string{"neighborhood-001-real"},
+					ResolvedInterface: "neighborhood",
+				},
+			},
+			expected: &dtdv1.TwinInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "neighborhood-001",
+					Namespace: "default",
+					Annotations: map[string]string{
+						twinInstanceSpecDataAnnotation: `{"endpointSettings":{"mqttEndpoint":{"publisherTopic":"ktwin.real.neighborhood.neighborhood-001"}},"telemetries":[{"name":"temperature","value":"21.5"}]}`,
+					},
+				},
+				Spec: dtdv1.TwinInstanceSpec{
+					Interface:        "neighborhood",
+					InterfaceVersion: "latest",
+					Data:             &dtdv1.TwinInstanceDataSpec{Properties: []dtdv1.TwinInstancePropertyData{{Name: "name", Value: "Downtown"}}},
+					Relationships:    []dtdv1.TwinInstanceRelationship{{Name: "refCity", Interface: "city", Instance: "city-001"}},
+				},
+				Status: dtdv1.TwinInstanceStatus{
+					Status:            dtdv1.TwinInstancePhaseRunning,
+					Bindings:          []string{"neighborhood-001-real"},
+					ResolvedInterface: "neighborhood",
+					EndpointSettings: &dtdv1.TwinInstanceEndpointSettings{
+						MqttEndpoint: &dtdv1.TwinInstanceMqttEndpointSettings{PublisherTopic: "ktwin.real.neighborhood.neighborhood-001"},
+					},
+					Telemetries: []dtdv1.TwinInstanceTelemetryData{{Name: "temperature", Value: "21.5"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &dtdv1.TwinInstance{}
+			assert.Nil(t, tt.twinInstance.ConvertTo(hub))
+			assert.Equal(t, tt.expected, hub)
+
+			converted := &TwinInstance{}
+			assert.Nil(t, converted.ConvertFrom(hub))
+			assert.Equal(t, tt.twinInstance, converted)
+		})
+	}
+}
+
+func TestTwinInstance_ConvertFrom(t *testing.T) {
+
+	hub := &dtdv1.TwinInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "neighborhood-001", Namespace: "default"},
+		Spec: dtdv1.TwinInstanceSpec{
+			Interface: "neighborhood",
+			Data:      &dtdv1.TwinInstanceDataSpec{Properties: []dtdv1.TwinInstancePropertyData{{Name: "population", Value: "1200"}}},
+		},
+		Status: dtdv1.TwinInstanceStatus{
+			EndpointSettings: &dtdv1.TwinInstanceEndpointSettings{
+				HttpEndpoint: &dtdv1.TwinInstanceHttpEndpointSettings{Url: "http://neighborhood-001"},
+				AmqpEndpoint: &dtdv1.TwinInstanceAmqpEndpointSettings{SubscriberTopic: "ktwin.virtual.neighborhood.neighborhood-001"},
+			},
+			Telemetries: []dtdv1.TwinInstanceTelemetryData{{Name: "temperature", Value: "21.5"}},
+		},
+	}
+
+	twinInstance := &TwinInstance{}
+	assert.Nil(t, twinInstance.ConvertFrom(hub))
+	assert.Equal(t, "http://neighborhood-001", twinInstance.Spec.EndpointSettings.HttpEndpoint.Url)
+	assert.Equal(t, []TwinInstanceTelemetryData{{Name: "temperature", Value: "21.5"}}, twinInstance.Spec.Data.Telemetries)
+
+	// Converting back keeps the v0 spec fields in the annotation too
+	converted := &dtdv1.TwinInstance{}
+	assert.Nil(t, twinInstance.ConvertTo(converted))
+	assert.Equal(t, hub.Spec, converted.Spec)
+	assert.Equal(t, hub.Status, converted.Status)
+	assert.Contains(t, converted.Annotations, twinInstanceSpecDataAnnotation)
+	assert.Nil(t, hub.Annotations)
+}
+
+func TestTwinInstance_ConvertTo_StatusDropped(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		twinInstance *TwinInstance
+	}{
+		{
+			name: "TwinInstance with endpoint settings and telemetries",
+			twinInstance: &TwinInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "neighborhood-001", Namespace: "default", Annotations: map[string]string{"owner": "city"}},
+				Spec: TwinInstanceSpec{
+					Interface: "neighborhood",
+					EndpointSettings: &TwinInstanceEndpointSettings{
+						HttpEndpoint: &TwinInstanceHttpEndpointSettings{Url: "http://neighborhood-001"},
+						MqttEndpoint: &TwinInstanceMqttEndpointSettings{PublisherTopic: "ktwin.real.neighborhood.neighborhood-001"},
+					},
+					Data: &TwinInstanceDataSpec{
+						Properties:  []TwinInstancePropertyData{{Name: "name", Value: "Downtown"}},
+						Telemetries: []TwinInstanceTelemetryData{{Id: "temperature-001", Name: "temperature", Value: "21.5"}},
+					},
+				},
+			},
+		},
+		{
+			name: "TwinInstance with telemetries only",
+			twinInstance: &TwinInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "neighborhood-001", Namespace: "default"},
+				Spec: TwinInstanceSpec{
+					Interface: "neighborhood",
+					Data:      &TwinInstanceDataSpec{Telemetries: []TwinInstanceTelemetryData{{Name: "temperature", Value: "21.5"}}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &dtdv1.TwinInstance{}
+			assert.Nil(t, tt.twinInstance.ConvertTo(hub))
+
+			// The status subresource drops the status written on create and update
+			hub.Status = dtdv1.TwinInstanceStatus{}
+
+			converted := &TwinInstance{}
+			assert.Nil(t, converted.ConvertFrom(hub))
+			assert.Equal(t, tt.twinInstance, converted)
+		})
+	}
+}
+
+func TestTwinInstance_ConvertFrom_StatusPrecedence(t *testing.T) {
+
+	twinInstance := &TwinInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "neighborhood-001", Namespace: "default"},
+		Spec: TwinInstanceSpec{
+			Interface: "neighborhood",
+			EndpointSettings: &TwinInstanceEndpointSettings{
+				MqttEndpoint: &TwinInstanceMqttEndpointSettings{PublisherTopic: "ktwin.real.neighborhood.neighborhood-001"},
+			},
+		},
+	}
+
+	hub := &dtdv1.TwinInstance{}
+	assert.Nil(t, twinInstance.ConvertTo(hub))
+
+	// The endpoint settings written by the controller in the status replace the ones of the annotation
+	hub.Status.EndpointSettings = &dtdv1.TwinInstanceEndpointSettings{
+		HttpEndpoint: &dtdv1.TwinInstanceHttpEndpointSettings{Url: "http://neighborhood-001"},
+	}
+
+	converted := &TwinInstance{}
+	assert.Nil(t, converted.ConvertFrom(hub))
+	assert.Equal(t, &TwinInstanceEndpointSettings{HttpEndpoint: &TwinInstanceHttpEndpointSettings{Url: "http://neighborhood-001"}}, converted.Spec.EndpointSettings)
+	assert.Nil(t, converted.Annotations)
+}
@@ -31,7 +31,11 @@ const (
 
 // TwinInstanceSpec defines the desired state of TwinInstance
 type TwinInstanceSpec struct {
-	Interface                 string                        `json:"interface,omitempty"`
+	Interface string `json:"interface,omitempty"`
+	// Version of the model of the TwinInterface whose service processes the TwinInstance events: a version number
+	// or latest, for the highest version. When empty, the TwinInterface informed in interface is used.
+	// +kubebuilder:validation:Pattern=`^(latest|[1-9][0-9]*)$`
+	InterfaceVersion          string                        `json:"interfaceVersion,omitempty"`
 	EndpointSettings          *TwinInstanceEndpointSettings `json:"endpointSettings,omitempty"`
 	Data                      *TwinInstanceDataSpec         `json:"data,omitempty"`
 	TwinInstanceRelationships []TwinInstanceRelationship    `json:"twinInstanceRelationships,omitempty"`
@@ -97,6 +101,16 @@ type TwinInterfaceEventsSink struct {
 // TwinInstanceStatus defines the observed state of TwinInstance
 type TwinInstanceStatus struct {
 	Status TwinInstancePhase `json:"status,omitempty"`
+	// Generation of the TwinInstance observed by the last reconciliation
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Names of the RabbitMQ Bindings generated for the TwinInstance
+	Bindings []string `json:"bindings,omitempty"`
+	// TwinInterface resolved from interface and interfaceVersion, whose service processes the TwinInstance events
+	ResolvedInterface string `json:"resolvedInterface,omitempty"`
 }
 
 //+kubebuilder:object:root=true
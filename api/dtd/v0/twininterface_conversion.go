@@ -0,0 +1,432 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v0
+
+import (
+	dtdv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this TwinInterface to the Hub version (v1)
+func (src *TwinInterface) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*dtdv1.TwinInterface)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertTwinInterfaceSpecToV1(src.Spec)
+	dst.Status = dtdv1.TwinInterfaceStatus{
+		Status:             dtdv1.TwinInterfacePhase(src.Status.Status),
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+		ServiceURL:         src.Status.ServiceURL,
+		Bindings:           src.Status.Bindings,
+	}
+
+	if src.Status.Cleanup != nil {
+		dst.Status.Cleanup = &dtdv1.TwinInterfaceCleanupStatus{
+			RemainingBindings: src.Status.Cleanup.RemainingBindings,
+			RemainingTriggers: src.Status.Cleanup.RemainingTriggers,
+			RemainingServices: src.Status.Cleanup.RemainingServices,
+			RemainingQueues:   src.Status.Cleanup.RemainingQueues,
+			Completed:         src.Status.Cleanup.Completed,
+		}
+	}
+
+	if src.Status.EffectiveSpec != nil {
+		effectiveSpec := convertTwinInterfaceSpecToV1(*src.Status.EffectiveSpec)
+		dst.Status.EffectiveSpec = &effectiveSpec
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version
+func (dst *TwinInterface) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*dtdv1.TwinInterface)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertTwinInterfaceSpecFromV1(src.Spec)
+	dst.Status = TwinInterfaceStatus{
+		Status:             TwinInterfacePhase(src.Status.Status),
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         src.Status.Conditions,
+		ServiceURL:         src.Status.ServiceURL,
+		Bindings:           src.Status.Bindings,
+	}
+
+	if src.Status.Cleanup != nil {
+		dst.Status.Cleanup = &TwinInterfaceCleanupStatus{
+			RemainingBindings: src.Status.Cleanup.RemainingBindings,
+			RemainingTriggers: src.Status.Cleanup.RemainingTriggers,
+			RemainingServices: src.Status.Cleanup.RemainingServices,
+			RemainingQueues:   src.Status.Cleanup.RemainingQueues,
+			Completed:         src.Status.Cleanup.Completed,
+		}
+	}
+
+	if src.Status.EffectiveSpec != nil {
+		effectiveSpec := convertTwinInterfaceSpecFromV1(*src.Status.EffectiveSpec)
+		dst.Status.EffectiveSpec = &effectiveSpec
+	}
+
+	return nil
+}
+
+func convertTwinInterfaceSpecToV1(src TwinInterfaceSpec) dtdv1.TwinInterfaceSpec {
+	dst := dtdv1.TwinInterfaceSpec{
+		Id:          src.Id,
+		ModelId:     src.ModelId,
+		Version:     src.Version,
+		DisplayName: src.DisplayName,
+		Description: src.Description,
+		Comment:     src.Comment,
+		Properties:  convertTwinPropertiesToV1(src.Properties),
+		Extends:     src.GetExtends(),
+		EventStore: dtdv1.TwinInterfaceEventStore{
+			PersistRealEvent:    src.EventStore.PersistRealEvent,
+			PersistVirtualEvent: src.EventStore.PersistVirtualEvent,
+		},
+	}
+
+	for _, command := range src.Commands {
+		dst.Commands = append(dst.Commands, dtdv1.TwinCommand{
+			Id:          command.Id,
+			Comment:     command.Comment,
+			Description: command.Description,
+			DisplayName: command.DisplayName,
+			Name:        command.Name,
+			Request: dtdv1.TwinCommandPayload{
+				Name:        command.Request.Name,
+				DisplayName: command.Request.DisplayName,
+				Description: command.Request.Description,
+				Schema:      convertTwinSchemaToV1(command.Request.Schema),
+			},
+			Response: dtdv1.TwinCommandPayload{
+				Name:        command.Response.Name,
+				DisplayName: command.Response.DisplayName,
+				Description: command.Response.Description,
+				Schema:      convertTwinSchemaToV1(command.Response.Schema),
+			},
+		})
+	}
+
+	for _, relationship := range src.Relationships {
+		dst.Relationships = append(dst.Relationships, dtdv1.TwinRelationship{
+			Id:              relationship.Id,
+			Comment:         relationship.Comment,
+			Description:     relationship.Description,
+			DisplayName:     relationship.DisplayName,
+			MaxMultiplicity: relationship.MaxMultiplicity,
+			MinMultiplicity: relationship.MinMultiplicity,
+			Name:            relationship.Name,
+			Properties:      convertTwinPropertiesToV1(relationship.Properties),
+			Interface:       relationship.Interface,
+			Schema:          convertTwinSchemaToV1(relationship.Schema),
+			Writable:        relationship.Writeable,
+			AggregateData:   relationship.AggregateData,
+		})
+	}
+
+	for _, telemetry := range src.Telemetries {
+		dst.Telemetries = append(dst.Telemetries, dtdv1.TwinTelemetry{
+			Id:            telemetry.Id,
+			Comment:       telemetry.Comment,
+			Description:   telemetry.Description,
+			DisplayName:   telemetry.DisplayName,
+			Name:          telemetry.Name,
+			Schema:        convertTwinSchemaToV1(telemetry.Schema),
+			SemanticTypes: telemetry.SemanticTypes,
+			Unit:          telemetry.Unit,
+		})
+	}
+
+	for _, component := range src.Components {
+		dst.Components = append(dst.Components, dtdv1.TwinComponent{
+			Id:          component.Id,
+			Comment:     component.Comment,
+			Description: component.Description,
+			DisplayName: component.DisplayName,
+			Name:        component.Name,
+			Interface:   component.Interface,
+		})
+	}
+
+	if src.Service != nil {
+		dst.Service = &dtdv1.TwinInterfaceService{
+			Template: *src.Service.Template.DeepCopy(),
+			AutoScaling: dtdv1.TwinInterfaceAutoScaling{
+				MinScale:                    src.Service.AutoScaling.MinScale,
+				MaxScale:                    src.Service.AutoScaling.MaxScale,
+				Target:                      src.Service.AutoScaling.Target,
+				TargetUtilizationPercentage: src.Service.AutoScaling.TargetUtilizationPercentage,
+				Parallelism:                 src.Service.AutoScaling.Parallelism,
+				Metric:                      dtdv1.AutoScalerType(src.Service.AutoScaling.Metric),
+			},
+		}
+	}
+
+	return dst
+}
+
+func convertTwinInterfaceSpecFromV1(src dtdv1.TwinInterfaceSpec) TwinInterfaceSpec {
+	dst := TwinInterfaceSpec{
+		Id:          src.Id,
+		ModelId:     src.ModelId,
+		Version:     src.Version,
+		DisplayName: src.DisplayName,
+		Description: src.Description,
+		Comment:     src.Comment,
+		Properties:  convertTwinPropertiesFromV1(src.Properties),
+		// The deprecated ExtendsInterface is merged into Extends by ConvertTo and is not restored
+		Extends: src.Extends,
+		EventStore: TwinInterfaceEventStore{
+			PersistRealEvent:    src.EventStore.PersistRealEvent,
+			PersistVirtualEvent: src.EventStore.PersistVirtualEvent,
+		},
+	}
+
+	for _, command := range src.Commands {
+		dst.Commands = append(dst.Commands, TwinCommand{
+			Id:          command.Id,
+			Comment:     command.Comment,
+			Description: command.Description,
+			DisplayName: command.DisplayName,
+			Name:        command.Name,
+			Request: CommandRequest{
+				Name:        command.Request.Name,
+				DisplayName: command.Request.DisplayName,
+				Description: command.Request.Description,
+				Schema:      convertTwinSchemaFromV1(command.Request.Schema),
+			},
+			Response: CommandResponse{
+				Name:        command.Response.Name,
+				DisplayName: command.Response.DisplayName,
+				Description: command.Response.Description,
+				Schema:      convertTwinSchemaFromV1(command.Response.Schema),
+			},
+		})
+	}
+
+	for _, relationship := range src.Relationships {
+		dst.Relationships = append(dst.Relationships, TwinRelationship{
+			Id:              relationship.Id,
+			Comment:         relationship.Comment,
+			Description:     relationship.Description,
+			DisplayName:     relationship.DisplayName,
+			MaxMultiplicity: relationship.MaxMultiplicity,
+			MinMultiplicity: relationship.MinMultiplicity,
+			Name:            relationship.Name,
+			Properties:      convertTwinPropertiesFromV1(relationship.Properties),
+			Interface:       relationship.Interface,
+			Schema:          convertTwinSchemaFromV1(relationship.Schema),
+			Writeable:       relationship.Writable,
+			AggregateData:   relationship.AggregateData,
+		})
+	}
+
+	for _, telemetry := range src.Telemetries {
+		dst.Telemetries = append(dst.Telemetries, TwinTelemetry{
+			Id:            telemetry.Id,
+			Comment:       telemetry.Comment,
+			Description:   telemetry.Description,
+			DisplayName:   telemetry.DisplayName,
+			Name:          telemetry.Name,
+			Schema:        convertTwinSchemaFromV1(telemetry.Schema),
+			SemanticTypes: telemetry.SemanticTypes,
+			Unit:          telemetry.Unit,
+		})
+	}
+
+	for _, component := range src.Components {
+		dst.Components = append(dst.Components, TwinComponent{
+			Id:          component.Id,
+			Comment:     component.Comment,
+			Description: component.Description,
+			DisplayName: component.DisplayName,
+			Name:        component.Name,
+			Interface:   component.Interface,
+		})
+	}
+
+	if src.Service != nil {
+		dst.Service = &TwinInterfaceService{
+			Template: *src.Service.Template.DeepCopy(),
+			AutoScaling: TwinInterfaceAutoScaling{
+				MinScale:                    src.Service.AutoScaling.MinScale,
+				MaxScale:                    src.Service.AutoScaling.MaxScale,
+				Target:                      src.Service.AutoScaling.Target,
+				TargetUtilizationPercentage: src.Service.AutoScaling.TargetUtilizationPercentage,
+				Parallelism:                 src.Service.AutoScaling.Parallelism,
+				Metric:                      AutoScalerType(src.Service.AutoScaling.Metric),
+			},
+		}
+	}
+
+	return dst
+}
+
+func convertTwinPropertiesToV1(src []TwinProperty) []dtdv1.TwinProperty {
+	var dst []dtdv1.TwinProperty
+	for _, property := range src {
+		dst = append(dst, dtdv1.TwinProperty{
+			Id:            property.Id,
+			Comment:       property.Comment,
+			Description:   property.Description,
+			DisplayName:   property.DisplayName,
+			Name:          property.Name,
+			Schema:        convertTwinSchemaToV1(property.Schema),
+			SemanticTypes: property.SemanticTypes,
+			Unit:          property.Unit,
+			Writable:      property.Writeable,
+		})
+	}
+	return dst
+}
+
+func convertTwinPropertiesFromV1(src []dtdv1.TwinProperty) []TwinProperty {
+	var dst []TwinProperty
+	for _, property := range src {
+		dst = append(dst, TwinProperty{
+			Id:            property.Id,
+			Comment:       property.Comment,
+			Description:   property.Description,
+			DisplayName:   property.DisplayName,
+			Name:          property.Name,
+			Schema:        convertTwinSchemaFromV1(property.Schema),
+			SemanticTypes: property.SemanticTypes,
+			Unit:          property.Unit,
+			Writeable:     property.Writable,
+		})
+	}
+	return dst
+}
+
+// The v0 complex types are converted to v1 object schemas
+func convertTwinSchemaToV1(src *TwinSchema) *dtdv1.TwinSchema {
+	if src == nil {
+		return nil
+	}
+
+	dst := &dtdv1.TwinSchema{
+		PrimitiveType: dtdv1.PrimitiveType(src.PrimitiveType),
+		Enum:          convertTwinEnumSchemaToV1(src.EnumType),
+	}
+
+	if src.ComplexType != nil {
+		dst.Object = &dtdv1.TwinObjectSchema{}
+		for _, complexField := range src.ComplexType.Fields {
+			dst.Object.Fields = append(dst.Object.Fields, dtdv1.TwinObjectSchemaField{
+				Name:        complexField.Name,
+				DisplayName: complexField.DisplayName,
+				Description: complexField.Description,
+				Schema:      convertTwinSchemaToV1(complexField.Schema),
+			})
+		}
+	}
+
+	if src.ArrayType != nil {
+		dst.Array = &dtdv1.TwinArraySchema{
+			ElementSchema: convertTwinSchemaToV1(src.ArrayType.ElementSchema),
+		}
+	}
+
+	if src.MapType != nil {
+		dst.Map = &dtdv1.TwinMapSchema{
+			MapKey: dtdv1.TwinMapKey{Name: src.MapType.MapKey.Name},
+			MapValue: dtdv1.TwinMapValue{
+				Name:   src.MapType.MapValue.Name,
+				Schema: convertTwinSchemaToV1(src.MapType.MapValue.Schema),
+			},
+		}
+	}
+
+	return dst
+}
+
+// The v1 object schemas are converted to v0 complex types, which only support the Object type
+func convertTwinSchemaFromV1(src *dtdv1.TwinSchema) *TwinSchema {
+	if src == nil {
+		return nil
+	}
+
+	dst := &TwinSchema{
+		PrimitiveType: PrimitiveType(src.PrimitiveType),
+		EnumType:      convertTwinEnumSchemaFromV1(src.Enum),
+	}
+
+	if src.Object != nil {
+		dst.ComplexType = &TwinComplexType{Type: Object}
+		for _, objectField := range src.Object.Fields {
+			dst.ComplexType.Fields = append(dst.ComplexType.Fields, TwinComplexTypeFields{
+				Name:        objectField.Name,
+				DisplayName: objectField.DisplayName,
+				Description: objectField.Description,
+				Schema:      convertTwinSchemaFromV1(objectField.Schema),
+			})
+		}
+	}
+
+	if src.Array != nil {
+		dst.ArrayType = &TwinArraySchema{
+			ElementSchema: convertTwinSchemaFromV1(src.Array.ElementSchema),
+		}
+	}
+
+	if src.Map != nil {
+		dst.MapType = &TwinMapSchema{
+			MapKey: TwinMapKey{Name: src.Map.MapKey.Name},
+			MapValue: TwinMapValue{
+				Name:   src.Map.MapValue.Name,
+				Schema: convertTwinSchemaFromV1(src.Map.MapValue.Schema),
+			},
+		}
+	}
+
+	return dst
+}
+
+func convertTwinEnumSchemaToV1(src *TwinEnumSchema) *dtdv1.TwinEnumSchema {
+	if src == nil {
+		return nil
+	}
+
+	dst := &dtdv1.TwinEnumSchema{ValueSchema: dtdv1.PrimitiveType(src.ValueSchema)}
+	for _, enumValue := range src.EnumValues {
+		dst.EnumValues = append(dst.EnumValues, dtdv1.TwinEnumSchemaValues{
+			Name:        enumValue.Name,
+			DisplayName: enumValue.DisplayName,
+			EnumValue:   enumValue.EnumValue,
+		})
+	}
+	return dst
+}
+
+func convertTwinEnumSchemaFromV1(src *dtdv1.TwinEnumSchema) *TwinEnumSchema {
+	if src == nil {
+		return nil
+	}
+
+	dst := &TwinEnumSchema{ValueSchema: PrimitiveType(src.ValueSchema)}
+	for _, enumValue := range src.EnumValues {
+		dst.EnumValues = append(dst.EnumValues, TwinEnumSchemaValues{
+			Name:        enumValue.Name,
+			DisplayName: enumValue.DisplayName,
+			EnumValue:   enumValue.EnumValue,
+		})
+	}
+	return dst
+}
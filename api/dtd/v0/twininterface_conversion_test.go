@@ -0,0 +1,251 @@
+package v0
+
+import (
+	"testing"
+
+	dtdv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newConversionTwinInterfaceSpec() TwinInterfaceSpec {
+	minScale := 1
+	return TwinInterfaceSpec{
+		Id:               "dtmi:ngsi-ld:city:AirQualityObserved;1",
+		ModelId:          "dtmi:ngsi-ld:city:AirQualityObserved",
+		Version:          1,
+		DisplayName:      "Air Quality Observed",
+		Description:      "Air quality observed in a place",
+		ExtendsInterface: "ngsi-ld-city-observed",
+		Properties: []TwinProperty{
+			{Name: "name", Schema: &TwinSchema{PrimitiveType: String}, Writeable: true},
+			{
+				Name: "status",
+				Schema: &TwinSchema{
+					EnumType: &TwinEnumSchema{
+						ValueSchema: String,
+						EnumValues:  []TwinEnumSchemaValues{{Name: "active", DisplayName: "Active", EnumValue: "active"}},
+					},
+				},
+			},
+			{
+				Name: "location",
+				Schema: &TwinSchema{
+					ComplexType: &TwinComplexType{
+						Type: Object,
+						Fields: []TwinComplexTypeFields{
+							{Name: "latitude", Schema: &TwinSchema{PrimitiveType: Double}},
+							{Name: "longitude", Schema: &TwinSchema{PrimitiveType: Double}},
+						},
+					},
+				},
+			},
+		},
+		Telemetries: []TwinTelemetry{{Name: "co2", Schema: &TwinSchema{PrimitiveType: Double}, SemanticTypes: []string{"Density"}, Unit: "milligramPerCubicMetre"}},
+		Commands: []TwinCommand{
+			{
+				Name:     "reset",
+				Request:  CommandRequest{Name: "delay", Schema: &TwinSchema{PrimitiveType: Integer}},
+				Response: CommandResponse{Name: "result", Schema: &TwinSchema{PrimitiveType: Boolean}},
+			},
+		},
+		Relationships: []TwinRelationship{
+			{
+				Name:            "refPointOfInterest",
+				Interface:       "ngsi-ld-city-pointofinterest",
+				MaxMultiplicity: 1,
+				Properties:      []TwinProperty{{Name: "distance", Schema: &TwinSchema{PrimitiveType: Double}}},
+				Writeable:       true,
+				AggregateData:   true,
+			},
+		},
+		Components: []TwinComponent{{Name: "airQualitySensor", Interface: "ngsi-ld-city-airqualitysensor"}},
+		EventStore: TwinInterfaceEventStore{PersistRealEvent: true},
+		Service: &TwinInterfaceService{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "air-quality", Image: "ktwin/air-quality:0.1"}}},
+			},
+			AutoScaling: TwinInterfaceAutoScaling{MinScale: &minScale, Metric: CONCURRENCY},
+		},
+	}
+}
+
+func TestTwinInterface_ConvertTo(t *testing.T) {
+
+	spec := newConversionTwinInterfaceSpec()
+
+	twinInterface := &TwinInterface{
+		ObjectMeta: metav1.ObjectMeta{Name: "ngsi-ld-city-airqualityobserved", Namespace: "default"},
+		Spec:       spec,
+		Status: TwinInterfaceStatus{
+			Status:        TwinInterfacePhaseRunning,
+			ServiceURL:    "http://ngsi-ld-city-airqualityobserved.default.svc.cluster.local",
+			Bindings:      []string{"ngsi-ld-city-airqualityobserved-real"},
+			EffectiveSpec: &spec,
+		},
+	}
+
+	hub := &dtdv1.TwinInterface{}
+	assert.Nil(t, twinInterface.ConvertTo(hub))
+
+	assert.Equal(t, twinInterface.ObjectMeta, hub.ObjectMeta)
+	assert.Equal(t, dtdv1.TwinInterfacePhaseRunning, hub.Status.Status)
+	assert.Equal(t, twinInterface.Status.ServiceURL, hub.Status.ServiceURL)
+	assert.True(t, hub.Spec.Properties[0].Writable)
+	assert.True(t, hub.Spec.Relationships[0].Writable)
+	assert.Equal(t, &dtdv1.TwinObjectSchema{
+		Fields: []dtdv1.TwinObjectSchemaField{
+			{Name: "latitude", Schema: &dtdv1.TwinSchema{PrimitiveType: dtdv1.Double}},
+			{Name: "longitude", Schema: &dtdv1.TwinSchema{PrimitiveType: dtdv1.Double}},
+		},
+	}, hub.Spec.Properties[2].Schema.Object)
+	assert.Equal(t, hub.Spec, *hub.Status.EffectiveSpec)
+
+	// The deprecated ExtendsInterface is read back as the only TwinInterface of Extends
+	expectedSpec := newConversionTwinInterfaceSpec()
+	expectedSpec.Extends = []string{expectedSpec.ExtendsInterface}
+	expectedSpec.ExtendsInterface = ""
+
+	expected := twinInterface.DeepCopy()
+	expected.Spec = expectedSpec
+	expected.Status.EffectiveSpec = &expectedSpec
+
+	converted := &TwinInterface{}
+	assert.Nil(t, converted.ConvertFrom(hub))
+	assert.Equal(t, expected, converted)
+}
+
+func TestTwinInterface_ConvertTo_Extends(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		spec     TwinInterfaceSpec
+		expected []string
+	}{
+		{name: "Single TwinInterface in extends", spec: TwinInterfaceSpec{Extends: []string{"ngsi-ld-city-streetlight"}}, expected: []string{"ngsi-ld-city-streetlight"}},
+		{
+			name:     "Multiple TwinInterfaces in extends",
+			spec:     TwinInterfaceSpec{Extends: []string{"ngsi-ld-city-streetlight", "ngsi-ld-city-device"}},
+			expected: []string{"ngsi-ld-city-streetlight", "ngsi-ld-city-device"},
+		},
+		{name: "Deprecated extendsInterface", spec: TwinInterfaceSpec{ExtendsInterface: "ngsi-ld-city-streetlight"}, expected: []string{"ngsi-ld-city-streetlight"}},
+		{name: "No TwinInterface extended", spec: TwinInterfaceSpec{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twinInterface := &TwinInterface{ObjectMeta: metav1.ObjectMeta{Name: "ngsi-ld-city-smartstreetlight"}, Spec: tt.spec}
+
+			hub := &dtdv1.TwinInterface{}
+			assert.Nil(t, twinInterface.ConvertTo(hub))
+			assert.Equal(t, tt.expected, hub.Spec.Extends)
+
+			converted := &TwinInterface{}
+			assert.Nil(t, converted.ConvertFrom(hub))
+			assert.Equal(t, tt.expected, converted.Spec.Extends)
+			assert.Equal(t, "", converted.Spec.ExtendsInterface)
+		})
+	}
+}
+
+func TestTwinInterface_ConvertFrom(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		twinInterface *dtdv1.TwinInterface
+	}{
+		{
+			name: "TwinInterface representable in v0",
+			twinInterface: &dtdv1.TwinInterface{
+				ObjectMeta: metav1.ObjectMeta{Name: "ngsi-ld-city-airqualityobserved", Namespace: "default"},
+				Spec: dtdv1.TwinInterfaceSpec{
+					Id: "dtmi:ngsi-ld:city:AirQualityObserved;1",
+					Properties: []dtdv1.TwinProperty{
+						{
+							Name: "location",
+							Schema: &dtdv1.TwinSchema{
+								Object: &dtdv1.TwinObjectSchema{
+									Fields: []dtdv1.TwinObjectSchemaField{
+										{Name: "latitude", Schema: &dtdv1.TwinSchema{PrimitiveType: dtdv1.Double}},
+									},
+								},
+							},
+							Writable: true,
+						},
+					},
+					Commands: []dtdv1.TwinCommand{{Name: "reset", Request: dtdv1.TwinCommandPayload{Name: "delay"}}},
+				},
+				Status: dtdv1.TwinInterfaceStatus{
+					Status:  dtdv1.TwinInterfacePhaseFailed,
+					Cleanup: &dtdv1.TwinInterfaceCleanupStatus{RemainingQueues: 1},
+				},
+			},
+		},
+		{
+			name: "TwinInterface extending multiple TwinInterfaces",
+			twinInterface: &dtdv1.TwinInterface{
+				ObjectMeta: metav1.ObjectMeta{Name: "ngsi-ld-city-smartstreetlight"},
+				Spec: dtdv1.TwinInterfaceSpec{
+					Extends: []string{"ngsi-ld-city-streetlight", "ngsi-ld-city-device"},
+				},
+			},
+		},
+		{
+			name: "TwinInterface with nested schemas",
+			twinInterface: &dtdv1.TwinInterface{
+				ObjectMeta: metav1.ObjectMeta{Name: "ngsi-ld-city-airqualityobserved"},
+				Spec: dtdv1.TwinInterfaceSpec{
+					Telemetries: []dtdv1.TwinTelemetry{
+						{
+							Name: "readings",
+							Schema: &dtdv1.TwinSchema{
+								Array: &dtdv1.TwinArraySchema{ElementSchema: &dtdv1.TwinSchema{PrimitiveType: dtdv1.Double}},
+							},
+						},
+						{
+							Name: "sensors",
+							Schema: &dtdv1.TwinSchema{
+								Map: &dtdv1.TwinMapSchema{
+									MapKey:   dtdv1.TwinMapKey{Name: "sensorId"},
+									MapValue: dtdv1.TwinMapValue{Name: "status", Schema: &dtdv1.TwinSchema{PrimitiveType: dtdv1.Boolean}},
+								},
+							},
+						},
+						{
+							Name: "location",
+							Schema: &dtdv1.TwinSchema{
+								Object: &dtdv1.TwinObjectSchema{
+									Fields: []dtdv1.TwinObjectSchemaField{
+										{
+											Name:        "point",
+											Description: "Coordinates of the observation",
+											Schema: &dtdv1.TwinSchema{
+												Object: &dtdv1.TwinObjectSchema{
+													Fields: []dtdv1.TwinObjectSchemaField{{Name: "latitude", Schema: &dtdv1.TwinSchema{PrimitiveType: dtdv1.Double}}},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twinInterface := &TwinInterface{}
+			assert.Nil(t, twinInterface.ConvertFrom(tt.twinInterface))
+
+			hub := &dtdv1.TwinInterface{}
+			assert.Nil(t, twinInterface.ConvertTo(hub))
+
+			assert.Equal(t, tt.twinInterface, hub)
+		})
+	}
+}
@@ -24,22 +24,40 @@ import (
 type TwinInterfacePhase string
 
 const (
-	TwinInterfacePhasePending TwinInterfacePhase = "Pending"
-	TwinInterfacePhaseUnknown TwinInterfacePhase = "Unknown"
-	TwinInterfacePhaseRunning TwinInterfacePhase = "Running"
-	TwinInterfacePhaseFailed  TwinInterfacePhase = "Failed"
+	TwinInterfacePhasePending     TwinInterfacePhase = "Pending"
+	TwinInterfacePhaseUnknown     TwinInterfacePhase = "Unknown"
+	TwinInterfacePhaseRunning     TwinInterfacePhase = "Running"
+	TwinInterfacePhaseFailed      TwinInterfacePhase = "Failed"
+	TwinInterfacePhaseTerminating TwinInterfacePhase = "Terminating"
 )
 
+// DTDL v3 primitive schemas, including the geospatial schemas, whose values are GeoJSON geometries
+// +kubebuilder:validation:Enum=boolean;date;dateTime;double;duration;float;integer;long;string;time;point;multiPoint;lineString;multiLineString;polygon;multiPolygon
 type PrimitiveType string
 type ComplexType string
 type Multiplicity string
 type AutoScalerType string
 
 const (
-	Integer PrimitiveType = "integer"
-	String  PrimitiveType = "string"
-	Boolean PrimitiveType = "boolean"
-	Double  PrimitiveType = "double"
+	Integer  PrimitiveType = "integer"
+	String   PrimitiveType = "string"
+	Boolean  PrimitiveType = "boolean"
+	Double   PrimitiveType = "double"
+	Date     PrimitiveType = "date"
+	DateTime PrimitiveType = "dateTime"
+	Duration PrimitiveType = "duration"
+	Float    PrimitiveType = "float"
+	Long     PrimitiveType = "long"
+	Time     PrimitiveType = "time"
+)
+
+const (
+	Point           PrimitiveType = "point"
+	MultiPoint      PrimitiveType = "multiPoint"
+	LineString      PrimitiveType = "lineString"
+	MultiLineString PrimitiveType = "multiLineString"
+	Polygon         PrimitiveType = "polygon"
+	MultiPolygon    PrimitiveType = "multiPolygon"
 )
 
 const (
@@ -60,17 +78,44 @@ const (
 
 // TwinInterfaceSpec defines the desired state of TwinInterface
 type TwinInterfaceSpec struct {
-	Id               string                  `json:"id,omitempty"`
-	DisplayName      string                  `json:"displayName,omitempty"`
-	Description      string                  `json:"description,omitempty"`
-	Comment          string                  `json:"comment,omitempty"`
-	Properties       []TwinProperty          `json:"properties,omitempty"`
-	Commands         []TwinCommand           `json:"commands,omitempty"`
-	Relationships    []TwinRelationship      `json:"relationships,omitempty"`
-	Telemetries      []TwinTelemetry         `json:"telemetries,omitempty"`
-	ExtendsInterface string                  `json:"extendsInterface,omitempty"`
-	EventStore       TwinInterfaceEventStore `json:"eventStore,omitempty"`
-	Service          *TwinInterfaceService   `json:"service,omitempty"` // Must be a pointer because Containers[] field is required
+	Id string `json:"id,omitempty"`
+	// DTMI of the model without version, shared by all the versions of the TwinInterface, such as dtmi:city:Pole
+	ModelId string `json:"modelId,omitempty"`
+	// Version of the model, such as 2 for dtmi:city:Pole;2. The TwinInterfaces of the versions of a model coexist,
+	// each one with its own service, and TwinInstances choose the version that processes their events.
+	// +kubebuilder:validation:Minimum=1
+	Version       int                `json:"version,omitempty"`
+	DisplayName   string             `json:"displayName,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	Comment       string             `json:"comment,omitempty"`
+	Properties    []TwinProperty     `json:"properties,omitempty"`
+	Commands      []TwinCommand      `json:"commands,omitempty"`
+	Relationships []TwinRelationship `json:"relationships,omitempty"`
+	Telemetries   []TwinTelemetry    `json:"telemetries,omitempty"`
+	Components    []TwinComponent    `json:"components,omitempty"`
+	// Deprecated: use Extends. When informed, it is handled as the first TwinInterface of Extends.
+	ExtendsInterface string `json:"extendsInterface,omitempty"`
+	// TwinInterfaces extended by the TwinInterface. DTDL allows up to two parent interfaces.
+	// +kubebuilder:validation:MaxItems=2
+	Extends    []string                `json:"extends,omitempty"`
+	EventStore TwinInterfaceEventStore `json:"eventStore,omitempty"`
+	Service    *TwinInterfaceService   `json:"service,omitempty"` // Must be a pointer because Containers[] field is required
+}
+
+// Return the names of the TwinInterfaces extended by the TwinInterface, starting with the deprecated ExtendsInterface.
+// Repeated names are returned once.
+func (in *TwinInterfaceSpec) GetExtends() []string {
+	var extends []string
+	added := map[string]bool{}
+
+	for _, parentName := range append([]string{in.ExtendsInterface}, in.Extends...) {
+		if parentName != "" && !added[parentName] {
+			added[parentName] = true
+			extends = append(extends, parentName)
+		}
+	}
+
+	return extends
 }
 
 type TwinInterfaceService struct {
@@ -105,7 +150,10 @@ type TwinProperty struct {
 	DisplayName string      `json:"displayName,omitempty"`
 	Name        string      `json:"name,omitempty"`
 	Schema      *TwinSchema `json:"schema,omitempty"`
-	Writeable   bool        `json:"writable,omitempty"`
+	// DTDL semantic types of the property, such as Temperature, and the unit of its values, such as degreeCelsius
+	SemanticTypes []string `json:"semanticTypes,omitempty"`
+	Unit          string   `json:"unit,omitempty"`
+	Writeable     bool     `json:"writable,omitempty"`
 }
 
 type TwinCommand struct {
@@ -155,12 +203,31 @@ type TwinTelemetry struct {
 	DisplayName string      `json:"displayName,omitempty"`
 	Name        string      `json:"name,omitempty"`
 	Schema      *TwinSchema `json:"schema,omitempty"`
+	// DTDL semantic types of the telemetry, such as Temperature, and the unit of its values, such as degreeCelsius
+	SemanticTypes []string `json:"semanticTypes,omitempty"`
+	Unit          string   `json:"unit,omitempty"`
+}
+
+// Component embedding the contents of another TwinInterface. The properties, telemetries and commands of the
+// component TwinInterface are referred as <component name>.<name> in the TwinInstances and events.
+type TwinComponent struct {
+	Id          string `json:"id,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	Description string `json:"description,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Name        string `json:"name,omitempty"`
+	// The TwinInterface of the component
+	Interface string `json:"interface,omitempty"`
 }
 
+// Schema of a property, telemetry, relationship or command payload.
+// Complex type fields, array elements and map values are TwinSchemas themselves, so schemas can be nested at any depth.
 type TwinSchema struct {
 	PrimitiveType PrimitiveType    `json:"primitiveType,omitempty"`
 	ComplexType   *TwinComplexType `json:"complexType,omitempty"`
 	EnumType      *TwinEnumSchema  `json:"enumType,omitempty"`
+	ArrayType     *TwinArraySchema `json:"arrayType,omitempty"`
+	MapType       *TwinMapSchema   `json:"mapType,omitempty"`
 }
 
 type TwinComplexType struct {
@@ -169,12 +236,39 @@ type TwinComplexType struct {
 }
 
 type TwinComplexTypeFields struct {
-	Name   string                 `json:"name,omitempty"`
-	Schema *TwinComplexTypeSchema `json:"schema,omitempty"`
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Nested schemas are not expanded in the CRD, since OpenAPI schemas of CRDs can not be recursive
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Schema *TwinSchema `json:"schema,omitempty"`
+}
+
+type TwinArraySchema struct {
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ElementSchema *TwinSchema `json:"elementSchema,omitempty"`
 }
 
-type TwinComplexTypeSchema struct {
-	PrimitiveType PrimitiveType `json:"primitiveType,omitempty"`
+type TwinMapSchema struct {
+	MapKey   TwinMapKey   `json:"mapKey,omitempty"`
+	MapValue TwinMapValue `json:"mapValue,omitempty"`
+}
+
+// Map keys are always strings in DTDL
+type TwinMapKey struct {
+	Name string `json:"name,omitempty"`
+}
+
+type TwinMapValue struct {
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Schema *TwinSchema `json:"schema,omitempty"`
 }
 
 type TwinEnumSchema struct {
@@ -202,6 +296,32 @@ type TwinObjectSchemaFields struct {
 // TwinInterfaceStatus defines the observed state of TwinInterface
 type TwinInterfaceStatus struct {
 	Status TwinInterfacePhase `json:"status,omitempty"`
+	// Generation of the TwinInterface observed by the last reconciliation
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// URL of the KService that handles the TwinInterface events
+	ServiceURL string `json:"serviceURL,omitempty"`
+	// Names of the RabbitMQ Bindings generated for the TwinInterface
+	Bindings []string                    `json:"bindings,omitempty"`
+	Cleanup  *TwinInterfaceCleanupStatus `json:"cleanup,omitempty"`
+	// Spec resulting from merging the TwinInterface spec with the specs of all the TwinInterfaces it extends.
+	// It is only written by the controller, the schema is not repeated to keep the CRD size under the API server limits.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	EffectiveSpec *TwinInterfaceSpec `json:"effectiveSpec,omitempty"`
+}
+
+// TwinInterfaceCleanupStatus reports the resources still to be removed before a deleted TwinInterface is released
+type TwinInterfaceCleanupStatus struct {
+	RemainingBindings int  `json:"remainingBindings,omitempty"`
+	RemainingTriggers int  `json:"remainingTriggers,omitempty"`
+	RemainingServices int  `json:"remainingServices,omitempty"`
+	RemainingQueues   int  `json:"remainingQueues,omitempty"`
+	Completed         bool `json:"completed,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -216,6 +336,15 @@ type TwinInterface struct {
 	Status TwinInterfaceStatus `json:"status,omitempty"`
 }
 
+// Return the spec including the inherited definitions, falling back to the TwinInterface own spec
+// while the inheritance chain was not resolved by the controller
+func (t *TwinInterface) GetEffectiveSpec() *TwinInterfaceSpec {
+	if t.Status.EffectiveSpec != nil {
+		return t.Status.EffectiveSpec
+	}
+	return &t.Spec
+}
+
 //+kubebuilder:object:root=true
 
 // TwinInterfaceList contains a list of TwinInterface
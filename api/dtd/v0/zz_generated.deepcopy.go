@@ -6,6 +6,7 @@
 package v0
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -49,6 +50,26 @@ func (in *CommandResponse) DeepCopy() *CommandResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TwinArraySchema) DeepCopyInto(out *TwinArraySchema) {
+	*out = *in
+	if in.ElementSchema != nil {
+		in, out := &in.ElementSchema, &out.ElementSchema
+		*out = new(TwinSchema)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinArraySchema.
+func (in *TwinArraySchema) DeepCopy() *TwinArraySchema {
+	if in == nil {
+		return nil
+	}
+	out := new(TwinArraySchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TwinCommand) DeepCopyInto(out *TwinCommand) {
 	*out = *in
@@ -93,8 +114,8 @@ func (in *TwinComplexTypeFields) DeepCopyInto(out *TwinComplexTypeFields) {
 	*out = *in
 	if in.Schema != nil {
 		in, out := &in.Schema, &out.Schema
-		*out = new(TwinComplexTypeSchema)
-		**out = **in
+		*out = new(TwinSchema)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -109,16 +130,16 @@ func (in *TwinComplexTypeFields) DeepCopy() *TwinComplexTypeFields {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TwinComplexTypeSchema) DeepCopyInto(out *TwinComplexTypeSchema) {
+func (in *TwinComponent) DeepCopyInto(out *TwinComponent) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinComplexTypeSchema.
-func (in *TwinComplexTypeSchema) DeepCopy() *TwinComplexTypeSchema {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinComponent.
+func (in *TwinComponent) DeepCopy() *TwinComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(TwinComplexTypeSchema)
+	out := new(TwinComponent)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -164,7 +185,7 @@ func (in *TwinInstance) DeepCopyInto(out *TwinInstance) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinInstance.
@@ -380,6 +401,18 @@ func (in *TwinInstanceSpec) DeepCopy() *TwinInstanceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TwinInstanceStatus) DeepCopyInto(out *TwinInstanceStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinInstanceStatus.
@@ -413,7 +446,7 @@ func (in *TwinInterface) DeepCopyInto(out *TwinInterface) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinInterface.
@@ -474,6 +507,21 @@ func (in *TwinInterfaceAutoScaling) DeepCopy() *TwinInterfaceAutoScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TwinInterfaceCleanupStatus) DeepCopyInto(out *TwinInterfaceCleanupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinInterfaceCleanupStatus.
+func (in *TwinInterfaceCleanupStatus) DeepCopy() *TwinInterfaceCleanupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TwinInterfaceCleanupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TwinInterfaceEventStore) DeepCopyInto(out *TwinInterfaceEventStore) {
 	*out = *in
@@ -584,6 +632,16 @@ func (in *TwinInterfaceSpec) DeepCopyInto(out *TwinInterfaceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]TwinComponent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Extends != nil {
+		in, out := &in.Extends, &out.Extends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	out.EventStore = in.EventStore
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
@@ -605,6 +663,28 @@ func (in *TwinInterfaceSpec) DeepCopy() *TwinInterfaceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TwinInterfaceStatus) DeepCopyInto(out *TwinInterfaceStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(TwinInterfaceCleanupStatus)
+		**out = **in
+	}
+	if in.EffectiveSpec != nil {
+		in, out := &in.EffectiveSpec, &out.EffectiveSpec
+		*out = new(TwinInterfaceSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinInterfaceStatus.
@@ -617,6 +697,58 @@ func (in *TwinInterfaceStatus) DeepCopy() *TwinInterfaceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TwinMapKey) DeepCopyInto(out *TwinMapKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinMapKey.
+func (in *TwinMapKey) DeepCopy() *TwinMapKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TwinMapKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TwinMapSchema) DeepCopyInto(out *TwinMapSchema) {
+	*out = *in
+	out.MapKey = in.MapKey
+	in.MapValue.DeepCopyInto(&out.MapValue)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinMapSchema.
+func (in *TwinMapSchema) DeepCopy() *TwinMapSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(TwinMapSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TwinMapValue) DeepCopyInto(out *TwinMapValue) {
+	*out = *in
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(TwinSchema)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinMapValue.
+func (in *TwinMapValue) DeepCopy() *TwinMapValue {
+	if in == nil {
+		return nil
+	}
+	out := new(TwinMapValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TwinObjectSchema) DeepCopyInto(out *TwinObjectSchema) {
 	*out = *in
@@ -660,6 +792,11 @@ func (in *TwinProperty) DeepCopyInto(out *TwinProperty) {
 		*out = new(TwinSchema)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SemanticTypes != nil {
+		in, out := &in.SemanticTypes, &out.SemanticTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinProperty.
@@ -712,6 +849,16 @@ func (in *TwinSchema) DeepCopyInto(out *TwinSchema) {
 		*out = new(TwinEnumSchema)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ArrayType != nil {
+		in, out := &in.ArrayType, &out.ArrayType
+		*out = new(TwinArraySchema)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MapType != nil {
+		in, out := &in.MapType, &out.MapType
+		*out = new(TwinMapSchema)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinSchema.
@@ -732,6 +879,11 @@ func (in *TwinTelemetry) DeepCopyInto(out *TwinTelemetry) {
 		*out = new(TwinSchema)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SemanticTypes != nil {
+		in, out := &in.SemanticTypes, &out.SemanticTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TwinTelemetry.
@@ -0,0 +1,133 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type TwinInstancePhase string
+
+const (
+	TwinInstancePhasePending TwinInstancePhase = "Pending"
+	TwinInstancePhaseUnknown TwinInstancePhase = "Unknown"
+	TwinInstancePhaseRunning TwinInstancePhase = "Running"
+	TwinInstancePhaseFailed  TwinInstancePhase = "Failed"
+)
+
+// TwinInstanceSpec defines the desired state of TwinInstance
+type TwinInstanceSpec struct {
+	Interface string `json:"interface,omitempty"`
+	// Version of the model of the TwinInterface whose service processes the TwinInstance events: a version number
+	// or latest, for the highest version. When empty, the TwinInterface informed in interface is used.
+	// +kubebuilder:validation:Pattern=`^(latest|[1-9][0-9]*)$`
+	InterfaceVersion string                     `json:"interfaceVersion,omitempty"`
+	Data             *TwinInstanceDataSpec      `json:"data,omitempty"`
+	Relationships    []TwinInstanceRelationship `json:"relationships,omitempty"`
+}
+
+type TwinInstanceDataSpec struct {
+	Properties []TwinInstancePropertyData `json:"properties,omitempty"`
+}
+
+type TwinInstancePropertyData struct {
+	Id    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+type TwinInstanceTelemetryData struct {
+	Id    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+type TwinInstanceRelationship struct {
+	// The TwinInstance Relationship name
+	Name string `json:"name"`
+	// The Target TwinInterface of the Relationship
+	Interface string `json:"interface"`
+	// The Target TwinInstance of the Relationship
+	Instance string `json:"instance"`
+}
+
+type TwinInstanceEndpointSettings struct {
+	HttpEndpoint *TwinInstanceHttpEndpointSettings `json:"httpEndpoint,omitempty"`
+	MqttEndpoint *TwinInstanceMqttEndpointSettings `json:"mqttEndpoint,omitempty"`
+	AmqpEndpoint *TwinInstanceAmqpEndpointSettings `json:"amqpEndpoint,omitempty"`
+}
+
+type TwinInstanceHttpEndpointSettings struct {
+	Url string `json:"url,omitempty"`
+}
+
+type TwinInstanceMqttEndpointSettings struct {
+	Url             string `json:"url,omitempty"`
+	PublisherTopic  string `json:"publisherTopic,omitempty"`
+	SubscriberTopic string `json:"subscriberTopic,omitempty"`
+}
+
+type TwinInstanceAmqpEndpointSettings struct {
+	Url             string `json:"url,omitempty"`
+	PublisherTopic  string `json:"publisherTopic,omitempty"`
+	SubscriberTopic string `json:"subscriberTopic,omitempty"`
+}
+
+// TwinInstanceStatus defines the observed state of TwinInstance
+type TwinInstanceStatus struct {
+	Status TwinInstancePhase `json:"status,omitempty"`
+	// Generation of the TwinInstance observed by the last reconciliation
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Names of the RabbitMQ Bindings generated for the TwinInstance
+	Bindings []string `json:"bindings,omitempty"`
+	// TwinInterface resolved from interface and interfaceVersion, whose service processes the TwinInstance events
+	ResolvedInterface string `json:"resolvedInterface,omitempty"`
+	// Endpoints where the TwinInstance events are published and consumed
+	EndpointSettings *TwinInstanceEndpointSettings `json:"endpointSettings,omitempty"`
+	// Last telemetry values reported by the TwinInstance
+	Telemetries []TwinInstanceTelemetryData `json:"telemetries,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// TwinInstance is the Schema for the twininstances API
+type TwinInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TwinInstanceSpec   `json:"spec,omitempty"`
+	Status TwinInstanceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TwinInstanceList contains a list of TwinInstance
+type TwinInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TwinInstance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TwinInstance{}, &TwinInstanceList{})
+}
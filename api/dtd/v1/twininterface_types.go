@@ -0,0 +1,304 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type TwinInterfacePhase string
+
+const (
+	TwinInterfacePhasePending     TwinInterfacePhase = "Pending"
+	TwinInterfacePhaseUnknown     TwinInterfacePhase = "Unknown"
+	TwinInterfacePhaseRunning     TwinInterfacePhase = "Running"
+	TwinInterfacePhaseFailed      TwinInterfacePhase = "Failed"
+	TwinInterfacePhaseTerminating TwinInterfacePhase = "Terminating"
+)
+
+// DTDL v3 primitive schemas, including the geospatial schemas, whose values are GeoJSON geometries
+// +kubebuilder:validation:Enum=boolean;date;dateTime;double;duration;float;integer;long;string;time;point;multiPoint;lineString;multiLineString;polygon;multiPolygon
+type PrimitiveType string
+type AutoScalerType string
+
+const (
+	Integer  PrimitiveType = "integer"
+	String   PrimitiveType = "string"
+	Boolean  PrimitiveType = "boolean"
+	Double   PrimitiveType = "double"
+	Date     PrimitiveType = "date"
+	DateTime PrimitiveType = "dateTime"
+	Duration PrimitiveType = "duration"
+	Float    PrimitiveType = "float"
+	Long     PrimitiveType = "long"
+	Time     PrimitiveType = "time"
+)
+
+const (
+	Point           PrimitiveType = "point"
+	MultiPoint      PrimitiveType = "multiPoint"
+	LineString      PrimitiveType = "lineString"
+	MultiLineString PrimitiveType = "multiLineString"
+	Polygon         PrimitiveType = "polygon"
+	MultiPolygon    PrimitiveType = "multiPolygon"
+)
+
+const (
+	CONCURRENCY AutoScalerType = "concurrency"
+	RPS         AutoScalerType = "rps"
+	CPU         AutoScalerType = "cpu"
+	MEMORY      AutoScalerType = "memory"
+)
+
+// TwinInterfaceSpec defines the desired state of TwinInterface
+type TwinInterfaceSpec struct {
+	Id string `json:"id,omitempty"`
+	// DTMI of the model without version, shared by all the versions of the TwinInterface, such as dtmi:city:Pole
+	ModelId string `json:"modelId,omitempty"`
+	// Version of the model, such as 2 for dtmi:city:Pole;2. The TwinInterfaces of the versions of a model coexist,
+	// each one with its own service, and TwinInstances choose the version that processes their events.
+	// +kubebuilder:validation:Minimum=1
+	Version       int                `json:"version,omitempty"`
+	DisplayName   string             `json:"displayName,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	Comment       string             `json:"comment,omitempty"`
+	Properties    []TwinProperty     `json:"properties,omitempty"`
+	Commands      []TwinCommand      `json:"commands,omitempty"`
+	Relationships []TwinRelationship `json:"relationships,omitempty"`
+	Telemetries   []TwinTelemetry    `json:"telemetries,omitempty"`
+	Components    []TwinComponent    `json:"components,omitempty"`
+	// TwinInterfaces extended by the TwinInterface. DTDL allows up to two parent interfaces.
+	// +kubebuilder:validation:MaxItems=2
+	Extends    []string                `json:"extends,omitempty"`
+	EventStore TwinInterfaceEventStore `json:"eventStore,omitempty"`
+	Service    *TwinInterfaceService   `json:"service,omitempty"` // Must be a pointer because Containers[] field is required
+}
+
+type TwinInterfaceService struct {
+	Template    corev1.PodTemplateSpec   `json:"template,omitempty"`
+	AutoScaling TwinInterfaceAutoScaling `json:"autoScaling,omitempty"`
+}
+
+// KNative Pod Auto Scaler Settings
+type TwinInterfaceAutoScaling struct {
+	MinScale                    *int `json:"minScale,omitempty"`
+	MaxScale                    *int `json:"maxScale,omitempty"`
+	Target                      *int `json:"target,omitempty"`
+	TargetUtilizationPercentage *int `json:"targetUtilizationPercentage,omitempty"`
+	Parallelism                 *int `json:"parallelism,omitempty"`
+	// KNative Metric values (default, if not informed: concurrency)
+	// concurrency: the number of simultaneous requests that can be processed by each replica of an application at any given time
+	// rps: requests per seconds
+	// cpu: cpu usage
+	// memory: memory usage
+	Metric AutoScalerType `json:"metric,omitempty"`
+}
+
+type TwinInterfaceEventStore struct {
+	PersistRealEvent    bool `json:"persistRealEvent,omitempty"`
+	PersistVirtualEvent bool `json:"persistVirtualEvent,omitempty"`
+}
+
+type TwinProperty struct {
+	Id          string      `json:"id,omitempty"`
+	Comment     string      `json:"comment,omitempty"`
+	Description string      `json:"description,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Schema      *TwinSchema `json:"schema,omitempty"`
+	// DTDL semantic types of the property, such as Temperature, and the unit of its values, such as degreeCelsius
+	SemanticTypes []string `json:"semanticTypes,omitempty"`
+	Unit          string   `json:"unit,omitempty"`
+	Writable      bool     `json:"writable,omitempty"`
+}
+
+type TwinCommand struct {
+	Id          string             `json:"id,omitempty"`
+	Comment     string             `json:"comment,omitempty"`
+	Description string             `json:"description,omitempty"`
+	DisplayName string             `json:"displayName,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	Request     TwinCommandPayload `json:"request,omitempty"`
+	Response    TwinCommandPayload `json:"response,omitempty"`
+}
+
+// Request or response of a TwinCommand
+type TwinCommandPayload struct {
+	Name        string      `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Schema      *TwinSchema `json:"schema,omitempty"`
+}
+
+type TwinRelationship struct {
+	Id              string         `json:"id,omitempty"`
+	Comment         string         `json:"comment,omitempty"`
+	Description     string         `json:"description,omitempty"`
+	DisplayName     string         `json:"displayName,omitempty"`
+	MaxMultiplicity int            `json:"maxMultiplicity,omitempty"`
+	MinMultiplicity int            `json:"minMultiplicity,omitempty"`
+	Name            string         `json:"name,omitempty"`
+	Properties      []TwinProperty `json:"properties,omitempty"`
+	Interface       string         `json:"interface,omitempty"`
+	Schema          *TwinSchema    `json:"schema,omitempty"`
+	Writable        bool           `json:"writable,omitempty"`
+	// Indicate if the data must be aggregated in the relationship parent
+	AggregateData bool `json:"aggregateData,omitempty"`
+}
+
+type TwinTelemetry struct {
+	Id          string      `json:"id,omitempty"`
+	Comment     string      `json:"comment,omitempty"`
+	Description string      `json:"description,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Schema      *TwinSchema `json:"schema,omitempty"`
+	// DTDL semantic types of the telemetry, such as Temperature, and the unit of its values, such as degreeCelsius
+	SemanticTypes []string `json:"semanticTypes,omitempty"`
+	Unit          string   `json:"unit,omitempty"`
+}
+
+// Component embedding the contents of another TwinInterface. The properties, telemetries and commands of the
+// component TwinInterface are referred as <component name>.<name> in the TwinInstances and events.
+type TwinComponent struct {
+	Id          string `json:"id,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	Description string `json:"description,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Name        string `json:"name,omitempty"`
+	// The TwinInterface of the component
+	Interface string `json:"interface,omitempty"`
+}
+
+// Schema of a property, telemetry, relationship or command payload. Exactly one of the fields is expected to be set.
+// Object fields, array elements and map values are TwinSchemas themselves, so schemas can be nested at any depth.
+type TwinSchema struct {
+	PrimitiveType PrimitiveType     `json:"primitiveType,omitempty"`
+	Enum          *TwinEnumSchema   `json:"enum,omitempty"`
+	Object        *TwinObjectSchema `json:"object,omitempty"`
+	Array         *TwinArraySchema  `json:"array,omitempty"`
+	Map           *TwinMapSchema    `json:"map,omitempty"`
+}
+
+type TwinEnumSchema struct {
+	ValueSchema PrimitiveType          `json:"valueSchema,omitempty"`
+	EnumValues  []TwinEnumSchemaValues `json:"enumValues,omitempty"`
+}
+
+type TwinEnumSchemaValues struct {
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	EnumValue   string `json:"enumValue,omitempty"`
+}
+
+type TwinObjectSchema struct {
+	Fields []TwinObjectSchemaField `json:"fields,omitempty"`
+}
+
+type TwinObjectSchemaField struct {
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Nested schemas are not expanded in the CRD, since OpenAPI schemas of CRDs can not be recursive
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Schema *TwinSchema `json:"schema,omitempty"`
+}
+
+type TwinArraySchema struct {
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ElementSchema *TwinSchema `json:"elementSchema,omitempty"`
+}
+
+type TwinMapSchema struct {
+	MapKey   TwinMapKey   `json:"mapKey,omitempty"`
+	MapValue TwinMapValue `json:"mapValue,omitempty"`
+}
+
+// Map keys are always strings in DTDL
+type TwinMapKey struct {
+	Name string `json:"name,omitempty"`
+}
+
+type TwinMapValue struct {
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Schema *TwinSchema `json:"schema,omitempty"`
+}
+
+// TwinInterfaceStatus defines the observed state of TwinInterface
+type TwinInterfaceStatus struct {
+	Status TwinInterfacePhase `json:"status,omitempty"`
+	// Generation of the TwinInterface observed by the last reconciliation
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// URL of the KService that handles the TwinInterface events
+	ServiceURL string `json:"serviceURL,omitempty"`
+	// Names of the RabbitMQ Bindings generated for the TwinInterface
+	Bindings []string                    `json:"bindings,omitempty"`
+	Cleanup  *TwinInterfaceCleanupStatus `json:"cleanup,omitempty"`
+	// Spec resulting from merging the TwinInterface spec with the specs of all the TwinInterfaces it extends.
+	// It is only written by the controller, the schema is not repeated to keep the CRD size under the API server limits.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	EffectiveSpec *TwinInterfaceSpec `json:"effectiveSpec,omitempty"`
+}
+
+// TwinInterfaceCleanupStatus reports the resources still to be removed before a deleted TwinInterface is released
+type TwinInterfaceCleanupStatus struct {
+	RemainingBindings int  `json:"remainingBindings,omitempty"`
+	RemainingTriggers int  `json:"remainingTriggers,omitempty"`
+	RemainingServices int  `json:"remainingServices,omitempty"`
+	RemainingQueues   int  `json:"remainingQueues,omitempty"`
+	Completed         bool `json:"completed,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// TwinInterface is the Schema for the twininterfaces API
+type TwinInterface struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TwinInterfaceSpec   `json:"spec,omitempty"`
+	Status TwinInterfaceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TwinInterfaceList contains a list of TwinInterface
+type TwinInterfaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TwinInterface `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TwinInterface{}, &TwinInterfaceList{})
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	pkg "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/pkg"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	OUTPUT_LAYOUT_FILES  = "files"
+	OUTPUT_LAYOUT_BUNDLE = "bundle"
+)
+
+// The options of the commands that generate the TwinInterfaces and TwinInstances of the DTDL files
+type GenerateOptions struct {
+	InputFolderPath      string
+	Namespace            string
+	InstanceGraphFile    string
+	InventoryFile        string
+	InventoryMappingFile string
+	ConfigFile           string
+}
+
+func addGenerateFlags(flagSet *flag.FlagSet) *GenerateOptions {
+	options := &GenerateOptions{}
+	flagSet.StringVar(&options.InputFolderPath, "input-folder-path", "", "the input folder path to DTDL files")
+	flagSet.StringVar(&options.Namespace, "namespace", pkg.DEFAULT_NAMESPACE, "the namespace of the generated TwinInterfaces and TwinInstances")
+	flagSet.StringVar(&options.InstanceGraphFile, "instance-graph-file", "", "the instance graph file path used to generate instances file. when not informed, all interfaces are created with one instance")
+	flagSet.StringVar(&options.InventoryFile, "inventory-file", "", "the CSV or JSON inventory file used to generate one instance for each row, instead of the interfaces and example instances")
+	flagSet.StringVar(&options.InventoryMappingFile, "inventory-mapping-file", "", "the JSON file that maps the inventory columns to interfaces, properties and relationships")
+	flagSet.StringVar(&options.ConfigFile, "config-file", "", "the YAML or JSON CLI config file with the service and event store settings of the interfaces, matched by DTMI patterns. when not informed, all interfaces have the default service")
+	return options
+}
+
+func (o *GenerateOptions) Validate() error {
+	if o.InputFolderPath == "" {
+		return errors.New("Inform the DTDL input folder path")
+	}
+
+	if o.Namespace == "" {
+		return errors.New("Inform the namespace")
+	}
+
+	if (o.InventoryFile == "") != (o.InventoryMappingFile == "") {
+		return errors.New("Inform both the inventory file and the inventory mapping file")
+	}
+
+	return nil
+}
+
+// Parse the flags of a command, returning false when they are not valid or the help is requested
+func parseFlags(flagSet *flag.FlagSet, args []string, validate func() error) bool {
+	if err := flagSet.Parse(args); err == flag.ErrHelp {
+		os.Exit(0)
+	} else if err != nil {
+		return false
+	}
+
+	if flagSet.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n", strings.Join(flagSet.Args(), " "))
+		flagSet.Usage()
+		return false
+	}
+
+	if err := validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flagSet.Usage()
+		return false
+	}
+
+	return true
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet("ktwin "+name, flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Usage of ktwin %s:\n", name)
+		flagSet.PrintDefaults()
+	}
+	return flagSet
+}
+
+func runValidate(args []string) int {
+	flagSet := newFlagSet("validate")
+	options := addGenerateFlags(flagSet)
+
+	if !parseFlags(flagSet, args, options.Validate) {
+		return 2
+	}
+
+	if _, ok := generateResources(options, ""); !ok {
+		return 1
+	}
+
+	fmt.Fprintln(progress, "No errors found")
+	return 0
+}
+
+func runGenerate(args []string) int {
+	flagSet := newFlagSet("generate")
+	options := addGenerateFlags(flagSet)
+	outputFolderPath := flagSet.String("output-folder-path", "", "the output folder path to the generated files")
+	outputFormat := flagSet.String("output-format", string(pkg.OutputFormatYAML), "the format of the generated files: yaml or json")
+	outputLayout := flagSet.String("output-layout", OUTPUT_LAYOUT_FILES, "the layout of the output folder: files, with the TwinInterface and TwinInstances files of each DTDL file in the folders of the input folder, or bundle, with a single file of all the resources and a kustomization.yaml")
+	stdout := flagSet.Bool("stdout", false, "write all the generated resources to the standard output, in topological order, instead of the output folder")
+
+	var format pkg.OutputFormat
+
+	validate := func() error {
+		var err error
+		if format, err = pkg.ParseOutputFormat(*outputFormat); err != nil {
+			return err
+		}
+		if *outputLayout != OUTPUT_LAYOUT_FILES && *outputLayout != OUTPUT_LAYOUT_BUNDLE {
+			return fmt.Errorf("Output layout must be %s or %s: %q", OUTPUT_LAYOUT_FILES, OUTPUT_LAYOUT_BUNDLE, *outputLayout)
+		}
+		if (*outputFolderPath == "") == !*stdout {
+			return errors.New("Inform either the output folder path or the standard output")
+		}
+		return options.Validate()
+	}
+
+	if !parseFlags(flagSet, args, validate) {
+		return 2
+	}
+
+	if *stdout {
+		// The resources are the output of the command
+		progress = os.Stderr
+	}
+
+	generatedFiles, ok := generateResources(options, *outputFolderPath)
+	if !ok {
+		return 1
+	}
+
+	var err error
+
+	switch {
+	case *stdout:
+		var content []byte
+		if content, err = pkg.EncodeManifests(getGeneratedObjects(generatedFiles), format); err == nil {
+			_, err = os.Stdout.Write(content)
+		}
+	case *outputLayout == OUTPUT_LAYOUT_BUNDLE:
+		fmt.Fprintln(progress, "Generating output files...")
+		err = writeBundleFiles(*outputFolderPath, generatedFiles, format, options.Namespace)
+	default:
+		fmt.Fprintln(progress, "Generating output files...")
+		for _, generatedFile := range generatedFiles {
+			if err = writeGeneratedFile(generatedFile, format); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func runGraph(args []string) int {
+	flagSet := newFlagSet("graph")
+	inputFolderPath := flagSet.String("input-folder-path", "", "the input folder path to DTDL files")
+	instanceGraphFile := flagSet.String("instance-graph-file", "", "the instance graph file path whose TwinInstance graph is also printed")
+
+	validate := func() error {
+		if *inputFolderPath == "" {
+			return errors.New("Inform the DTDL input folder path")
+		}
+		return nil
+	}
+
+	if !parseFlags(flagSet, args, validate) {
+		return 2
+	}
+
+	dtdlGraph, _, ok := loadTwinInterfaceGraph(*inputFolderPath, "", pkg.NewResourceBuilder())
+	if !ok {
+		return 1
+	}
+
+	dtdlGraph.PrintGraph()
+
+	if *instanceGraphFile == "" {
+		return 0
+	}
+
+	instanceGraph, instanceGraphErrors := generateInstanceGraph(*instanceGraphFile, dtdlGraph)
+
+	if len(instanceGraphErrors) > 0 {
+		printInstanceGraphErrorsReport(*instanceGraphFile, instanceGraphErrors)
+		return 1
+	}
+
+	instanceGraph.PrintGraph()
+	return 0
+}
+
+// Print the differences between the generated resources and the cluster, or a folder of previously generated YAML
+// files. As kubectl diff, it exits with 1 when there are differences and with 2 or more on errors.
+func runDiff(args []string) int {
+	flagSet := newFlagSet("diff")
+	options := addGenerateFlags(flagSet)
+	manifestsFolderPath := flagSet.String("manifests-folder-path", "", "the folder path to previously generated YAML files to compare with, instead of the cluster of the kubeconfig")
+	config.RegisterFlags(flagSet)
+
+	if !parseFlags(flagSet, args, options.Validate) {
+		return 2
+	}
+
+	// The differences are the output of the command
+	progress = os.Stderr
+
+	generatedFiles, ok := generateResources(options, "")
+	if !ok {
+		return 2
+	}
+
+	var differ pkg.Differ
+
+	if *manifestsFolderPath != "" {
+		currentObjects, err := loadFolderManifests(*manifestsFolderPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		differ = pkg.NewManifestDiffer(currentObjects)
+	} else {
+		clusterClient, err := pkg.NewClusterClient()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		differ = pkg.NewClusterDiffer(clusterClient)
+	}
+
+	objectDiffs, err := differ.Diff(context.Background(), getGeneratedObjects(generatedFiles))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	for _, objectDiff := range objectDiffs {
+		if objectDiff.Action != pkg.DiffActionUnchanged {
+			fmt.Println(objectDiff)
+		}
+	}
+
+	if pkg.HasDifferences(objectDiffs) {
+		return 1
+	}
+
+	return 0
+}
+
+func runApply(args []string) int {
+	flagSet := newFlagSet("apply")
+	options := addGenerateFlags(flagSet)
+	config.RegisterFlags(flagSet)
+
+	if !parseFlags(flagSet, args, options.Validate) {
+		return 2
+	}
+
+	generatedFiles, ok := generateResources(options, "")
+	if !ok {
+		return 1
+	}
+
+	clusterClient, err := pkg.NewClusterClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	applyResults, err := pkg.NewApplier(clusterClient).Apply(context.Background(), getGeneratedObjects(generatedFiles))
+
+	for _, applyResult := range applyResults {
+		result := string(applyResult.Result)
+		if applyResult.Result == controllerutil.OperationResultNone {
+			result = "unchanged"
+		}
+		fmt.Printf("%s/%s %s\n", strings.ToLower(applyResult.Kind), applyResult.Name, result)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func runExport(args []string) int {
+	flagSet := newFlagSet("export")
+	inputFolderPath := flagSet.String("input-folder-path", "", "the input folder path to TwinInterface YAML files")
+	outputFolderPath := flagSet.String("output-folder-path", "", "the output folder path to the DTDL files")
+	fromCluster := flagSet.Bool("from-cluster", false, "export the TwinInterfaces of the cluster of the kubeconfig instead of the input folder")
+	namespace := flagSet.String("namespace", pkg.DEFAULT_NAMESPACE, "the namespace of the TwinInterfaces exported from the cluster")
+	config.RegisterFlags(flagSet)
+
+	validate := func() error {
+		if *outputFolderPath == "" || (*inputFolderPath == "" && !*fromCluster) {
+			return errors.New("Inform the TwinInterfaces input folder path, or export from the cluster, and the DTDL output folder path")
+		}
+		return nil
+	}
+
+	if !parseFlags(flagSet, args, validate) {
+		return 2
+	}
+
+	exportErrors := exportTwinInterfaces(*inputFolderPath, *fromCluster, *namespace, *outputFolderPath)
+
+	if len(exportErrors) > 0 {
+		printExportErrorsReport(exportErrors)
+		return 1
+	}
+
+	return 0
+}
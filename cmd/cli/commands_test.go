@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunValidate(t *testing.T) {
+	progress = io.Discard
+
+	tests := []struct {
+		name            string
+		inputFolderPath string
+		expected        int
+	}{
+		{
+			name:            "Valid DTDL files",
+			inputFolderPath: testSmartCitiesFolderPath,
+			expected:        0,
+		},
+		{
+			name:            "Inheritance cycle",
+			inputFolderPath: "testdata/cycle",
+			expected:        1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, runValidate([]string{"-input-folder-path", tt.inputFolderPath}))
+		})
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"reflect"
+	"strings"
 )
 
 var (
@@ -16,6 +16,7 @@ var (
 	ContentTelemetryType    = "Telemetry"
 
 	ErrContentUnmarshalInvalidType = errors.New("Invalid content @type")
+	ErrInvalidComponentSchema      = errors.New("Component schema must be the DTMI of an interface")
 )
 
 func ErrContentUnmarshalTypeNotSupported(typeValue string) error {
@@ -38,141 +39,222 @@ func (c *Content) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	objectMap := jsonObject.(map[string]interface{})
+	content, errs := parseContent(jsonObject, "", nil)
 
-	objectType := objectMap["@type"].(string)
-
-	switch objectMap["@type"] {
-	case ContentPropertyType:
-		c.Property = c.newProperty(objectMap)
-		return nil
-	case ContentRelationshipType:
-		c.Relationship = c.newRelationship(objectMap)
-		return nil
-	case ContentCommandType:
-		c.Command = c.newCommand(objectMap)
-		return nil
-	case ContentComponentType:
-		c.Component = c.newComponent(objectMap)
-		return nil
-	case ContentTelemetryType:
-		c.Telemetry = c.newTelemetry(objectMap)
-		return nil
-	default:
-		return ErrContentUnmarshalTypeNotSupported(objectType)
+	if len(errs) > 0 {
+		return errs[0]
 	}
+
+	*c = content
+	return nil
 }
 
-func (c Content) MarshalYAML() (interface{}, error) {
-	if !reflect.DeepEqual(c.Command, Command{}) {
-		return c.Command, nil
-	}
+// Parse a content of an interface. Errors are returned as *ParseError, located by the JSON pointer of the
+// content element appended to the informed one. The rules of the DTDL version are applied when the
+// document context is informed.
+func parseContent(jsonObject interface{}, pointer string, context *DocumentContext) (Content, []error) {
+	content := Content{}
 
-	if !reflect.DeepEqual(c.Relationship, Relationship{}) {
-		return c.Relationship, nil
-	}
+	objectMap, ok := jsonObject.(map[string]interface{})
 
-	if !reflect.DeepEqual(c.Property, Property{}) {
-		return c.Property, nil
+	if !ok {
+		return content, []error{newParseError(pointer, ErrContentUnmarshalInvalidType)}
 	}
 
-	if !reflect.DeepEqual(c.Telemetry, Telemetry{}) {
-		return c.Telemetry, nil
-	}
+	objectTypes, err := getContentTypes(objectMap["@type"])
 
-	if !reflect.DeepEqual(c.Component, Component{}) {
-		return c.Component, nil
+	if err != nil {
+		return content, []error{newParseError(joinPointer(pointer, "@type"), err)}
 	}
 
-	return nil, errors.New("Not possible to marshal Yaml")
-}
+	contentType := getContentType(objectTypes)
 
-func (s *Content) newProperty(data interface{}) *Property {
-	property := Property{}
+	if contentType == "" {
+		return content, []error{newParseError(joinPointer(pointer, "@type"), ErrContentUnmarshalTypeNotSupported(strings.Join(objectTypes, ", ")))}
+	}
 
-	dataByte, err := json.Marshal(data)
+	// Schemas and DTMIs are processed first, so their errors are located in the content
+	errs := processContentSchemas(contentType, objectMap, pointer, context)
+	errs = append(errs, processContentDTMIs(contentType, objectMap, pointer, context)...)
 
-	if err != nil {
-		log.Fatal("Error in marshaling property ", err)
+	if context != nil {
+		if err := context.validateQuantitativeTypes(objectTypes, objectMap); err != nil {
+			errs = append(errs, newParseError(joinPointer(pointer, "@type"), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return content, errs
 	}
 
-	err = json.Unmarshal(dataByte, &property)
+	switch contentType {
+	case ContentPropertyType:
+		content.Property = &Property{}
+		err = unmarshalContent(objectMap, content.Property)
+	case ContentRelationshipType:
+		content.Relationship = &Relationship{}
+		err = unmarshalContent(objectMap, content.Relationship)
+	case ContentCommandType:
+		content.Command = &Command{}
+		err = unmarshalContent(objectMap, content.Command)
+	case ContentComponentType:
+		content.Component = &Component{}
+		err = unmarshalContent(objectMap, content.Component)
+	case ContentTelemetryType:
+		content.Telemetry = &Telemetry{}
+		err = unmarshalContent(objectMap, content.Telemetry)
+	}
 
 	if err != nil {
-		log.Fatal("Error in unmarshaling property ", err)
+		return Content{}, []error{newUnmarshalParseError(pointer, err)}
 	}
 
-	return &property
+	return content, nil
 }
 
-func (s *Content) newRelationship(data interface{}) *Relationship {
-	relationship := Relationship{}
-
-	dataByte, err := json.Marshal(data)
-
-	if err != nil {
-		log.Fatal("Error in marshaling relationship ", err)
+// Process the schemas declared in a content, collecting all their errors
+func processContentSchemas(contentType string, objectMap map[string]interface{}, pointer string, context *DocumentContext) []error {
+	var errs []error
+	schema := &Schema{}
+	scope := schemaScope{context: context}
+
+	processSchema := func(schemaObject interface{}, schemaPointer string) {
+		if schemaObject != nil {
+			_, schemaErrs := schema.processSchema(schemaObject, schemaPointer, scope)
+			errs = append(errs, schemaErrs...)
+		}
 	}
 
-	err = json.Unmarshal(dataByte, &relationship)
-
-	if err != nil {
-		log.Fatal("Error in unmarshaling relationship ", err)
+	switch contentType {
+	case ContentPropertyType:
+		scope.inProperty = true
+		processSchema(objectMap["schema"], joinPointer(pointer, "schema"))
+	case ContentTelemetryType:
+		processSchema(objectMap["schema"], joinPointer(pointer, "schema"))
+	case ContentCommandType:
+		for _, payload := range []string{"request", "response"} {
+			if payloadMap, ok := objectMap[payload].(map[string]interface{}); ok {
+				processSchema(payloadMap["schema"], joinPointer(pointer, payload, "schema"))
+			}
+		}
+	case ContentRelationshipType:
+		processSchema(objectMap["schema"], joinPointer(pointer, "schema"))
+
+		// The properties of a relationship follow the rules of the properties of an interface
+		scope.inProperty = true
+		properties, _ := objectMap["properties"].([]interface{})
+		for i, property := range properties {
+			if propertyMap, ok := property.(map[string]interface{}); ok {
+				processSchema(propertyMap["schema"], joinPointer(pointer, "properties", i, "schema"))
+			}
+		}
+	case ContentComponentType:
+		// The schema of a component is the DTMI of an interface
+		if _, ok := objectMap["schema"].(string); !ok {
+			errs = append(errs, newParseError(joinPointer(pointer, "schema"), ErrInvalidComponentSchema))
+		}
 	}
 
-	return &relationship
+	return errs
 }
 
-func (s *Content) newCommand(data interface{}) *Command {
-	command := Command{}
+// Validate the DTMIs of a content: its optional @id and the interfaces referenced by relationships and components
+func processContentDTMIs(contentType string, objectMap map[string]interface{}, pointer string, context *DocumentContext) []error {
+	var errs []error
 
-	dataByte, err := json.Marshal(data)
+	if id, ok := objectMap["@id"]; ok {
+		errs = append(errs, context.validateDTMI(id, joinPointer(pointer, "@id"), false)...)
+	}
 
-	if err != nil {
-		log.Fatal("Error in marshaling command", err)
+	switch contentType {
+	case ContentRelationshipType:
+		if target, ok := objectMap["target"]; ok {
+			errs = append(errs, context.validateDTMI(target, joinPointer(pointer, "target"), false)...)
+		}
+	case ContentComponentType:
+		// A schema that is not a string is already reported by processContentSchemas
+		if schema, ok := objectMap["schema"].(string); ok {
+			errs = append(errs, context.validateDTMI(schema, joinPointer(pointer, "schema"), false)...)
+		}
 	}
 
-	err = json.Unmarshal(dataByte, &command)
+	return errs
+}
+
+func unmarshalContent(objectMap map[string]interface{}, content interface{}) error {
+	dataByte, err := json.Marshal(objectMap)
 
 	if err != nil {
-		log.Fatal("Error in unmarshaling command", err)
+		return err
 	}
 
-	return &command
+	return json.Unmarshal(dataByte, content)
 }
 
-func (s *Content) newTelemetry(data interface{}) *Telemetry {
-	telemetry := Telemetry{}
+// The @type of a content is either a string or an array with the content type and its semantic types
+func getContentTypes(typeValue interface{}) ([]string, error) {
+	switch value := typeValue.(type) {
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		var contentTypes []string
+		for _, item := range value {
+			itemValue, ok := item.(string)
+			if !ok {
+				return nil, ErrContentUnmarshalInvalidType
+			}
+			contentTypes = append(contentTypes, itemValue)
+		}
+		return contentTypes, nil
+	}
 
-	dataByte, err := json.Marshal(data)
+	return nil, ErrContentUnmarshalInvalidType
+}
 
-	if err != nil {
-		log.Fatal("Error in marshaling telemetry ", err)
+func getContentType(contentTypes []string) string {
+	for _, contentType := range contentTypes {
+		switch contentType {
+		case ContentPropertyType, ContentRelationshipType, ContentCommandType, ContentComponentType, ContentTelemetryType:
+			return contentType
+		}
 	}
+	return ""
+}
 
-	err = json.Unmarshal(dataByte, &telemetry)
+// Return the semantic types listed in the @type of a content, such as Temperature in ["Telemetry", "Temperature"]
+func GetSemanticTypes(contentTypes []string) []string {
+	var semanticTypes []string
+	contentType := getContentType(contentTypes)
 
-	if err != nil {
-		log.Fatal("Error in unmarshaling telemetry ", err)
+	for _, semanticType := range contentTypes {
+		if semanticType != contentType {
+			semanticTypes = append(semanticTypes, semanticType)
+		}
 	}
 
-	return &telemetry
+	return semanticTypes
 }
 
-func (s *Content) newComponent(data interface{}) *Component {
-	component := Component{}
+func (c Content) MarshalYAML() (interface{}, error) {
+	if !reflect.DeepEqual(c.Command, Command{}) {
+		return c.Command, nil
+	}
 
-	dataByte, err := json.Marshal(data)
+	if !reflect.DeepEqual(c.Relationship, Relationship{}) {
+		return c.Relationship, nil
+	}
 
-	if err != nil {
-		log.Fatal("Error in marshaling component ", err)
+	if !reflect.DeepEqual(c.Property, Property{}) {
+		return c.Property, nil
 	}
 
-	err = json.Unmarshal(dataByte, &component)
+	if !reflect.DeepEqual(c.Telemetry, Telemetry{}) {
+		return c.Telemetry, nil
+	}
 
-	if err != nil {
-		log.Fatal("Error in unmarshaling component ", err)
+	if !reflect.DeepEqual(c.Component, Component{}) {
+		return c.Component, nil
 	}
 
-	return &component
+	return nil, errors.New("Not possible to marshal Yaml")
 }
@@ -0,0 +1,154 @@
+package dtdl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// https://github.com/Azure/opendigitaltwins-dtdl/blob/master/DTDL/v3/DTDL.v3.md#context
+
+type Version int
+
+const (
+	VERSION_2 Version = 2
+	VERSION_3 Version = 3
+
+	DTDL_V2_CONTEXT = "dtmi:dtdl:context;2"
+	DTDL_V3_CONTEXT = "dtmi:dtdl:context;3"
+
+	// From DTDL v3, semantic types and units are declared by the QuantitativeTypes extension
+	QUANTITATIVE_TYPES_EXTENSION = "dtmi:dtdl:extension:quantitativeTypes"
+)
+
+var (
+	ErrMissingContext                = errors.New("@context must declare the DTDL context " + DTDL_V2_CONTEXT + " or " + DTDL_V3_CONTEXT)
+	ErrMultipleContexts              = errors.New("@context must declare only one DTDL context")
+	ErrSchemaNotSupported            = errors.New("Schema requires DTDL v3")
+	ErrArraySchemaInProperty         = errors.New("Array schemas in properties require DTDL v3")
+	ErrSchemaTooDeep                 = errors.New("Complex schema exceeds the maximum depth")
+	ErrTooManyContents               = errors.New("Interface exceeds the maximum number of contents")
+	ErrQuantitativeTypesNotSupported = errors.New("Semantic types and units require the " + QUANTITATIVE_TYPES_EXTENSION + " extension in @context")
+)
+
+// Limits of the DTDL language, which are relaxed by DTDL v3
+type Limits struct {
+	MaxContents            int
+	MaxSchemaDepth         int
+	MaxInterfaceDTMILength int
+	MaxDTMILength          int
+}
+
+var versionLimits = map[Version]Limits{
+	VERSION_2: {MaxContents: 300, MaxSchemaDepth: 5, MaxInterfaceDTMILength: 128, MaxDTMILength: 2048},
+	VERSION_3: {MaxContents: 100000, MaxSchemaDepth: 8, MaxInterfaceDTMILength: 128, MaxDTMILength: 2048},
+}
+
+// Primitive schemas added by DTDL v3
+var v3PrimitiveSchemas = []string{
+	"byte", "bytes", "decimal", "short", "uuid", "unsignedByte", "unsignedShort", "unsignedInteger", "unsignedLong",
+}
+
+var v2PrimitiveSchemas = []string{
+	"boolean", "date", "dateTime", "double", "duration", "float", "integer", "long", "string", "time",
+	"point", "multiPoint", "lineString", "multiLineString", "polygon", "multiPolygon",
+}
+
+// The @context of a DTDL document, with the DTDL version and the extensions used by the document
+type DocumentContext struct {
+	Version    Version
+	Extensions []string
+}
+
+// Parse the @context of a DTDL document, which is either a DTDL context or an array with
+// the DTDL context and the contexts of the extensions, such as dtmi:dtdl:extension:quantitativeTypes;1
+func ParseContext(contexts []string) (*DocumentContext, error) {
+	var documentContext *DocumentContext
+
+	for _, context := range contexts {
+		var version Version
+
+		switch context {
+		case DTDL_V2_CONTEXT:
+			version = VERSION_2
+		case DTDL_V3_CONTEXT:
+			version = VERSION_3
+		default:
+			continue
+		}
+
+		if documentContext != nil {
+			return nil, ErrMultipleContexts
+		}
+		documentContext = &DocumentContext{Version: version}
+	}
+
+	if documentContext == nil {
+		return nil, ErrMissingContext
+	}
+
+	for _, context := range contexts {
+		if context != DTDL_V2_CONTEXT && context != DTDL_V3_CONTEXT {
+			documentContext.Extensions = append(documentContext.Extensions, context)
+		}
+	}
+
+	return documentContext, nil
+}
+
+func (c *DocumentContext) Limits() Limits {
+	return versionLimits[c.Version]
+}
+
+// Check if the document uses an extension, informed by its DTMI without version
+func (c *DocumentContext) HasExtension(extension string) bool {
+	for _, documentExtension := range c.Extensions {
+		if strings.HasPrefix(documentExtension, extension+";") {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate a schema referenced by name, which is either a primitive schema of the DTDL version
+// or the DTMI of a schema declared in the interface
+func (c *DocumentContext) validatePrimitiveSchema(schema string) error {
+	if containsString(v2PrimitiveSchemas, schema) {
+		return nil
+	}
+
+	if containsString(v3PrimitiveSchemas, schema) {
+		if c.Version < VERSION_3 {
+			return fmt.Errorf("%w: %q", ErrSchemaNotSupported, schema)
+		}
+		return nil
+	}
+
+	if _, err := ParseDTMI(schema); err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrInvalidSchemaType, schema)
+}
+
+// Validate the semantic types and unit of a content, which DTDL v3 only allows with the QuantitativeTypes extension
+func (c *DocumentContext) validateQuantitativeTypes(contentTypes []string, objectMap map[string]interface{}) error {
+	if c.Version < VERSION_3 || c.HasExtension(QUANTITATIVE_TYPES_EXTENSION) {
+		return nil
+	}
+
+	if len(GetSemanticTypes(contentTypes)) > 0 || objectMap["unit"] != nil {
+		return ErrQuantitativeTypesNotSupported
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
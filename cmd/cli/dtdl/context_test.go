@@ -0,0 +1,92 @@
+package dtdl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContext(t *testing.T) {
+	tests := []struct {
+		name          string
+		contexts      []string
+		expected      *DocumentContext
+		expectedError error
+	}{
+		{name: "DTDL v2", contexts: []string{DTDL_V2_CONTEXT}, expected: &DocumentContext{Version: VERSION_2}},
+		{name: "DTDL v3", contexts: []string{DTDL_V3_CONTEXT}, expected: &DocumentContext{Version: VERSION_3}},
+		{
+			name:     "DTDL v3 with extensions",
+			contexts: []string{"dtmi:dtdl:extension:historization;1", DTDL_V3_CONTEXT, "dtmi:dtdl:extension:quantitativeTypes;1"},
+			expected: &DocumentContext{Version: VERSION_3, Extensions: []string{"dtmi:dtdl:extension:historization;1", "dtmi:dtdl:extension:quantitativeTypes;1"}},
+		},
+		{name: "No context", expectedError: ErrMissingContext},
+		{name: "Unknown DTDL version", contexts: []string{"dtmi:dtdl:context;4"}, expectedError: ErrMissingContext},
+		{name: "Multiple DTDL versions", contexts: []string{DTDL_V2_CONTEXT, DTDL_V3_CONTEXT}, expectedError: ErrMultipleContexts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documentContext, err := ParseContext(tt.contexts)
+
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+				assert.Nil(t, documentContext)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, documentContext)
+		})
+	}
+}
+
+func TestDocumentContext_Limits(t *testing.T) {
+	v2Limits := (&DocumentContext{Version: VERSION_2}).Limits()
+	v3Limits := (&DocumentContext{Version: VERSION_3}).Limits()
+
+	assert.Equal(t, 300, v2Limits.MaxContents)
+	assert.Equal(t, 5, v2Limits.MaxSchemaDepth)
+	assert.Equal(t, 100000, v3Limits.MaxContents)
+	assert.Equal(t, 8, v3Limits.MaxSchemaDepth)
+	assert.Equal(t, v2Limits.MaxInterfaceDTMILength, v3Limits.MaxInterfaceDTMILength)
+	assert.Equal(t, v2Limits.MaxDTMILength, v3Limits.MaxDTMILength)
+}
+
+func TestDocumentContext_HasExtension(t *testing.T) {
+	documentContext := &DocumentContext{Version: VERSION_3, Extensions: []string{"dtmi:dtdl:extension:quantitativeTypes;1"}}
+
+	assert.True(t, documentContext.HasExtension(QUANTITATIVE_TYPES_EXTENSION))
+	assert.False(t, documentContext.HasExtension("dtmi:dtdl:extension:quantitative"))
+	assert.False(t, documentContext.HasExtension("dtmi:dtdl:extension:historization"))
+}
+
+func TestDocumentContext_ValidatePrimitiveSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		version       Version
+		schema        string
+		expectedError error
+	}{
+		{name: "DTDL v2 schema", version: VERSION_2, schema: "double"},
+		{name: "Geospatial schema", version: VERSION_2, schema: "point"},
+		{name: "Schema declared in the interface", version: VERSION_2, schema: "dtmi:city:PoleStatus;1"},
+		{name: "DTDL v3 schema in DTDL v2", version: VERSION_2, schema: "unsignedShort", expectedError: ErrSchemaNotSupported},
+		{name: "DTDL v3 schema in DTDL v3", version: VERSION_3, schema: "unsignedShort"},
+		{name: "Unknown schema", version: VERSION_3, schema: "number", expectedError: ErrInvalidSchemaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&DocumentContext{Version: tt.version}).validatePrimitiveSchema(tt.schema)
+
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
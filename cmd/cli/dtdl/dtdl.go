@@ -30,9 +30,10 @@ type TwinDefinitionContent struct {
 }
 
 // DTDL Types
+// The @type of contents may list semantic types along with the content type, such as ["Telemetry", "Temperature"]
 
 type Interface struct {
-	Context     IRI               `json:"@context"`
+	Context     types.StringArray `json:"@context"` // The DTDL context, optionally followed by the contexts of extensions
 	Type        IRI               `json:"@type"`
 	Id          DTMI              `json:"@id"`
 	Comment     string            `json:"comment,omitempty"`
@@ -44,36 +45,38 @@ type Interface struct {
 }
 
 type Telemetry struct {
-	Type        IRI             `json:"@type"`
-	Id          DTMI            `json:"@id,omitempty"`
-	Comment     string          `json:"comment,omitempty"`
-	Description LocalizedString `json:"description,omitempty"`
-	DisplayName LocalizedString `json:"displayName,omitempty"`
-	Name        string          `json:"name"`
-	Schema      Schema          `json:"schema"`
+	Type        types.StringArray `json:"@type"`
+	Id          DTMI              `json:"@id,omitempty"`
+	Comment     string            `json:"comment,omitempty"`
+	Description LocalizedString   `json:"description,omitempty"`
+	DisplayName LocalizedString   `json:"displayName,omitempty"`
+	Name        string            `json:"name"`
+	Schema      Schema            `json:"schema"`
+	Unit        string            `json:"unit,omitempty"`
 }
 
 type Property struct {
-	Type        IRI             `json:"@type"`
-	Id          DTMI            `json:"@id,omitempty"`
-	Comment     string          `json:"comment,omitempty"`
-	Description LocalizedString `json:"description,omitempty"`
-	DisplayName LocalizedString `json:"displayName,omitempty"`
-	Name        string          `json:"name"`
-	Schema      Schema          `json:"schema"`
-	Writeable   bool            `json:"writable"`
+	Type        types.StringArray `json:"@type"`
+	Id          DTMI              `json:"@id,omitempty"`
+	Comment     string            `json:"comment,omitempty"`
+	Description LocalizedString   `json:"description,omitempty"`
+	DisplayName LocalizedString   `json:"displayName,omitempty"`
+	Name        string            `json:"name"`
+	Schema      Schema            `json:"schema"`
+	Unit        string            `json:"unit,omitempty"`
+	Writeable   bool              `json:"writable"`
 }
 
 type Command struct {
-	Type        IRI             `json:"@type"`
-	Id          DTMI            `json:"@id,omitempty"`
-	Comment     string          `json:"comment,omitempty"`
-	CommandType string          `json:"commandType,omitempty"` // Deprecated
-	Description LocalizedString `json:"description,omitempty"`
-	DisplayName LocalizedString `json:"displayName,omitempty"`
-	Name        string          `json:"name"`
-	Request     CommandRequest  `json:"request"`
-	Response    CommandResponse `json:"response"`
+	Type        types.StringArray `json:"@type"`
+	Id          DTMI              `json:"@id,omitempty"`
+	Comment     string            `json:"comment,omitempty"`
+	CommandType string            `json:"commandType,omitempty"` // Deprecated
+	Description LocalizedString   `json:"description,omitempty"`
+	DisplayName LocalizedString   `json:"displayName,omitempty"`
+	Name        string            `json:"name"`
+	Request     CommandRequest    `json:"request"`
+	Response    CommandResponse   `json:"response"`
 }
 
 type CommandRequest struct {
@@ -97,26 +100,26 @@ type CommandResponse struct {
 }
 
 type Relationship struct {
-	Type            IRI             `json:"@type"`
-	Id              DTMI            `json:"@id,omitempty"`
-	Comment         string          `json:"comment,omitempty"`
-	Description     LocalizedString `json:"description,omitempty"`
-	DisplayName     LocalizedString `json:"displayName,omitempty"`
-	MaxMultiplicity int             `json:"maxMultiplicity,omitempty"`
-	MinMultiplicity int             `json:"minMultiplicity,omitempty"`
-	Name            string          `json:"name"`
-	Properties      []Property      `json:"properties"`
-	Target          DTMI            `json:"target"`
-	Schema          Schema          `json:"schema"`
-	Writeable       bool            `json:"writeable"`
+	Type            types.StringArray `json:"@type"`
+	Id              DTMI              `json:"@id,omitempty"`
+	Comment         string            `json:"comment,omitempty"`
+	Description     LocalizedString   `json:"description,omitempty"`
+	DisplayName     LocalizedString   `json:"displayName,omitempty"`
+	MaxMultiplicity int               `json:"maxMultiplicity,omitempty"`
+	MinMultiplicity int               `json:"minMultiplicity,omitempty"`
+	Name            string            `json:"name"`
+	Properties      []Property        `json:"properties"`
+	Target          DTMI              `json:"target"`
+	Schema          Schema            `json:"schema"`
+	Writeable       bool              `json:"writable"`
 }
 
 type Component struct {
-	Type        IRI             `json:"@type"`
-	Id          DTMI            `json:"@id,omitempty"`
-	Comment     string          `json:"comment,omitempty"`
-	Description LocalizedString `json:"description,omitempty"`
-	DisplayName LocalizedString `json:"displayName,omitempty"`
-	Name        string          `json:"name"`
-	Schema      Schema          `json:"schema"`
+	Type        types.StringArray `json:"@type"`
+	Id          DTMI              `json:"@id,omitempty"`
+	Comment     string            `json:"comment,omitempty"`
+	Description LocalizedString   `json:"description,omitempty"`
+	DisplayName LocalizedString   `json:"displayName,omitempty"`
+	Name        string            `json:"name"`
+	Schema      Schema            `json:"schema"`
 }
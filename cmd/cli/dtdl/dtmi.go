@@ -0,0 +1,109 @@
+package dtdl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// https://github.com/Azure/digital-twin-model-identifier
+
+// A DTMI is formed by a scheme, colon separated path segments and an optional version after a semicolon, such as
+// dtmi:com:example:Thermostat;1. Segments start with a letter, have letters, digits and underscores, and do not end
+// with an underscore. The version has a major number and, from DTDL v3, an optional minor number, such as ;1.2.
+var dtmiRegexp = regexp.MustCompile(`^dtmi:([A-Za-z](?:[A-Za-z0-9_]*[A-Za-z0-9])?(?::[A-Za-z](?:[A-Za-z0-9_]*[A-Za-z0-9])?)*)(?:;([1-9][0-9]{0,8})(?:\.([1-9][0-9]{0,5}))?)?$`)
+
+var (
+	ErrInvalidDTMI           = errors.New("Invalid DTMI")
+	ErrDTMIVersionRequired   = errors.New("Interface DTMI must have a version")
+	ErrDTMIMinorVersion      = errors.New("DTMI minor versions require DTDL v3")
+	ErrDTMITooLong           = errors.New("DTMI exceeds the maximum length")
+	ErrDuplicateInterface    = errors.New("Interface is declared more than once")
+	ErrResourceNameCollision = errors.New("Interface resource name collides with another interface")
+	ErrInvalidResourceName   = errors.New("Interface DTMI does not map to a valid resource name")
+	ErrModelVersionCollision = errors.New("Interface has the same major version as another interface")
+	ErrDTMINotString         = errors.New("DTMI must be a string")
+)
+
+type ParsedDTMI struct {
+	Value        string
+	Segments     []string
+	MajorVersion int // Zero when the DTMI has no version
+	MinorVersion int // Zero when the DTMI has no minor version
+}
+
+// Parse a DTMI, splitting its path segments and version
+func ParseDTMI(value string) (ParsedDTMI, error) {
+	matches := dtmiRegexp.FindStringSubmatch(value)
+
+	if matches == nil {
+		return ParsedDTMI{}, fmt.Errorf("%w: %q", ErrInvalidDTMI, value)
+	}
+
+	parsedDTMI := ParsedDTMI{
+		Value:    value,
+		Segments: strings.Split(matches[1], ":"),
+	}
+
+	if matches[2] != "" {
+		parsedDTMI.MajorVersion, _ = strconv.Atoi(matches[2])
+	}
+
+	if matches[3] != "" {
+		parsedDTMI.MinorVersion, _ = strconv.Atoi(matches[3])
+	}
+
+	return parsedDTMI, nil
+}
+
+func (d ParsedDTMI) HasVersion() bool {
+	return d.MajorVersion > 0
+}
+
+// Return the DTMI without its version, which identifies all the versions of a model
+func (d ParsedDTMI) Unversioned() string {
+	return "dtmi:" + strings.Join(d.Segments, ":")
+}
+
+// Validate the DTMI of an element according to the DTDL version of the document. The DTMI of an interface
+// must have a version and is shorter than the ones of other elements.
+func (c *DocumentContext) validateDTMI(value interface{}, pointer string, isInterface bool) []error {
+	dtmi, ok := value.(string)
+
+	if !ok {
+		return []error{newParseError(pointer, ErrDTMINotString)}
+	}
+
+	parsedDTMI, err := ParseDTMI(dtmi)
+
+	if err != nil {
+		return []error{newParseError(pointer, err)}
+	}
+
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	limits := c.Limits()
+
+	if parsedDTMI.MinorVersion > 0 && c.Version < VERSION_3 {
+		errs = append(errs, newParseError(pointer, ErrDTMIMinorVersion))
+	}
+
+	if isInterface && !parsedDTMI.HasVersion() {
+		errs = append(errs, newParseError(pointer, ErrDTMIVersionRequired))
+	}
+
+	if isInterface && len(dtmi) > limits.MaxInterfaceDTMILength {
+		errs = append(errs, newParseError(pointer, fmt.Errorf("%w of %d characters", ErrDTMITooLong, limits.MaxInterfaceDTMILength)))
+	}
+
+	if !isInterface && len(dtmi) > limits.MaxDTMILength {
+		errs = append(errs, newParseError(pointer, fmt.Errorf("%w of %d characters", ErrDTMITooLong, limits.MaxDTMILength)))
+	}
+
+	return errs
+}
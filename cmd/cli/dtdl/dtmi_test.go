@@ -0,0 +1,107 @@
+package dtdl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDTMI(t *testing.T) {
+	tests := []struct {
+		name            string
+		value           string
+		expected        ParsedDTMI
+		expectedVersion bool
+		expectedError   error
+	}{
+		{
+			name:            "DTMI with major version",
+			value:           "dtmi:com:example:Thermostat;1",
+			expected:        ParsedDTMI{Value: "dtmi:com:example:Thermostat;1", Segments: []string{"com", "example", "Thermostat"}, MajorVersion: 1},
+			expectedVersion: true,
+		},
+		{
+			name:            "DTMI with minor version",
+			value:           "dtmi:digitaltwins:ngsi_ld:city:Streetlight;12.3",
+			expected:        ParsedDTMI{Value: "dtmi:digitaltwins:ngsi_ld:city:Streetlight;12.3", Segments: []string{"digitaltwins", "ngsi_ld", "city", "Streetlight"}, MajorVersion: 12, MinorVersion: 3},
+			expectedVersion: true,
+		},
+		{
+			name:     "DTMI without version",
+			value:    "dtmi:city:Pole",
+			expected: ParsedDTMI{Value: "dtmi:city:Pole", Segments: []string{"city", "Pole"}},
+		},
+		{name: "Missing scheme", value: "city:Pole;1", expectedError: ErrInvalidDTMI},
+		{name: "Segment starting with a digit", value: "dtmi:city:1Pole;1", expectedError: ErrInvalidDTMI},
+		{name: "Segment ending with an underscore", value: "dtmi:city:Pole_;1", expectedError: ErrInvalidDTMI},
+		{name: "Invalid character", value: "dtmi:city:Pole-1;1", expectedError: ErrInvalidDTMI},
+		{name: "Version zero", value: "dtmi:city:Pole;0", expectedError: ErrInvalidDTMI},
+		{name: "Empty minor version", value: "dtmi:city:Pole;1.", expectedError: ErrInvalidDTMI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsedDTMI, err := ParseDTMI(tt.value)
+
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, parsedDTMI)
+			assert.Equal(t, tt.expectedVersion, parsedDTMI.HasVersion())
+		})
+	}
+}
+
+func TestParsedDTMI_Unversioned(t *testing.T) {
+	parsedDTMI, err := ParseDTMI("dtmi:digitaltwins:city:Pole;2.1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "dtmi:digitaltwins:city:Pole", parsedDTMI.Unversioned())
+}
+
+func TestDocumentContext_ValidateDTMI(t *testing.T) {
+	tests := []struct {
+		name        string
+		context     *DocumentContext
+		value       interface{}
+		isInterface bool
+		expected    []error
+	}{
+		{name: "Interface DTMI", context: &DocumentContext{Version: VERSION_2}, value: "dtmi:city:Pole;1", isInterface: true},
+		{name: "Element DTMI without version", context: &DocumentContext{Version: VERSION_2}, value: "dtmi:city:Pole:height"},
+		{name: "Minor version in DTDL v3", context: &DocumentContext{Version: VERSION_3}, value: "dtmi:city:Pole;1.2", isInterface: true},
+		{name: "Invalid context only checks the syntax", value: "dtmi:city:Pole;1.2", isInterface: true},
+		{name: "Not a string", context: &DocumentContext{Version: VERSION_3}, value: 1.0, expected: []error{ErrDTMINotString}},
+		{name: "Invalid DTMI", context: &DocumentContext{Version: VERSION_3}, value: "dtmi:city:Pole;", expected: []error{ErrInvalidDTMI}},
+		{name: "Minor version in DTDL v2", context: &DocumentContext{Version: VERSION_2}, value: "dtmi:city:Pole;1.2", isInterface: true, expected: []error{ErrDTMIMinorVersion}},
+		{name: "Interface DTMI without version", context: &DocumentContext{Version: VERSION_3}, value: "dtmi:city:Pole", isInterface: true, expected: []error{ErrDTMIVersionRequired}},
+		{
+			name:        "Interface DTMI too long",
+			context:     &DocumentContext{Version: VERSION_3},
+			value:       "dtmi:city:" + strings.Repeat("a", 120) + ";1",
+			isInterface: true,
+			expected:    []error{ErrDTMITooLong},
+		},
+		{name: "Element DTMI longer than interface DTMIs", context: &DocumentContext{Version: VERSION_3}, value: "dtmi:city:" + strings.Repeat("a", 120)},
+		{name: "Element DTMI too long", context: &DocumentContext{Version: VERSION_3}, value: "dtmi:city:" + strings.Repeat("a", 2040), expected: []error{ErrDTMITooLong}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.context.validateDTMI(tt.value, "/@id", tt.isInterface)
+			assert.Equal(t, len(tt.expected), len(errs), errs)
+
+			for index, err := range errs {
+				if index < len(tt.expected) {
+					assert.True(t, errors.Is(err, tt.expected[index]), err.Error())
+					assert.Equal(t, "/@id", err.(*ParseError).Pointer)
+				}
+			}
+		})
+	}
+}
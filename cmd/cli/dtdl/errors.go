@@ -0,0 +1,83 @@
+package dtdl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidInterface    = errors.New("DTDL file must contain an interface object")
+	ErrInheritanceCycle    = errors.New("Interface extends itself")
+	ErrInheritanceConflict = errors.New("Interface inherits contents with the same name")
+)
+
+// Error found while parsing a DTDL interface, located by the path of the file, the @id of the interface and
+// the JSON pointer (RFC 6901) of the offending element in the file
+type ParseError struct {
+	FilePath    string
+	InterfaceId string
+	Pointer     string
+	Err         error
+}
+
+func newParseError(pointer string, err error) *ParseError {
+	return &ParseError{Pointer: pointer, Err: err}
+}
+
+func (e *ParseError) Error() string {
+	var location []string
+
+	if e.FilePath != "" {
+		location = append(location, e.FilePath)
+	}
+	if e.InterfaceId != "" {
+		location = append(location, "["+e.InterfaceId+"]")
+	}
+	if e.Pointer != "" {
+		location = append(location, e.Pointer)
+	}
+
+	if len(location) == 0 {
+		return e.Err.Error()
+	}
+
+	return strings.Join(location, " ") + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Append reference tokens to a JSON pointer, escaping them as defined by RFC 6901
+func joinPointer(pointer string, tokens ...interface{}) string {
+	for _, token := range tokens {
+		switch value := token.(type) {
+		case int:
+			pointer += "/" + strconv.Itoa(value)
+		default:
+			escapedToken := strings.ReplaceAll(fmt.Sprint(value), "~", "~0")
+			pointer += "/" + strings.ReplaceAll(escapedToken, "/", "~1")
+		}
+	}
+	return pointer
+}
+
+// Locate the errors returned by json.Unmarshal on the element at the informed JSON pointer
+func newUnmarshalParseError(pointer string, err error) *ParseError {
+	var parseError *ParseError
+	if errors.As(err, &parseError) {
+		return newParseError(pointer+parseError.Pointer, parseError.Err)
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		for _, token := range strings.Split(typeError.Field, ".") {
+			pointer = joinPointer(pointer, token)
+		}
+	}
+
+	return newParseError(pointer, err)
+}
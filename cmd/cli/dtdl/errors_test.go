@@ -0,0 +1,57 @@
+package dtdl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		parseError *ParseError
+		expected   string
+	}{
+		{
+			name:       "Located error",
+			parseError: &ParseError{FilePath: "city/pole.json", InterfaceId: "dtmi:city:Pole;1", Pointer: "/contents/0/schema", Err: ErrInvalidSchemaType},
+			expected:   "city/pole.json [dtmi:city:Pole;1] /contents/0/schema: Invalid schema type",
+		},
+		{
+			name:       "Error of a file without interface",
+			parseError: &ParseError{FilePath: "city/pole.json", Err: ErrInvalidInterface},
+			expected:   "city/pole.json: DTDL file must contain an interface object",
+		},
+		{name: "Error without location", parseError: &ParseError{Err: ErrContentUnmarshalInvalidType}, expected: "Invalid content @type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.parseError.Error())
+			assert.True(t, errors.Is(tt.parseError, tt.parseError.Err))
+		})
+	}
+}
+
+func TestJoinPointer(t *testing.T) {
+	assert.Equal(t, "/contents/2/schema", joinPointer("/contents", 2, "schema"))
+	assert.Equal(t, "/m~0n/a~1b", joinPointer("", "m~n", "a/b"))
+}
+
+func TestNewUnmarshalParseError(t *testing.T) {
+	var document struct {
+		DisplayName struct {
+			En string `json:"en"`
+		} `json:"displayName"`
+	}
+	err := json.Unmarshal([]byte(`{"displayName": {"en": 1}}`), &document)
+
+	parseError := newUnmarshalParseError("/contents/1", err)
+	assert.Equal(t, "/contents/1/displayName/en", parseError.Pointer)
+
+	parseError = newUnmarshalParseError("/contents/1", newParseError("/schema", ErrInvalidSchemaType))
+	assert.Equal(t, "/contents/1/schema", parseError.Pointer)
+	assert.Equal(t, ErrInvalidSchemaType, parseError.Err)
+}
@@ -0,0 +1,115 @@
+package dtdl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Interface document whose contents and schemas are parsed one by one, so all their errors are located
+type interfaceDocument struct {
+	Interface
+	Contents []interface{} `json:"contents,omitempty"`
+	Schemas  []interface{} `json:"schemas"`
+}
+
+// Parse the DTDL interface of a file. Instead of stopping at the first error, all the errors found in the file
+// are returned as *ParseError, located by the file path, the @id of the interface and the JSON pointer of the element.
+// The @context defines the DTDL version whose rules and limits are applied; when it is invalid, only the structure
+// of the interface is checked.
+func ParseInterface(filePath string, data []byte) (Interface, []error) {
+	var errs []error
+
+	var jsonObject interface{}
+	err := json.Unmarshal(data, &jsonObject)
+
+	if err != nil {
+		return Interface{}, []error{&ParseError{FilePath: filePath, Err: addSyntaxErrorPosition(data, err)}}
+	}
+
+	objectMap, ok := jsonObject.(map[string]interface{})
+
+	if !ok {
+		return Interface{}, []error{&ParseError{FilePath: filePath, Err: ErrInvalidInterface}}
+	}
+
+	interfaceId, _ := objectMap["@id"].(string)
+
+	document := interfaceDocument{}
+	err = json.Unmarshal(data, &document)
+
+	if err != nil {
+		errs = append(errs, newUnmarshalParseError("", err))
+	}
+
+	twinInterface := document.Interface
+
+	context, err := ParseContext(twinInterface.Context)
+
+	if err != nil {
+		errs = append(errs, newParseError("/@context", err))
+	}
+
+	errs = append(errs, context.validateDTMI(objectMap["@id"], "/@id", true)...)
+
+	for i, extends := range twinInterface.Extends {
+		errs = append(errs, context.validateDTMI(extends, joinPointer("", "extends", i), true)...)
+	}
+
+	if context != nil {
+		if maxContents := context.Limits().MaxContents; len(document.Contents) > maxContents {
+			errs = append(errs, newParseError("/contents", fmt.Errorf("%w of %d", ErrTooManyContents, maxContents)))
+		}
+	}
+
+	for i, contentObject := range document.Contents {
+		content, contentErrs := parseContent(contentObject, joinPointer("", "contents", i), context)
+		errs = append(errs, contentErrs...)
+
+		if len(contentErrs) == 0 {
+			twinInterface.Contents = append(twinInterface.Contents, content)
+		}
+	}
+
+	schema := &Schema{}
+	for i, schemaObject := range document.Schemas {
+		interfaceSchema, schemaErrs := schema.processSchema(schemaObject, joinPointer("", "schemas", i), schemaScope{context: context})
+		errs = append(errs, schemaErrs...)
+
+		if len(schemaErrs) == 0 {
+			twinInterface.Schemas = append(twinInterface.Schemas, interfaceSchema)
+		}
+	}
+
+	for _, err := range errs {
+		if parseError, ok := err.(*ParseError); ok {
+			parseError.FilePath = filePath
+			parseError.InterfaceId = interfaceId
+		}
+	}
+
+	return twinInterface, errs
+}
+
+// Add the line and column of JSON syntax errors, which only report the byte offset
+func addSyntaxErrorPosition(data []byte, err error) error {
+	syntaxError, ok := err.(*json.SyntaxError)
+
+	if !ok {
+		return err
+	}
+
+	// The offset counts the bytes read, including the offending one
+	offset := int(syntaxError.Offset) - 1
+	if offset > len(data) {
+		offset = len(data)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
@@ -0,0 +1,210 @@
+package dtdl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Return a DTDL interface document with the informed @context, @id and contents
+func newTestInterface(context string, id string, contents ...string) string {
+	return fmt.Sprintf(`{"@context": %s, "@id": %q, "@type": "Interface", "contents": [%s]}`, context, id, strings.Join(contents, ", "))
+}
+
+// Return a property whose schema is an object nested to the informed depth
+func newNestedProperty(depth int) string {
+	schema := `"double"`
+	for i := 0; i < depth; i++ {
+		schema = fmt.Sprintf(`{"@type": "Object", "fields": [{"name": "field", "schema": %s}]}`, schema)
+	}
+	return fmt.Sprintf(`{"@type": "Property", "name": "nested", "schema": %s}`, schema)
+}
+
+// Return the informed number of properties
+func newProperties(count int) []string {
+	var properties []string
+	for i := 0; i < count; i++ {
+		properties = append(properties, fmt.Sprintf(`{"@type": "Property", "name": "property%d", "schema": "double"}`, i))
+	}
+	return properties
+}
+
+const (
+	testV2Context                  = `"dtmi:dtdl:context;2"`
+	testV3Context                  = `"dtmi:dtdl:context;3"`
+	testV3QuantitativeTypesContext = `["dtmi:dtdl:context;3", "dtmi:dtdl:extension:quantitativeTypes;1"]`
+)
+
+func TestParseInterface(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		expectedContents int
+	}{
+		{
+			name:             "DTDL v2 interface",
+			content:          newTestInterface(testV2Context, "dtmi:city:Pole;1", `{"@type": ["Property", "Length"], "name": "height", "schema": "double", "unit": "metre"}`),
+			expectedContents: 1,
+		},
+		{
+			name: "DTDL v3 interface",
+			content: newTestInterface(testV3QuantitativeTypesContext, "dtmi:city:Pole;1.2",
+				`{"@type": ["Property", "Length"], "name": "height", "schema": "double", "unit": "metre"}`,
+				`{"@type": "Property", "name": "serial", "schema": "unsignedLong"}`,
+				`{"@type": "Property", "name": "tags", "schema": {"@type": "Array", "elementSchema": "string"}}`),
+			expectedContents: 3,
+		},
+		{name: "DTDL v3 schema depth", content: newTestInterface(testV3Context, "dtmi:city:Pole;1", newNestedProperty(8)), expectedContents: 1},
+		{name: "DTDL v3 number of contents", content: newTestInterface(testV3Context, "dtmi:city:Pole;1", newProperties(301)...), expectedContents: 301},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twinInterface, errs := ParseInterface("pole.json", []byte(tt.content))
+
+			assert.Empty(t, errs)
+			assert.Len(t, twinInterface.Contents, tt.expectedContents)
+		})
+	}
+}
+
+func TestParseInterface_Errors(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		expected         []error
+		expectedPointers []string
+	}{
+		{
+			name:             "Not an interface object",
+			content:          `[]`,
+			expected:         []error{ErrInvalidInterface},
+			expectedPointers: []string{""},
+		},
+		{
+			name:             "Missing DTDL context",
+			content:          newTestInterface(`"dtmi:dtdl:extension:quantitativeTypes;1"`, "dtmi:city:Pole;1"),
+			expected:         []error{ErrMissingContext},
+			expectedPointers: []string{"/@context"},
+		},
+		{
+			name:             "Multiple DTDL contexts",
+			content:          newTestInterface(`["dtmi:dtdl:context;2", "dtmi:dtdl:context;3"]`, "dtmi:city:Pole;1"),
+			expected:         []error{ErrMultipleContexts},
+			expectedPointers: []string{"/@context"},
+		},
+		{
+			name:             "Interface DTMI without version",
+			content:          newTestInterface(testV3Context, "dtmi:city:Pole"),
+			expected:         []error{ErrDTMIVersionRequired},
+			expectedPointers: []string{"/@id"},
+		},
+		{
+			name:             "DTMI minor version in DTDL v2",
+			content:          newTestInterface(testV2Context, "dtmi:city:Pole;1.2"),
+			expected:         []error{ErrDTMIMinorVersion},
+			expectedPointers: []string{"/@id"},
+		},
+		{
+			name:             "Invalid extended interface",
+			content:          `{"@context": "dtmi:dtdl:context;3", "@id": "dtmi:city:Pole;1", "@type": "Interface", "extends": ["dtmi:city:Asset;1", "city:Base;1"]}`,
+			expected:         []error{ErrInvalidDTMI},
+			expectedPointers: []string{"/extends/1"},
+		},
+		{
+			name: "DTDL v3 schemas in DTDL v2",
+			content: newTestInterface(testV2Context, "dtmi:city:Pole;1",
+				`{"@type": "Property", "name": "height", "schema": "double"}`,
+				`{"@type": "Property", "name": "serial", "schema": "unsignedLong"}`,
+				`{"@type": "Property", "name": "tags", "schema": {"@type": "Array", "elementSchema": "string"}}`),
+			expected:         []error{ErrSchemaNotSupported, ErrArraySchemaInProperty},
+			expectedPointers: []string{"/contents/1/schema", "/contents/2/schema"},
+		},
+		{
+			name:             "DTDL v2 schema depth",
+			content:          newTestInterface(testV2Context, "dtmi:city:Pole;1", newNestedProperty(6)),
+			expected:         []error{ErrSchemaTooDeep},
+			expectedPointers: []string{"/contents/0/schema/fields/0/schema/fields/0/schema/fields/0/schema/fields/0/schema/fields/0/schema"},
+		},
+		{
+			name:             "DTDL v3 schema depth",
+			content:          newTestInterface(testV3Context, "dtmi:city:Pole;1", newNestedProperty(9)),
+			expected:         []error{ErrSchemaTooDeep},
+			expectedPointers: []string{"/contents/0/schema/fields/0/schema/fields/0/schema/fields/0/schema/fields/0/schema/fields/0/schema/fields/0/schema/fields/0/schema/fields/0/schema"},
+		},
+		{
+			name:             "DTDL v2 number of contents",
+			content:          newTestInterface(testV2Context, "dtmi:city:Pole;1", newProperties(301)...),
+			expected:         []error{ErrTooManyContents},
+			expectedPointers: []string{"/contents"},
+		},
+		{
+			name:             "Units without the QuantitativeTypes extension",
+			content:          newTestInterface(testV3Context, "dtmi:city:Pole;1", `{"@type": ["Property", "Length"], "name": "height", "schema": "double", "unit": "metre"}`),
+			expected:         []error{ErrQuantitativeTypesNotSupported},
+			expectedPointers: []string{"/contents/0/@type"},
+		},
+		{
+			name: "Errors of several contents",
+			content: newTestInterface(testV3Context, "dtmi:city:Pole;1",
+				`{"@type": "Property", "name": "height", "schema": "number"}`,
+				`{"@type": "Component", "name": "light", "schema": {"@type": "Object", "fields": []}}`),
+			expected:         []error{ErrInvalidSchemaType, ErrInvalidComponentSchema},
+			expectedPointers: []string{"/contents/0/schema", "/contents/1/schema"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := ParseInterface("city/pole.json", []byte(tt.content))
+			assert.Equal(t, len(tt.expected), len(errs), errs)
+
+			for index, err := range errs {
+				if index < len(tt.expected) {
+					assert.True(t, errors.Is(err, tt.expected[index]), err.Error())
+
+					parseError, ok := err.(*ParseError)
+					assert.True(t, ok, err.Error())
+					assert.Equal(t, "city/pole.json", parseError.FilePath)
+					assert.Equal(t, tt.expectedPointers[index], parseError.Pointer)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInterface_ErrorLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "JSON syntax error",
+			content:  "{\n    \"@id\": \"dtmi:city:Pole;1\",\n    \"@type\": \n}",
+			expected: "city/pole.json: line 4, column 1: invalid character '}' looking for beginning of value",
+		},
+		{
+			name:     "Line break in a string",
+			content:  "{\n  \"@id\": \"dtmi\n\"}",
+			expected: "city/pole.json: line 2, column 15: invalid character '\\n' in string",
+		},
+		{
+			name:     "Content error",
+			content:  newTestInterface(testV3Context, "dtmi:city:Pole;1", `{"@type": "Property", "name": "height", "schema": "number"}`),
+			expected: `city/pole.json [dtmi:city:Pole;1] /contents/0/schema: Invalid schema type: "number"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := ParseInterface("city/pole.json", []byte(tt.content))
+
+			assert.Len(t, errs, 1)
+			assert.Equal(t, tt.expected, errs[0].Error())
+		})
+	}
+}
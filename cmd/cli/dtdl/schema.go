@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"strconv"
 )
 
 var (
@@ -14,9 +14,16 @@ var (
 	STANDARD_STRING_SCHEMA  = "string"
 	STANDARD_ENUM_SCHEMA    = "enum"
 
+	ARRAY_SCHEMA_TYPE  = "Array"
+	ENUM_SCHEMA_TYPE   = "Enum"
+	MAP_SCHEMA_TYPE    = "Map"
+	OBJECT_SCHEMA_TYPE = "Object"
+
 	ErrUnmarshalTypeNotSupported = errors.New("Unmarshal type not supported")
 	ErrInvalidSchemaType         = errors.New("Invalid schema type")
 	ErrUnmarshalUnknown          = errors.New("Unmarshal unknown error")
+	ErrInvalidEnumValues         = errors.New("Enum values must be an array of objects")
+	ErrInvalidObjectFields       = errors.New("Object fields must be an array of objects")
 )
 
 // https://github.com/Azure/opendigitaltwins-dtdl/blob/master/DTDL/v3/DTDL.v3.md#schema
@@ -33,17 +40,29 @@ var (
 // string - a UTF8 string
 // time	- a time in ISO 8601 format, per RFC 3339
 
+// Primitive Schemas added by DTDL v3:
+// byte, short, unsignedByte, unsignedShort, unsignedInteger, unsignedLong - integral numeric values of the informed size
+// bytes - a byte array encoded in base64
+// decimal - a decimal numeric value of arbitrary precision
+// uuid - a UUID string
+
+// Geospatial Schemas (GeoJSON geometries):
+// point, multiPoint, lineString, multiLineString, polygon, multiPolygon
+
 // Complex Schemas:
-// Array - Not supported
+// Array - Supported
 // Enum - Supported
-// Map - Not supported
-// Object - Not supported
+// Map - Supported
+// Object - Supported
+// Array elements, map values and object fields can be any schema, including other complex schemas
 
 type Schema struct {
 	IsDefaultSchema    bool
 	DefaultSchemaValue string
 	EnumSchema         EnumSchema
 	ObjectSchema       ObjectSchema
+	ArraySchema        ArraySchema
+	MapSchema          MapSchema
 }
 
 type EnumSchema struct {
@@ -65,7 +84,43 @@ type ObjectSchema struct {
 type ObjectSchemaFields struct {
 	Name        string `json:"name" yaml:"name,omitempty"`
 	DisplayName string `json:"displayName" yaml:"displayName,omitempty"`
-	Schema      string `json:"schema" yaml:"objectSchema,omitempty"`
+	Description string `json:"description" yaml:"description,omitempty"`
+	Schema      Schema `json:"schema" yaml:"schema,omitempty"`
+}
+
+type ArraySchema struct {
+	Type          string  `json:"@type" yaml:"type,omitempty"`
+	ElementSchema *Schema `json:"elementSchema" yaml:"elementSchema,omitempty"`
+}
+
+type MapSchema struct {
+	Type     string   `json:"@type" yaml:"type,omitempty"`
+	MapKey   MapKey   `json:"mapKey" yaml:"mapKey,omitempty"`
+	MapValue MapValue `json:"mapValue" yaml:"mapValue,omitempty"`
+}
+
+type MapKey struct {
+	Name   string `json:"name" yaml:"name,omitempty"`
+	Schema string `json:"schema" yaml:"schema,omitempty"`
+}
+
+type MapValue struct {
+	Name   string  `json:"name" yaml:"name,omitempty"`
+	Schema *Schema `json:"schema" yaml:"schema,omitempty"`
+}
+
+// Rules of the DTDL version applied while processing a schema. The zero value applies no version rules,
+// so schemas unmarshaled on their own are only checked for their structure.
+type schemaScope struct {
+	context    *DocumentContext
+	depth      int  // Number of complex schemas enclosing the schema
+	inProperty bool // Whether the schema is, or is nested in, the schema of a property
+}
+
+// Return the scope of the schemas nested in a complex schema
+func (sc schemaScope) nested() schemaScope {
+	sc.depth++
+	return sc
 }
 
 func (s *Schema) UnmarshalJSON(data []byte) error {
@@ -76,45 +131,18 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	switch object := jsonObject.(type) {
-	case string:
-		// TODO: check if the type is valid
-		*s = Schema{
-			IsDefaultSchema:    true,
-			DefaultSchemaValue: object,
-		}
+	if jsonObject == nil {
 		return nil
-	case nil:
-		return nil
-	case interface{}:
-		schema, err := s.processSchemaInterface(jsonObject)
-
-		if err != nil {
-			return err
-		}
-
-		enumSchema, isEnumSchema := schema.(EnumSchema)
-
-		if isEnumSchema {
-			*s = Schema{
-				IsDefaultSchema: false,
-				EnumSchema:      enumSchema,
-			}
-		}
-
-		objectSchema, isObjectSchema := schema.(ObjectSchema)
+	}
 
-		if isObjectSchema {
-			*s = Schema{
-				IsDefaultSchema: false,
-				ObjectSchema:    objectSchema,
-			}
-		}
+	schema, errs := s.processSchema(jsonObject, "", schemaScope{})
 
-		return nil
+	if len(errs) > 0 {
+		return errs[0]
 	}
 
-	return ErrUnmarshalUnknown
+	*s = schema
+	return nil
 }
 
 func (s *Schema) MarshalJSON() ([]byte, error) {
@@ -122,67 +150,148 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		return json.Marshal(s.DefaultSchemaValue)
 	}
 
-	return json.Marshal(s.EnumSchema)
+	return json.Marshal(s.getComplexSchema())
 }
 
 func (s Schema) MarshalYAML() (interface{}, error) {
 	if s.IsDefaultSchema {
-		fmt.Println("Default schema")
 		return s.DefaultSchemaValue, nil
 	}
-	fmt.Println("Enum schema")
-	return s.EnumSchema, nil
+	return s.getComplexSchema(), nil
+}
+
+func (s *Schema) getComplexSchema() interface{} {
+	switch {
+	case s.ObjectSchema.Type != "":
+		return s.ObjectSchema
+	case s.ArraySchema.Type != "":
+		return s.ArraySchema
+	case s.MapSchema.Type != "":
+		return s.MapSchema
+	default:
+		return s.EnumSchema
+	}
 }
 
 // Schema
-func (s *Schema) processSchemaInterface(jsonObject interface{}) (interface{}, error) {
+// Processing errors are returned as *ParseError, located by the JSON pointer of the schema appended to the informed one
+func (s *Schema) processSchema(jsonObject interface{}, pointer string, scope schemaScope) (Schema, []error) {
+	switch object := jsonObject.(type) {
+	case string:
+		if scope.context != nil {
+			if err := scope.context.validatePrimitiveSchema(object); err != nil {
+				return Schema{}, []error{newParseError(pointer, err)}
+			}
+		}
+
+		return Schema{
+			IsDefaultSchema:    true,
+			DefaultSchemaValue: object,
+		}, nil
+	case map[string]interface{}:
+		return s.processSchemaInterface(object, pointer, scope.nested())
+	}
+
+	return Schema{}, []error{newParseError(pointer, ErrUnmarshalTypeNotSupported)}
+}
 
-	objectMap, isMapStringInterface := jsonObject.(map[string]interface{})
+func (s *Schema) processSchemaInterface(objectMap map[string]interface{}, pointer string, scope schemaScope) (Schema, []error) {
 
-	if isMapStringInterface {
+	schemaType := s.getStringNotNull(objectMap["@type"])
 
-		schemaType := objectMap["@type"].(string)
+	if scope.context != nil {
+		if maxDepth := scope.context.Limits().MaxSchemaDepth; scope.depth > maxDepth {
+			return Schema{}, []error{newParseError(pointer, fmt.Errorf("%w of %d", ErrSchemaTooDeep, maxDepth))}
+		}
 
-		switch schemaType {
-		case "Enum":
-			valueSchema := objectMap["valueSchema"].(string)
-			enumValues := s.processSchemaEnumValues(objectMap["enumValues"])
+		if schemaType == ARRAY_SCHEMA_TYPE && scope.inProperty && scope.context.Version < VERSION_3 {
+			return Schema{}, []error{newParseError(pointer, ErrArraySchemaInProperty)}
+		}
+	}
 
-			enumSchema := EnumSchema{
+	switch schemaType {
+	case ENUM_SCHEMA_TYPE:
+		valueSchema := s.getStringNotNull(objectMap["valueSchema"])
+		enumValues, errs := s.processSchemaEnumValues(objectMap["enumValues"], joinPointer(pointer, "enumValues"))
+
+		return Schema{
+			EnumSchema: EnumSchema{
 				Type:        schemaType,
 				ValueSchema: valueSchema,
 				EnumValues:  enumValues,
-			}
-			return enumSchema, nil
-		case "Object":
-			fieldsValues := s.processSchemaObjectValues(objectMap["fields"])
+			},
+		}, errs
+	case OBJECT_SCHEMA_TYPE:
+		fieldsValues, errs := s.processSchemaObjectValues(objectMap["fields"], joinPointer(pointer, "fields"), scope)
 
-			objectSchema := ObjectSchema{
+		return Schema{
+			ObjectSchema: ObjectSchema{
 				Type:   schemaType,
 				Fields: fieldsValues,
-			}
-			return objectSchema, nil
-		default:
-			log.Fatal("It was not able to process schema. Schema type is invalid: ", schemaType)
-			return EnumSchema{}, ErrInvalidSchemaType
+			},
+		}, errs
+	case ARRAY_SCHEMA_TYPE:
+		elementSchema, errs := s.processSchema(objectMap["elementSchema"], joinPointer(pointer, "elementSchema"), scope)
+
+		return Schema{
+			ArraySchema: ArraySchema{
+				Type:          schemaType,
+				ElementSchema: &elementSchema,
+			},
+		}, errs
+	case MAP_SCHEMA_TYPE:
+		var errs []error
+		mapKey, isValidMapKey := objectMap["mapKey"].(map[string]interface{})
+		mapValue, isValidMapValue := objectMap["mapValue"].(map[string]interface{})
+
+		if !isValidMapKey {
+			errs = append(errs, newParseError(joinPointer(pointer, "mapKey"), ErrInvalidSchemaType))
 		}
-	}
 
-	return EnumSchema{}, ErrUnmarshalTypeNotSupported
+		if !isValidMapValue {
+			return Schema{}, append(errs, newParseError(joinPointer(pointer, "mapValue"), ErrInvalidSchemaType))
+		}
+
+		mapValueSchema, mapValueErrs := s.processSchema(mapValue["schema"], joinPointer(pointer, "mapValue", "schema"), scope)
+		errs = append(errs, mapValueErrs...)
+
+		return Schema{
+			MapSchema: MapSchema{
+				Type: schemaType,
+				MapKey: MapKey{
+					Name:   s.getStringNotNull(mapKey["name"]),
+					Schema: s.getStringNotNull(mapKey["schema"]),
+				},
+				MapValue: MapValue{
+					Name:   s.getStringNotNull(mapValue["name"]),
+					Schema: &mapValueSchema,
+				},
+			},
+		}, errs
+	default:
+		return Schema{}, []error{newParseError(joinPointer(pointer, "@type"), fmt.Errorf("%w: %q", ErrInvalidSchemaType, schemaType))}
+	}
 }
 
-func (s *Schema) processSchemaEnumValues(enumValuesMap interface{}) []EnumSchemaValues {
+func (s *Schema) processSchemaEnumValues(enumValuesMap interface{}, pointer string) ([]EnumSchemaValues, []error) {
+	var errs []error
 
 	enumValues, isValidListMap := enumValuesMap.([]interface{})
 
 	if !isValidListMap {
-		log.Fatal("Invalid type for enum values array")
+		return nil, []error{newParseError(pointer, ErrInvalidEnumValues)}
 	}
 
 	var enumSchemaValues []EnumSchemaValues = make([]EnumSchemaValues, 0)
 
-	for _, enumValue := range enumValues {
-		enumMap := enumValue.(map[string]interface{})
+	for i, enumValue := range enumValues {
+		enumMap, isValidMap := enumValue.(map[string]interface{})
+
+		if !isValidMap {
+			errs = append(errs, newParseError(joinPointer(pointer, i), ErrInvalidEnumValues))
+			continue
+		}
+
 		enumSchemaValue := EnumSchemaValues{
 			Name:        s.getStringNotNull(enumMap["name"]),
 			DisplayName: s.getStringNotNull(enumMap["displayName"]),
@@ -191,40 +300,55 @@ func (s *Schema) processSchemaEnumValues(enumValuesMap interface{}) []EnumSchema
 		enumSchemaValues = append(enumSchemaValues, enumSchemaValue)
 	}
 
-	return enumSchemaValues
+	return enumSchemaValues, errs
 
 }
 
-func (s *Schema) processSchemaObjectValues(objectFieldsMap interface{}) []ObjectSchemaFields {
+func (s *Schema) processSchemaObjectValues(objectFieldsMap interface{}, pointer string, scope schemaScope) ([]ObjectSchemaFields, []error) {
+	var errs []error
 
 	objectValues, isValidListMap := objectFieldsMap.([]interface{})
 
 	if !isValidListMap {
-		log.Fatal("Invalid type for object values array")
+		return nil, []error{newParseError(pointer, ErrInvalidObjectFields)}
 	}
 
 	var objectSchemaValues []ObjectSchemaFields = make([]ObjectSchemaFields, 0)
 
-	for _, objectValue := range objectValues {
-		objectMap := objectValue.(map[string]interface{})
+	for i, objectValue := range objectValues {
+		objectMap, isValidMap := objectValue.(map[string]interface{})
+
+		if !isValidMap {
+			errs = append(errs, newParseError(joinPointer(pointer, i), ErrInvalidObjectFields))
+			continue
+		}
+
+		fieldSchema, fieldErrs := s.processSchema(objectMap["schema"], joinPointer(pointer, i, "schema"), scope)
+		errs = append(errs, fieldErrs...)
+
 		objectSchemaValue := ObjectSchemaFields{
 			Name:        s.getStringNotNull(objectMap["name"]),
 			DisplayName: s.getStringNotNull(objectMap["displayName"]),
-			Schema:      s.getStringNotNull(objectMap["schema"]),
+			Description: s.getStringNotNull(objectMap["description"]),
+			Schema:      fieldSchema,
 		}
 		objectSchemaValues = append(objectSchemaValues, objectSchemaValue)
 	}
 
-	return objectSchemaValues
+	return objectSchemaValues, errs
 }
 
+// Return string values as they are and numbers formatted, such as the values of integer enums. Other values are ignored.
 func (s *Schema) getStringNotNull(value interface{}) string {
-	if value == nil {
-		return ""
+	switch object := value.(type) {
+	case string:
+		return object
+	case float64:
+		return strconv.FormatFloat(object, 'f', -1, 64)
 	}
-	return value.(string)
+	return ""
 }
 
 func (s *Schema) isValidSchemaType(schemaType string) bool {
-	return schemaType == "Enum" || schemaType == "Object"
+	return schemaType == ENUM_SCHEMA_TYPE || schemaType == OBJECT_SCHEMA_TYPE || schemaType == ARRAY_SCHEMA_TYPE || schemaType == MAP_SCHEMA_TYPE
 }
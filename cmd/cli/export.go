@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	v0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdl "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/dtdl"
+	pkg "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/pkg"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Export TwinInterfaces as DTDL v3 files, named by the TwinInterface. The exported files are parsed before they are
+// written, so models can be converted back into the same TwinInterfaces.
+func exportTwinInterfaces(inputFolderPath string, fromCluster bool, namespace string, outputFolderPath string) []error {
+	var twinInterfaces []v0.TwinInterface
+	var err error
+
+	if fromCluster {
+		fmt.Fprintln(progress, "Exporting TwinInterfaces of namespace "+namespace)
+		twinInterfaces, err = loadClusterTwinInterfaces(namespace)
+	} else {
+		fmt.Fprintln(progress, "Exporting TwinInterfaces of folder "+inputFolderPath)
+		twinInterfaces, err = loadFolderTwinInterfaces(inputFolderPath)
+	}
+
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	exportedInterfaces := pkg.NewDTDLExporter().ExportInterfaces(twinInterfaces)
+
+	for _, exportedInterface := range exportedInterfaces {
+		outputFilePath := filepath.Join(outputFolderPath, exportedInterface.TwinInterfaceName+".json")
+
+		for _, warning := range exportedInterface.Warnings {
+			fmt.Fprintf(progress, "Twin Interface {%s}: %s\n", exportedInterface.TwinInterfaceName, warning)
+		}
+
+		if _, parseErrors := dtdl.ParseInterface(outputFilePath, exportedInterface.Content); len(parseErrors) > 0 {
+			errs = append(errs, parseErrors...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for _, exportedInterface := range exportedInterfaces {
+		outputFilePath := filepath.Join(outputFolderPath, exportedInterface.TwinInterfaceName+".json")
+
+		if err := writeOutputFile(outputFilePath, exportedInterface.Content); err != nil {
+			return []error{err}
+		}
+	}
+
+	return nil
+}
+
+// Load the TwinInterfaces of the YAML and JSON files of the folder and its sub folders
+func loadFolderTwinInterfaces(inputFolderPath string) ([]v0.TwinInterface, error) {
+	objects, err := loadFolderManifests(inputFolderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var twinInterfaces []v0.TwinInterface
+	for _, object := range objects {
+		if twinInterface, ok := object.(*v0.TwinInterface); ok {
+			twinInterfaces = append(twinInterfaces, *twinInterface)
+		}
+	}
+
+	return twinInterfaces, nil
+}
+
+// Load the TwinInterfaces and TwinInstances of the YAML and JSON files of the folder and its sub folders
+func loadFolderManifests(folderPath string) ([]client.Object, error) {
+	var objects []client.Object
+
+	err := filepath.WalkDir(folderPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		if extension := filepath.Ext(filePath); extension != ".yaml" && extension != ".yml" && extension != ".json" {
+			return nil
+		}
+
+		fileContent, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		fileObjects, err := pkg.LoadManifests(filePath, fileContent)
+		objects = append(objects, fileObjects...)
+		return err
+	})
+
+	return objects, err
+}
+
+func loadClusterTwinInterfaces(namespace string) ([]v0.TwinInterface, error) {
+	clusterClient, err := pkg.NewClusterClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg.ListTwinInterfaces(context.Background(), clusterClient, namespace)
+}
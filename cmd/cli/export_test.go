@@ -0,0 +1,129 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdl "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/dtdl"
+	pkg "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/pkg"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DTDL models of the interfaces referenced by the instance graph of the hack folder
+const testSmartCitiesFolderPath = "testdata/smartcities"
+
+const testInstanceGraphFilePath = "../../hack/dtdl-instance-graph.json"
+
+// Generate the TwinInterfaces of the smart cities models, keyed by their name
+func generateSmartCitiesTwinInterfaces(t *testing.T) map[string]apiv0.TwinInterface {
+	generatedFiles, ok := generateResources(&GenerateOptions{
+		InputFolderPath:   testSmartCitiesFolderPath,
+		Namespace:         "ktwin",
+		InstanceGraphFile: testInstanceGraphFilePath,
+	}, "")
+	assert.True(t, ok)
+
+	twinInterfaces := map[string]apiv0.TwinInterface{}
+	for _, generatedFile := range generatedFiles {
+		twinInterfaces[generatedFile.TwinInterface.Name] = *generatedFile.TwinInterface
+	}
+
+	return twinInterfaces
+}
+
+// Parse the exported interfaces and create their TwinInterfaces, keyed by their name
+func parseExportedInterfaces(t *testing.T, exportedInterfaces []pkg.ExportedInterface) map[string]apiv0.TwinInterface {
+	resourceBuilder := pkg.NewResourceBuilder()
+	twinInterfaces := map[string]apiv0.TwinInterface{}
+
+	for _, exportedInterface := range exportedInterfaces {
+		assert.Empty(t, exportedInterface.Warnings, exportedInterface.TwinInterfaceName)
+
+		parsedInterface, errs := dtdl.ParseInterface(exportedInterface.TwinInterfaceName+".json", exportedInterface.Content)
+		assert.Empty(t, errs, string(exportedInterface.Content))
+
+		twinInterface := resourceBuilder.CreateTwinInterface(parsedInterface)
+		twinInterfaces[twinInterface.Name] = twinInterface
+	}
+
+	return twinInterfaces
+}
+
+func TestExportTwinInterfaces_RoundTrip(t *testing.T) {
+	progress = io.Discard
+
+	twinInterfaces := generateSmartCitiesTwinInterfaces(t)
+	assert.Len(t, twinInterfaces, 8)
+	assert.True(t, twinInterfaces["dtmi-digitaltwins-s4city-city-neighborhood-1"].Spec.Relationships[0].Writeable)
+
+	var objects []client.Object
+	for name := range twinInterfaces {
+		twinInterface := twinInterfaces[name]
+		objects = append(objects, &twinInterface)
+	}
+
+	manifestsFolderPath := t.TempDir()
+	assert.Nil(t, writeManifestsFile(filepath.Join(manifestsFolderPath, "interfaces.yaml"), objects, pkg.OutputFormatYAML))
+
+	outputFolderPath := t.TempDir()
+	assert.Empty(t, exportTwinInterfaces(manifestsFolderPath, false, "", outputFolderPath))
+
+	generatedFiles, ok := generateResources(&GenerateOptions{InputFolderPath: outputFolderPath, Namespace: "ktwin"}, "")
+	assert.True(t, ok)
+	assert.Len(t, generatedFiles, len(twinInterfaces))
+
+	for _, generatedFile := range generatedFiles {
+		exportedTwinInterface := generatedFile.TwinInterface
+		twinInterface, ok := twinInterfaces[exportedTwinInterface.Name]
+
+		assert.True(t, ok, exportedTwinInterface.Name)
+		assert.Equal(t, twinInterface.Annotations, exportedTwinInterface.Annotations)
+		assert.Equal(t, twinInterface.Spec, exportedTwinInterface.Spec)
+	}
+}
+
+func TestDTDLExporter_ClusterTwinInterfaces(t *testing.T) {
+	progress = io.Discard
+
+	twinInterfaces := generateSmartCitiesTwinInterfaces(t)
+	parkingTwinInterface := twinInterfaces["dtmi-digitaltwins-ngsi-ld-city-parking-1"]
+
+	// TwinInterfaces created in the cluster have no DTMI annotation and the controller resolves their effective spec
+	var clusterTwinInterfaces []apiv0.TwinInterface
+	for _, twinInterface := range twinInterfaces {
+		twinInterface.Annotations = nil
+		twinInterface.ResourceVersion = "1"
+
+		effectiveSpec := inheritance.GetEffectiveSpec([]apiv0.TwinInterface{twinInterface})
+		if len(twinInterface.Spec.GetExtends()) > 0 {
+			effectiveSpec = inheritance.GetEffectiveSpec([]apiv0.TwinInterface{twinInterface, parkingTwinInterface})
+		}
+		twinInterface.Status.EffectiveSpec = &effectiveSpec
+
+		clusterTwinInterfaces = append(clusterTwinInterfaces, twinInterface)
+	}
+
+	exportedInterfaces := pkg.NewDTDLExporter().ExportInterfaces(clusterTwinInterfaces)
+	assert.Len(t, exportedInterfaces, len(twinInterfaces))
+
+	exportedTwinInterfaces := parseExportedInterfaces(t, exportedInterfaces)
+
+	for name, twinInterface := range twinInterfaces {
+		exportedTwinInterface, ok := exportedTwinInterfaces[name]
+
+		assert.True(t, ok, name)
+		assert.Equal(t, twinInterface.Annotations, exportedTwinInterface.Annotations)
+		assert.Equal(t, twinInterface.Spec, exportedTwinInterface.Spec)
+	}
+
+	// Only the contents declared by the TwinInterface are exported, the inherited ones are kept in the parent
+	offStreetParking := exportedTwinInterfaces["dtmi-digitaltwins-ngsi-ld-city-offstreetparking-1"]
+	assert.Equal(t, []string{parkingTwinInterface.Name}, offStreetParking.Spec.Extends)
+	assert.Len(t, offStreetParking.Spec.Properties, 1)
+	assert.Equal(t, "maximumAllowedHeight", offStreetParking.Spec.Properties[0].Name)
+}
@@ -0,0 +1,497 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdl "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/dtdl"
+	pkg "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/pkg"
+	"github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/utils"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/graph"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The progress messages are written to the standard error by the commands whose output is written to the standard
+// output
+var progress io.Writer = os.Stdout
+
+const (
+	// Name of the file with all the generated resources, without extension
+	BUNDLE_FILE_NAME = "resources"
+)
+
+type ProcessedFile struct {
+	InputFilePath   string
+	outputFilePath  string
+	TwinInterfaceId string
+	DTMI            string
+}
+
+// The resources generated for a TwinInterface, written in a TwinInterface file and a TwinInstances file. The
+// TwinInterface is nil when only the TwinInstances are generated.
+type GeneratedFile struct {
+	OutputFilePath string
+	TwinInterface  *v0.TwinInterface
+	TwinInstances  []v0.TwinInstance
+}
+
+// Return the generated resources, all the TwinInterfaces before the TwinInstances that implement them
+func getGeneratedObjects(generatedFiles []GeneratedFile) []client.Object {
+	var twinInterfaces, twinInstances []client.Object
+
+	for _, generatedFile := range generatedFiles {
+		if generatedFile.TwinInterface != nil {
+			twinInterfaces = append(twinInterfaces, generatedFile.TwinInterface)
+		}
+
+		for index := range generatedFile.TwinInstances {
+			twinInstances = append(twinInstances, &generatedFile.TwinInstances[index])
+		}
+	}
+
+	return append(twinInterfaces, twinInstances...)
+}
+
+// Load the TwinInterface graph of the DTDL files of the input folder. The graph is not returned when any file has
+// errors, which are reported.
+func loadTwinInterfaceGraph(inputFolderPath string, outputFolderPath string, resourceBuilder pkg.ResourceBuilder) (graph.TwinInterfaceGraph, []ProcessedFile, bool) {
+	fmt.Fprintln(progress, "Processing folder "+inputFolderPath)
+
+	dtdlGraph, processedFiles, parseErrors := processAllFilesInFolder(inputFolderPath, outputFolderPath, resourceBuilder, graph.NewTwinInterfaceGraph(), []ProcessedFile{}, nil)
+	parseErrors = append(parseErrors, getResourceNameCollisions(processedFiles)...)
+	parseErrors = append(parseErrors, getInheritanceErrors(processedFiles, dtdlGraph)...)
+
+	// Resources are not generated from a partial set of interfaces
+	if len(parseErrors) > 0 {
+		printParseErrorsReport(parseErrors)
+		return nil, nil, false
+	}
+
+	return dtdlGraph, processedFiles, true
+}
+
+// Generate the TwinInterfaces and the TwinInstances of the instance graph file, or the TwinInstances of the
+// inventory when it is informed. Nothing is returned when there are errors, which are reported.
+func generateResources(options *GenerateOptions, outputFolderPath string) ([]GeneratedFile, bool) {
+	cliConfig, err := loadCLIConfig(options.ConfigFile)
+	if err != nil {
+		printConfigErrorReport(options.ConfigFile, err)
+		return nil, false
+	}
+
+	resourceBuilder := pkg.NewResourceBuilderWithConfig(cliConfig)
+
+	dtdlGraph, processedFiles, ok := loadTwinInterfaceGraph(options.InputFolderPath, outputFolderPath, resourceBuilder)
+	if !ok {
+		return nil, false
+	}
+
+	var generatedFiles []GeneratedFile
+
+	if options.InventoryFile != "" {
+		var inventoryErrors []error
+		generatedFiles, inventoryErrors = generateInventoryFiles(options.InventoryFile, options.InventoryMappingFile, outputFolderPath, dtdlGraph, resourceBuilder)
+
+		if len(inventoryErrors) > 0 {
+			printInventoryErrorsReport(options.InventoryFile, inventoryErrors)
+			return nil, false
+		}
+	} else {
+		instanceGraph, instanceGraphErrors := generateInstanceGraph(options.InstanceGraphFile, dtdlGraph)
+
+		if len(instanceGraphErrors) > 0 {
+			printInstanceGraphErrorsReport(options.InstanceGraphFile, instanceGraphErrors)
+			return nil, false
+		}
+
+		generatedFiles = generateFiles(processedFiles, dtdlGraph, instanceGraph, resourceBuilder)
+	}
+
+	for _, object := range getGeneratedObjects(generatedFiles) {
+		object.SetNamespace(options.Namespace)
+	}
+
+	return generatedFiles, true
+}
+
+// Load the CLI config file. When the file is not informed, the default settings are used.
+func loadCLIConfig(configFile string) (pkg.CLIConfig, error) {
+	if configFile == "" {
+		return pkg.CLIConfig{}, nil
+	}
+
+	fmt.Fprintln(progress, "Processing config file "+configFile)
+
+	fileContent, err := os.ReadFile(configFile)
+	if err != nil {
+		return pkg.CLIConfig{}, err
+	}
+
+	return pkg.LoadCLIConfig(fileContent)
+}
+
+// Generate the TwinInstances of all the TwinInterfaces according to the instance graph file. When the file is not
+// informed, each TwinInterface has one instance.
+func generateInstanceGraph(instanceGraphFile string, dtdlGraph graph.TwinInterfaceGraph) (graph.TwinInstanceGraph, []error) {
+	settings := graph.TwinGraphEnvironmentSettings{}
+
+	if instanceGraphFile != "" {
+		fmt.Fprintln(progress, "Processing instance graph file "+instanceGraphFile)
+
+		fileContent, err := os.ReadFile(instanceGraphFile)
+		if err != nil {
+			return nil, []error{err}
+		}
+
+		settings, err = graph.LoadTwinGraphEnvironmentSettings(fileContent)
+		if err != nil {
+			return nil, []error{err}
+		}
+	}
+
+	return graph.NewTwinInstanceGraphFromSettings(settings, dtdlGraph)
+}
+
+// Process all files in the specified folder. Files with errors are not added to the graph and their errors are
+// collected, so all the errors of the folder can be reported at once.
+func processAllFilesInFolder(inputFolderPath string, outputFolderPath string, resourceBuilder pkg.ResourceBuilder, dtdlGraph graph.TwinInterfaceGraph, processedFiles []ProcessedFile, parseErrors []error) (graph.TwinInterfaceGraph, []ProcessedFile, []error) {
+	files, err := os.ReadDir(inputFolderPath)
+
+	if err != nil {
+		return dtdlGraph, processedFiles, append(parseErrors, &dtdl.ParseError{FilePath: inputFolderPath, Err: err})
+	}
+
+	for _, file := range files {
+		inputFilePath := filepath.Join(inputFolderPath, file.Name())
+
+		if !file.IsDir() {
+			if !pkg.IsJsonFile(inputFilePath) {
+				continue
+			}
+
+			fmt.Fprintln(progress, "Processing file "+file.Name())
+			twinInterface, errs := loadDTDLFileIntoGraph(inputFilePath, resourceBuilder)
+
+			if len(errs) > 0 {
+				parseErrors = append(parseErrors, errs...)
+				continue
+			}
+
+			outputFileName := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+			outputFilePath := filepath.Join(outputFolderPath, outputFileName+".yaml")
+
+			processedFiles = append(processedFiles, ProcessedFile{
+				InputFilePath:   inputFilePath,
+				outputFilePath:  outputFilePath,
+				TwinInterfaceId: twinInterface.Spec.Id,
+				DTMI:            pkg.GetTwinInterfaceDTMI(twinInterface),
+			})
+
+			dtdlGraph = updateGraph(dtdlGraph, twinInterface)
+
+		} else {
+			fmt.Fprintln(progress, "Processing directory "+file.Name())
+
+			// The file is a directory, get into the the directory and process the files recursively
+			nestedOutputFolderPath := filepath.Join(outputFolderPath, file.Name())
+			dtdlGraph, processedFiles, parseErrors = processAllFilesInFolder(inputFilePath, nestedOutputFolderPath, resourceBuilder, dtdlGraph, processedFiles, parseErrors)
+		}
+	}
+
+	return dtdlGraph, processedFiles, parseErrors
+}
+
+func loadDTDLFileIntoGraph(inputFilePath string, resourceBuilder pkg.ResourceBuilder) (v0.TwinInterface, []error) {
+	fileContent, err := os.ReadFile(inputFilePath)
+	if err != nil {
+		return v0.TwinInterface{}, []error{&dtdl.ParseError{FilePath: inputFilePath, Err: err}}
+	}
+
+	twinInterface, errs := dtdl.ParseInterface(inputFilePath, fileContent)
+
+	if len(errs) > 0 {
+		return v0.TwinInterface{}, errs
+	}
+
+	twinInterfaceResource := resourceBuilder.CreateTwinInterface(twinInterface)
+
+	if err := utils.NewHostUtils().ValidateHostName(twinInterfaceResource.Name); err != nil {
+		return v0.TwinInterface{}, []error{&dtdl.ParseError{
+			FilePath:    inputFilePath,
+			InterfaceId: string(twinInterface.Id),
+			Pointer:     "/@id",
+			Err:         fmt.Errorf("%w %q: %s", dtdl.ErrInvalidResourceName, twinInterfaceResource.Name, err),
+		}}
+	}
+
+	return twinInterfaceResource, nil
+}
+
+// Return an error for each interface whose DTMI, resource name or model version is already used by an interface of
+// a previous file. Resource names are not unique, since DTMIs are case sensitive and some of their characters are
+// replaced, and the minor versions of DTDL v3 generate the TwinInterface of their major version.
+func getResourceNameCollisions(processedFiles []ProcessedFile) []error {
+	var errs []error
+	dtmis := map[string]ProcessedFile{}
+	resourceNames := map[string]ProcessedFile{}
+	modelVersions := map[string]ProcessedFile{}
+
+	for _, processedFile := range processedFiles {
+		var err error
+		var modelVersion string
+
+		if parsedDTMI, parseErr := dtdl.ParseDTMI(processedFile.DTMI); parseErr == nil {
+			modelVersion = fmt.Sprintf("%s;%d", parsedDTMI.Unversioned(), parsedDTMI.MajorVersion)
+		}
+
+		if previousFile, ok := dtmis[processedFile.DTMI]; ok {
+			err = fmt.Errorf("%w, also in %s", dtdl.ErrDuplicateInterface, previousFile.InputFilePath)
+		} else if previousFile, ok := resourceNames[processedFile.TwinInterfaceId]; ok {
+			dtmis[processedFile.DTMI] = processedFile
+			err = fmt.Errorf("%w %q of %s, both named %q", dtdl.ErrResourceNameCollision, previousFile.DTMI, previousFile.InputFilePath, processedFile.TwinInterfaceId)
+		} else if previousFile, ok := modelVersions[modelVersion]; ok && modelVersion != "" {
+			dtmis[processedFile.DTMI] = processedFile
+			err = fmt.Errorf("%w %q of %s", dtdl.ErrModelVersionCollision, previousFile.DTMI, previousFile.InputFilePath)
+		} else {
+			dtmis[processedFile.DTMI] = processedFile
+			resourceNames[processedFile.TwinInterfaceId] = processedFile
+			modelVersions[modelVersion] = processedFile
+			continue
+		}
+
+		errs = append(errs, &dtdl.ParseError{
+			FilePath:    processedFile.InputFilePath,
+			InterfaceId: processedFile.DTMI,
+			Pointer:     "/@id",
+			Err:         err,
+		})
+	}
+
+	return errs
+}
+
+// Generate one file for each twin interface with the twin instances of the inventory rows, without the twin
+// interface
+func generateInventoryFiles(inventoryFile string, inventoryMappingFile string, outputFolderPath string, dtdlGraph graph.TwinInterfaceGraph, resourceBuilder pkg.ResourceBuilder) ([]GeneratedFile, []error) {
+	fmt.Fprintln(progress, "Processing inventory file "+inventoryFile)
+
+	inventoryContent, err := os.ReadFile(inventoryFile)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	inventory, err := pkg.LoadInventory(inventoryFile, inventoryContent)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	mappingContent, err := os.ReadFile(inventoryMappingFile)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	mapping, err := pkg.LoadInventoryMapping(mappingContent)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", inventoryMappingFile, err)}
+	}
+
+	twinInstances, errs := pkg.NewInventoryBuilder(dtdlGraph, resourceBuilder).CreateTwinInstances(inventory, mapping)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var generatedFiles []GeneratedFile
+	for _, twinInterface := range dtdlGraph.GetTopologicalOrder() {
+		interfaceTwinInstances, ok := twinInstances[twinInterface.Spec.Id]
+		if !ok {
+			continue
+		}
+
+		generatedFiles = append(generatedFiles, GeneratedFile{
+			OutputFilePath: filepath.Join(outputFolderPath, twinInterface.Spec.Id+".yaml"),
+			TwinInstances:  interfaceTwinInstances,
+		})
+	}
+
+	return generatedFiles, nil
+}
+
+// Generate one file for each twin interface with its twin instances of the instance graph. The files are sorted by
+// the topological order of the twin interfaces.
+func generateFiles(processedFiles []ProcessedFile, dtdlGraph graph.TwinInterfaceGraph, instanceGraph graph.TwinInstanceGraph, resourceBuilder pkg.ResourceBuilder) []GeneratedFile {
+	twinInstancesByInterface := map[string][]v0.TwinInstance{}
+	for _, twinInstance := range instanceGraph.GetTwinInstances() {
+		twinInstancesByInterface[twinInstance.Spec.Interface] = append(twinInstancesByInterface[twinInstance.Spec.Interface], twinInstance)
+	}
+
+	processedFilesByInterface := map[string]ProcessedFile{}
+	for _, processedFile := range processedFiles {
+		processedFilesByInterface[processedFile.TwinInterfaceId] = processedFile
+	}
+
+	var generatedFiles []GeneratedFile
+	for _, sortedTwinInterface := range dtdlGraph.GetTopologicalOrder() {
+
+		twinInterface := dtdlGraph.GetVertex(sortedTwinInterface.Spec.Id)
+		processedFile, ok := processedFilesByInterface[twinInterface.Spec.Id]
+
+		if !ok {
+			fmt.Fprintf(progress, "Twin Interface {%s} not found\n", twinInterface.Spec.Id)
+			continue
+		}
+
+		parentTwinInterfaces := getParentTwinInterfaces(*twinInterface, dtdlGraph)
+		componentTwinInterfaces := getComponentTwinInterfaces(parentTwinInterfaces, dtdlGraph)
+
+		var twinInstances []v0.TwinInstance
+		for _, twinInstance := range twinInstancesByInterface[twinInterface.Spec.Id] {
+			twinInstances = append(twinInstances, resourceBuilder.CreateTwinInstance(twinInstance, parentTwinInterfaces, componentTwinInterfaces))
+		}
+
+		generatedTwinInterface := *twinInterface
+		generatedFiles = append(generatedFiles, GeneratedFile{
+			OutputFilePath: processedFile.outputFilePath,
+			TwinInterface:  &generatedTwinInterface,
+			TwinInstances:  twinInstances,
+		})
+	}
+
+	return generatedFiles
+}
+
+// Return a list of TwinInterfaces that contains the TwinInterface being processed and all the parent TwinInterfaces,
+// each TwinInterface before the ones it extends. Parents shared by more than one inheritance path are listed once.
+func getParentTwinInterfaces(twinInterface v0.TwinInterface, dtdlGraph graph.TwinInterfaceGraph) []v0.TwinInterface {
+	parentTwinInterfaces := dtdlGraph.GetExtendsChain(twinInterface.Spec.Id)
+
+	if parentTwinInterfaces == nil {
+		return []v0.TwinInterface{twinInterface}
+	}
+
+	return parentTwinInterfaces
+}
+
+// Return an error for each inheritance cycle and each content inherited with the same name from TwinInterfaces that
+// do not extend one another, located in the file of the TwinInterface whose inheritance chain has them
+func getInheritanceErrors(processedFiles []ProcessedFile, dtdlGraph graph.TwinInterfaceGraph) []error {
+	var errs []error
+
+	for _, processedFile := range processedFiles {
+		if cycle := dtdlGraph.GetExtendsCycle(processedFile.TwinInterfaceId); cycle != nil {
+			errs = append(errs, &dtdl.ParseError{
+				FilePath:    processedFile.InputFilePath,
+				InterfaceId: processedFile.DTMI,
+				Pointer:     "/extends",
+				Err:         fmt.Errorf("%w: %s", dtdl.ErrInheritanceCycle, strings.Join(cycle, " -> ")),
+			})
+		}
+
+		for _, conflict := range inheritance.GetInheritanceConflicts(dtdlGraph.GetExtendsChain(processedFile.TwinInterfaceId)) {
+			errs = append(errs, &dtdl.ParseError{
+				FilePath:    processedFile.InputFilePath,
+				InterfaceId: processedFile.DTMI,
+				Pointer:     "/contents",
+				Err:         fmt.Errorf("%w: %s", dtdl.ErrInheritanceConflict, conflict),
+			})
+		}
+	}
+
+	return errs
+}
+
+// Return the TwinInterface chain of each component of the TwinInterfaces, keyed by the component name
+func getComponentTwinInterfaces(twinInterfaces []v0.TwinInterface, dtdlGraph graph.TwinInterfaceGraph) map[string][]v0.TwinInterface {
+	componentTwinInterfaces := map[string][]v0.TwinInterface{}
+
+	for _, twinInterface := range twinInterfaces {
+		for _, component := range twinInterface.Spec.Components {
+			componentInterface := dtdlGraph.GetVertex(component.Interface)
+
+			if componentInterface == nil {
+				fmt.Fprintf(progress, "Twin Interface {%s} of component {%s} not found\n", component.Interface, component.Name)
+				continue
+			}
+
+			componentTwinInterfaces[component.Name] = getParentTwinInterfaces(*componentInterface, dtdlGraph)
+		}
+	}
+
+	return componentTwinInterfaces
+}
+
+// Write the TwinInterface file of the generated file, when it has a TwinInterface, and its TwinInstances file
+func writeGeneratedFile(generatedFile GeneratedFile, format pkg.OutputFormat) error {
+	if generatedFile.TwinInterface != nil {
+		interfaceFilePath := getOutputFilePath(generatedFile.OutputFilePath, "01-", "-interface", format)
+		if err := writeManifestsFile(interfaceFilePath, []client.Object{generatedFile.TwinInterface}, format); err != nil {
+			return err
+		}
+	}
+
+	var twinInstances []client.Object
+	for index := range generatedFile.TwinInstances {
+		twinInstances = append(twinInstances, &generatedFile.TwinInstances[index])
+	}
+
+	instanceFilePath := getOutputFilePath(generatedFile.OutputFilePath, "02-", "-instances", format)
+	return writeManifestsFile(instanceFilePath, twinInstances, format)
+}
+
+// Write all the generated resources in a single file of the output folder, with a kustomization file that lists it
+func writeBundleFiles(outputFolderPath string, generatedFiles []GeneratedFile, format pkg.OutputFormat, namespace string) error {
+	bundleFileName := BUNDLE_FILE_NAME + format.Extension()
+
+	if err := writeManifestsFile(filepath.Join(outputFolderPath, bundleFileName), getGeneratedObjects(generatedFiles), format); err != nil {
+		return err
+	}
+
+	return writeOutputFile(filepath.Join(outputFolderPath, pkg.KUSTOMIZATION_FILE_NAME), pkg.CreateKustomization([]string{bundleFileName}, namespace))
+}
+
+// Return the path of the file generated for the output file path, with the prefix and suffix added to its name and
+// the extension of the format
+func getOutputFilePath(outputFilePath string, prefix string, suffix string, format pkg.OutputFormat) string {
+	outputFilePath = strings.TrimSuffix(outputFilePath, filepath.Ext(outputFilePath)) + format.Extension()
+	return pkg.AddSuffixToFileName(outputFilePath, prefix, suffix)
+}
+
+func writeManifestsFile(outputFilePath string, objects []client.Object, format pkg.OutputFormat) error {
+	content, err := pkg.EncodeManifests(objects, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", outputFilePath, err)
+	}
+
+	return writeOutputFile(outputFilePath, content)
+}
+
+// Write the file, creating its folder and the missing parent folders
+func writeOutputFile(outputFilePath string, content []byte) error {
+	fmt.Fprintln(progress, "Writing output file "+outputFilePath)
+
+	if err := os.MkdirAll(filepath.Dir(outputFilePath), os.ModePerm); err != nil {
+		return err
+	}
+
+	return pkg.WriteToFile(outputFilePath, content)
+}
+
+func updateGraph(dtdlGraph graph.TwinInterfaceGraph, twinInterface v0.TwinInterface) graph.TwinInterfaceGraph {
+	dtdlGraph.AddVertex(twinInterface)
+
+	for _, relationship := range twinInterface.Spec.Relationships {
+		tInterface := v0.TwinInterface{
+			Spec: v0.TwinInterfaceSpec{
+				Id: relationship.Interface,
+			},
+		}
+		dtdlGraph.AddEdge(twinInterface, tInterface)
+	}
+
+	return dtdlGraph
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dtdl "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/dtdl"
+	pkg "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/pkg"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/graph"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPoleDTDL = `{
+	"@context": "dtmi:dtdl:context;2",
+	"@id": "dtmi:city:Pole;1",
+	"@type": "Interface",
+	"displayName": "Pole",
+	"contents": [
+		{"@type": "Property", "name": "height", "schema": "double"}
+	]
+}`
+
+// Write the files of a test input folder, keyed by their path relative to the folder
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	folderPath := t.TempDir()
+
+	for filePath, content := range files {
+		filePath = filepath.Join(folderPath, filePath)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.Nil(t, os.WriteFile(filePath, []byte(content), 0644))
+	}
+
+	return folderPath
+}
+
+func TestGenerateResources_ConfigFile(t *testing.T) {
+	progress = io.Discard
+
+	inputFolderPath := writeTestFiles(t, map[string]string{"pole.json": testPoleDTDL})
+	configFolderPath := writeTestFiles(t, map[string]string{"config.yaml": `
+twinInterfaces:
+- dtmi: "dtmi:city:*"
+  service:
+    image: registry.local/ktwin-{id}:1.0
+  eventStore:
+    persistRealEvent: true
+`})
+
+	generatedFiles, ok := generateResources(&GenerateOptions{
+		InputFolderPath: inputFolderPath,
+		Namespace:       "city",
+		ConfigFile:      filepath.Join(configFolderPath, "config.yaml"),
+	}, "")
+
+	assert.True(t, ok)
+	assert.Len(t, generatedFiles, 1)
+
+	twinInterface := generatedFiles[0].TwinInterface
+	assert.Equal(t, "registry.local/ktwin-dtmi-city-pole-1:1.0", twinInterface.Spec.Service.Template.Spec.Containers[0].Image)
+	assert.True(t, twinInterface.Spec.EventStore.PersistRealEvent)
+
+	assert.Len(t, generatedFiles[0].TwinInstances, 1)
+	assert.Equal(t, "city", generatedFiles[0].TwinInstances[0].Namespace)
+	assert.Equal(t, "height", generatedFiles[0].TwinInstances[0].Spec.Data.Properties[0].Name)
+}
+
+func TestGenerateResources_OutputFilePaths(t *testing.T) {
+	progress = io.Discard
+
+	inputFolderPath := writeTestFiles(t, map[string]string{filepath.Join("city", "city.pole.json"): testPoleDTDL})
+	outputFolderPath := t.TempDir()
+
+	generatedFiles, ok := generateResources(&GenerateOptions{InputFolderPath: inputFolderPath, Namespace: "city"}, outputFolderPath)
+
+	assert.True(t, ok)
+	assert.Len(t, generatedFiles, 1)
+	assert.Equal(t, filepath.Join(outputFolderPath, "city", "city.pole.yaml"), generatedFiles[0].OutputFilePath)
+	assert.Equal(t, filepath.Join(outputFolderPath, "city", "01-city.pole-interface.json"), getOutputFilePath(generatedFiles[0].OutputFilePath, "01-", "-interface", pkg.OutputFormatJSON))
+}
+
+// Return the processed file of an interface, named as the resource generated from its DTMI
+func newProcessedFile(inputFilePath string, dtmi string) ProcessedFile {
+	twinInterface := pkg.NewResourceBuilder().CreateTwinInterface(dtdl.Interface{Id: dtdl.DTMI(dtmi)})
+
+	return ProcessedFile{InputFilePath: inputFilePath, TwinInterfaceId: twinInterface.Spec.Id, DTMI: dtmi}
+}
+
+func TestGetResourceNameCollisions(t *testing.T) {
+	tests := []struct {
+		name           string
+		processedFiles []ProcessedFile
+		expected       []error
+		expectedFiles  []string
+	}{
+		{
+			name: "Distinct interfaces and major versions",
+			processedFiles: []ProcessedFile{
+				newProcessedFile("pole.json", "dtmi:city:Pole;1"),
+				newProcessedFile("pole-v2.json", "dtmi:city:Pole;2"),
+				newProcessedFile("light.json", "dtmi:city:Light;1"),
+			},
+		},
+		{
+			name: "Interface declared more than once",
+			processedFiles: []ProcessedFile{
+				newProcessedFile("pole.json", "dtmi:city:Pole;1"),
+				newProcessedFile("copy/pole.json", "dtmi:city:Pole;1"),
+			},
+			expected:      []error{dtdl.ErrDuplicateInterface},
+			expectedFiles: []string{"copy/pole.json"},
+		},
+		{
+			name: "DTMIs with the same resource name",
+			processedFiles: []ProcessedFile{
+				newProcessedFile("pole.json", "dtmi:city:Pole;1"),
+				newProcessedFile("city-pole.json", "dtmi:city_Pole;1"),
+				newProcessedFile("pole-case.json", "dtmi:City:pole;1"),
+			},
+			expected:      []error{dtdl.ErrResourceNameCollision, dtdl.ErrResourceNameCollision},
+			expectedFiles: []string{"city-pole.json", "pole-case.json"},
+		},
+		{
+			name: "Minor versions of the same major version",
+			processedFiles: []ProcessedFile{
+				newProcessedFile("pole.json", "dtmi:city:Pole;1.1"),
+				newProcessedFile("pole-minor.json", "dtmi:city:Pole;1.2"),
+			},
+			expected:      []error{dtdl.ErrModelVersionCollision},
+			expectedFiles: []string{"pole-minor.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := getResourceNameCollisions(tt.processedFiles)
+			assert.Equal(t, len(tt.expected), len(errs), errs)
+
+			for index, err := range errs {
+				if index < len(tt.expected) {
+					assert.True(t, errors.Is(err, tt.expected[index]), err.Error())
+
+					parseError, ok := err.(*dtdl.ParseError)
+					assert.True(t, ok, err.Error())
+					assert.Equal(t, tt.expectedFiles[index], parseError.FilePath)
+					assert.Equal(t, "/@id", parseError.Pointer)
+				}
+			}
+		})
+	}
+}
+
+func TestGetInheritanceErrors(t *testing.T) {
+	progress = io.Discard
+
+	tests := []struct {
+		name          string
+		files         map[string]string
+		expected      []error
+		expectedFiles []string
+	}{
+		{
+			name: "Interface extending another interface",
+			files: map[string]string{
+				"pole.json": testPoleDTDL,
+				"light.json": `{
+					"@context": "dtmi:dtdl:context;2",
+					"@id": "dtmi:city:Light;1",
+					"@type": "Interface",
+					"extends": "dtmi:city:Pole;1",
+					"contents": [{"@type": "Property", "name": "height", "schema": "double"}]
+				}`,
+			},
+		},
+		{
+			name: "Inheritance cycle",
+			files: map[string]string{
+				"bus.json":  `{"@context": "dtmi:dtdl:context;2", "@id": "dtmi:city:Bus;1", "@type": "Interface", "extends": "dtmi:city:Tram;1"}`,
+				"tram.json": `{"@context": "dtmi:dtdl:context;2", "@id": "dtmi:city:Tram;1", "@type": "Interface", "extends": "dtmi:city:Bus;1"}`,
+			},
+			expected:      []error{dtdl.ErrInheritanceCycle, dtdl.ErrInheritanceCycle},
+			expectedFiles: []string{"bus.json", "tram.json"},
+		},
+		{
+			name: "Contents inherited from unrelated interfaces",
+			files: map[string]string{
+				"pole.json": testPoleDTDL,
+				"lamp.json": `{
+					"@context": "dtmi:dtdl:context;2",
+					"@id": "dtmi:city:Lamp;1",
+					"@type": "Interface",
+					"contents": [{"@type": "Property", "name": "height", "schema": "double"}]
+				}`,
+				"streetlight.json": `{
+					"@context": "dtmi:dtdl:context;2",
+					"@id": "dtmi:city:Streetlight;1",
+					"@type": "Interface",
+					"extends": ["dtmi:city:Pole;1", "dtmi:city:Lamp;1"]
+				}`,
+			},
+			expected:      []error{dtdl.ErrInheritanceConflict},
+			expectedFiles: []string{"streetlight.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFolderPath := writeTestFiles(t, tt.files)
+
+			dtdlGraph, processedFiles, parseErrors := processAllFilesInFolder(inputFolderPath, "", pkg.NewResourceBuilder(), graph.NewTwinInterfaceGraph(), []ProcessedFile{}, nil)
+			assert.Empty(t, parseErrors)
+
+			errs := getInheritanceErrors(processedFiles, dtdlGraph)
+			assert.Equal(t, len(tt.expected), len(errs), errs)
+
+			for index, err := range errs {
+				if index < len(tt.expected) {
+					assert.True(t, errors.Is(err, tt.expected[index]), err.Error())
+
+					parseError, ok := err.(*dtdl.ParseError)
+					assert.True(t, ok, err.Error())
+					assert.Equal(t, filepath.Join(inputFolderPath, tt.expectedFiles[index]), parseError.FilePath)
+				}
+			}
+		})
+	}
+}
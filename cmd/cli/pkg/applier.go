@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"context"
+
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/apply"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// The result of applying a generated object
+type ApplyResult struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Result    controllerutil.OperationResult
+}
+
+// Apply generated TwinInterfaces and TwinInstances to a cluster with server-side apply. Objects are applied in
+// order, so TwinInterfaces must come before the TwinInstances that implement them.
+type Applier interface {
+	Apply(ctx context.Context, desiredObjects []client.Object) ([]ApplyResult, error)
+}
+
+func NewApplier(c client.Client) Applier {
+	return &applier{
+		client: c,
+		differ: NewClusterDiffer(c),
+	}
+}
+
+type applier struct {
+	client client.Client
+	differ Differ
+}
+
+// Objects already matching the generated ones are not applied, so their resource version is kept. The results of
+// the objects applied before an error are returned with it.
+func (a *applier) Apply(ctx context.Context, desiredObjects []client.Object) ([]ApplyResult, error) {
+	var applyResults []ApplyResult
+
+	for _, desiredObject := range desiredObjects {
+		objectDiffs, err := a.differ.Diff(ctx, []client.Object{desiredObject})
+		if err != nil {
+			return applyResults, err
+		}
+
+		applyResult := ApplyResult{
+			Kind:      objectDiffs[0].Kind,
+			Namespace: desiredObject.GetNamespace(),
+			Name:      desiredObject.GetName(),
+			Result:    controllerutil.OperationResultNone,
+		}
+
+		if objectDiffs[0].Action != DiffActionUnchanged {
+			applyResult.Result, err = apply.Apply(ctx, a.client, desiredObject)
+			if err != nil {
+				return applyResults, err
+			}
+		}
+
+		applyResults = append(applyResults, applyResult)
+	}
+
+	return applyResults, nil
+}
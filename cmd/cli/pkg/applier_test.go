@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// The fake client does not create objects with apply patches, as the API server does, so they are created instead
+func newFakeApplyClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	apiv0.AddToScheme(scheme)
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() == types.ApplyPatchType {
+				err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopyObject().(client.Object))
+				if apierrors.IsNotFound(err) {
+					return c.Create(ctx, obj)
+				}
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+}
+
+func TestApplier_Apply(t *testing.T) {
+	fakeClient := newFakeApplyClient(
+		newDiffTwinInterface("pole", "Pole", nil),
+		newDiffTwinInstance("pole-001", "pole"),
+		newDiffTwinInstance("pole-002", "streetlight"),
+	)
+
+	applyResults, err := NewApplier(fakeClient).Apply(context.Background(), []client.Object{
+		newDiffTwinInterface("pole", "Pole", nil),
+		newDiffTwinInstance("pole-001", "pole"),
+		newDiffTwinInstance("pole-002", "pole"),
+		newDiffTwinInstance("pole-003", "pole"),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ApplyResult{
+		{Kind: "TwinInterface", Namespace: "ktwin", Name: "pole", Result: controllerutil.OperationResultNone},
+		{Kind: "TwinInstance", Namespace: "ktwin", Name: "pole-001", Result: controllerutil.OperationResultNone},
+		{Kind: "TwinInstance", Namespace: "ktwin", Name: "pole-002", Result: controllerutil.OperationResultUpdated},
+		{Kind: "TwinInstance", Namespace: "ktwin", Name: "pole-003", Result: controllerutil.OperationResultCreated},
+	}, applyResults)
+
+	twinInstance := &apiv0.TwinInstance{}
+	assert.Nil(t, fakeClient.Get(context.Background(), client.ObjectKey{Namespace: "ktwin", Name: "pole-002"}, twinInstance))
+	assert.Equal(t, "pole", twinInstance.Spec.Interface)
+
+	assert.Nil(t, fakeClient.Get(context.Background(), client.ObjectKey{Namespace: "ktwin", Name: "pole-003"}, twinInstance))
+	assert.Equal(t, "pole", twinInstance.Spec.Interface)
+
+	objectDiffs, err := NewClusterDiffer(fakeClient).Diff(context.Background(), []client.Object{
+		newDiffTwinInterface("pole", "Pole", nil),
+		newDiffTwinInstance("pole-001", "pole"),
+		newDiffTwinInstance("pole-002", "pole"),
+		newDiffTwinInstance("pole-003", "pole"),
+	})
+
+	assert.Nil(t, err)
+	assert.False(t, HasDifferences(objectDiffs))
+}
+
+func TestApplier_Apply_Error(t *testing.T) {
+	scheme := runtime.NewScheme()
+	apiv0.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			return apierrors.NewForbidden(apiv0.GroupVersion.WithResource("twininstances").GroupResource(), obj.GetName(), nil)
+		},
+	}).Build()
+
+	applyResults, err := NewApplier(fakeClient).Apply(context.Background(), []client.Object{
+		newDiffTwinInstance("pole-001", "pole"),
+		newDiffTwinInstance("pole-002", "pole"),
+	})
+
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Empty(t, applyResults)
+}
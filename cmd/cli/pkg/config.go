@@ -0,0 +1,230 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdl "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/dtdl"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/naming"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// Placeholder of the image of the service, replaced by the TwinInterface name
+	IMAGE_ID_PLACEHOLDER = "{id}"
+)
+
+var (
+	ErrInvalidDTMIPattern = errors.New("Invalid DTMI pattern")
+)
+
+// The settings of the generated TwinInterfaces. Each TwinInterface gets the default settings, overridden by the
+// settings of the TwinInterfaces entries whose DTMI pattern matches its DTMI, in order, so later entries win.
+type CLIConfig struct {
+	TwinInterfaces []TwinInterfaceConfig `json:"twinInterfaces,omitempty"`
+}
+
+type TwinInterfaceConfig struct {
+	// Glob pattern, as in path.Match, matched against the DTMI of the TwinInterface. Patterns without a version
+	// also match the versions of the DTMI, so dtmi:city:Pole matches dtmi:city:Pole;1.
+	DTMI       string            `json:"dtmi"`
+	Service    *ServiceConfig    `json:"service,omitempty"`
+	EventStore *EventStoreConfig `json:"eventStore,omitempty"`
+}
+
+// The settings of the service of the TwinInterface. Settings not informed keep the value of the previous entries.
+type ServiceConfig struct {
+	// When false, the TwinInterface is generated without a service
+	Enabled *bool `json:"enabled,omitempty"`
+	// Container image, where {id} is replaced by the TwinInterface name
+	Image           *string                      `json:"image,omitempty"`
+	ImagePullPolicy *corev1.PullPolicy           `json:"imagePullPolicy,omitempty"`
+	Resources       *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Only the informed autoscaling settings are overridden
+	AutoScaling *apiv0.TwinInterfaceAutoScaling `json:"autoScaling,omitempty"`
+}
+
+type EventStoreConfig struct {
+	PersistRealEvent    *bool `json:"persistRealEvent,omitempty"`
+	PersistVirtualEvent *bool `json:"persistVirtualEvent,omitempty"`
+}
+
+// The service generated for the TwinInterfaces when no setting is overridden
+func NewDefaultServiceConfig() ServiceConfig {
+	return ServiceConfig{
+		Enabled:         newBoolPtr(true),
+		Image:           newStringPtr(naming.GetContainerRegistry("ktwin-" + IMAGE_ID_PLACEHOLDER + "-service:0.1")),
+		ImagePullPolicy: newPullPolicyPtr(corev1.PullIfNotPresent),
+		AutoScaling: &apiv0.TwinInterfaceAutoScaling{
+			MaxScale:                    newIntPtr(20),
+			Target:                      newIntPtr(10),
+			Parallelism:                 newIntPtr(100),
+			TargetUtilizationPercentage: newIntPtr(65),
+			Metric:                      "concurrency",
+		},
+	}
+}
+
+// Load a YAML or JSON CLI config file. Unknown fields and invalid DTMI patterns are errors.
+func LoadCLIConfig(content []byte) (CLIConfig, error) {
+	config := CLIConfig{}
+
+	jsonContent, err := yaml.ToJSON(content)
+	if err != nil {
+		return config, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonContent))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&config); err != nil {
+		return config, err
+	}
+
+	for index, twinInterfaceConfig := range config.TwinInterfaces {
+		if _, err := path.Match(twinInterfaceConfig.DTMI, ""); err != nil || twinInterfaceConfig.DTMI == "" {
+			return config, fmt.Errorf("twinInterfaces[%d]: %w %q", index, ErrInvalidDTMIPattern, twinInterfaceConfig.DTMI)
+		}
+	}
+
+	return config, nil
+}
+
+// Return the service of the TwinInterface with the informed DTMI and name, or nil when it is disabled
+func (c CLIConfig) GetTwinInterfaceService(dtmi string, twinInterfaceName string) *apiv0.TwinInterfaceService {
+	serviceConfig := NewDefaultServiceConfig()
+
+	for _, twinInterfaceConfig := range c.getMatchingConfigs(dtmi) {
+		if twinInterfaceConfig.Service != nil {
+			serviceConfig = mergeServiceConfig(serviceConfig, *twinInterfaceConfig.Service)
+		}
+	}
+
+	if !*serviceConfig.Enabled {
+		return nil
+	}
+
+	container := corev1.Container{
+		Name:            twinInterfaceName,
+		Image:           strings.ReplaceAll(*serviceConfig.Image, IMAGE_ID_PLACEHOLDER, twinInterfaceName),
+		ImagePullPolicy: *serviceConfig.ImagePullPolicy,
+	}
+
+	if serviceConfig.Resources != nil {
+		container.Resources = *serviceConfig.Resources
+	}
+
+	return &apiv0.TwinInterfaceService{
+		AutoScaling: *serviceConfig.AutoScaling,
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{container}},
+		},
+	}
+}
+
+// Return the event store settings of the TwinInterface with the informed DTMI. Events are not persisted by default.
+func (c CLIConfig) GetTwinInterfaceEventStore(dtmi string) apiv0.TwinInterfaceEventStore {
+	eventStore := apiv0.TwinInterfaceEventStore{}
+
+	for _, twinInterfaceConfig := range c.getMatchingConfigs(dtmi) {
+		if twinInterfaceConfig.EventStore == nil {
+			continue
+		}
+		if twinInterfaceConfig.EventStore.PersistRealEvent != nil {
+			eventStore.PersistRealEvent = *twinInterfaceConfig.EventStore.PersistRealEvent
+		}
+		if twinInterfaceConfig.EventStore.PersistVirtualEvent != nil {
+			eventStore.PersistVirtualEvent = *twinInterfaceConfig.EventStore.PersistVirtualEvent
+		}
+	}
+
+	return eventStore
+}
+
+func (c CLIConfig) getMatchingConfigs(dtmi string) []TwinInterfaceConfig {
+	var matchingConfigs []TwinInterfaceConfig
+
+	unversionedDTMI := dtmi
+	if parsedDTMI, err := dtdl.ParseDTMI(dtmi); err == nil {
+		unversionedDTMI = parsedDTMI.Unversioned()
+	}
+
+	for _, twinInterfaceConfig := range c.TwinInterfaces {
+		matches, _ := path.Match(twinInterfaceConfig.DTMI, dtmi)
+
+		if !matches && !strings.Contains(twinInterfaceConfig.DTMI, ";") {
+			matches, _ = path.Match(twinInterfaceConfig.DTMI, unversionedDTMI)
+		}
+
+		if matches {
+			matchingConfigs = append(matchingConfigs, twinInterfaceConfig)
+		}
+	}
+
+	return matchingConfigs
+}
+
+func mergeServiceConfig(serviceConfig ServiceConfig, override ServiceConfig) ServiceConfig {
+	if override.Enabled != nil {
+		serviceConfig.Enabled = override.Enabled
+	}
+	if override.Image != nil {
+		serviceConfig.Image = override.Image
+	}
+	if override.ImagePullPolicy != nil {
+		serviceConfig.ImagePullPolicy = override.ImagePullPolicy
+	}
+	if override.Resources != nil {
+		serviceConfig.Resources = override.Resources
+	}
+
+	if override.AutoScaling != nil {
+		autoScaling := *serviceConfig.AutoScaling
+
+		if override.AutoScaling.MinScale != nil {
+			autoScaling.MinScale = override.AutoScaling.MinScale
+		}
+		if override.AutoScaling.MaxScale != nil {
+			autoScaling.MaxScale = override.AutoScaling.MaxScale
+		}
+		if override.AutoScaling.Target != nil {
+			autoScaling.Target = override.AutoScaling.Target
+		}
+		if override.AutoScaling.TargetUtilizationPercentage != nil {
+			autoScaling.TargetUtilizationPercentage = override.AutoScaling.TargetUtilizationPercentage
+		}
+		if override.AutoScaling.Parallelism != nil {
+			autoScaling.Parallelism = override.AutoScaling.Parallelism
+		}
+		if override.AutoScaling.Metric != "" {
+			autoScaling.Metric = override.AutoScaling.Metric
+		}
+
+		serviceConfig.AutoScaling = &autoScaling
+	}
+
+	return serviceConfig
+}
+
+func newBoolPtr(value bool) *bool {
+	return &value
+}
+
+func newIntPtr(value int) *int {
+	return &value
+}
+
+func newStringPtr(value string) *string {
+	return &value
+}
+
+func newPullPolicyPtr(value corev1.PullPolicy) *corev1.PullPolicy {
+	return &value
+}
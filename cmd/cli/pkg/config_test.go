@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/naming"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const testCLIConfig = `
+twinInterfaces:
+- dtmi: "dtmi:digitaltwins:ngsi_ld:city:*"
+  service:
+    image: registry.local/ktwin-{id}:1.0
+    imagePullPolicy: Always
+    autoScaling:
+      minScale: 1
+      maxScale: 5
+  eventStore:
+    persistRealEvent: true
+- dtmi: "dtmi:digitaltwins:ngsi_ld:city:ParkingSpot"
+  service:
+    enabled: false
+  eventStore:
+    persistVirtualEvent: true
+- dtmi: "dtmi:digitaltwins:ngsi_ld:city:Streetlight;2"
+  service:
+    resources:
+      limits:
+        cpu: 500m
+`
+
+func TestLoadCLIConfig(t *testing.T) {
+	tests := []struct {
+		name                   string
+		content                string
+		expectedTwinInterfaces int
+		expectedError          error
+	}{
+		{name: "YAML config", content: testCLIConfig, expectedTwinInterfaces: 3},
+		{name: "JSON config", content: `{"twinInterfaces": [{"dtmi": "dtmi:city:*", "service": {"enabled": false}}]}`, expectedTwinInterfaces: 1},
+		{name: "Empty config", content: ``, expectedTwinInterfaces: 0},
+		{name: "Invalid DTMI pattern", content: `{"twinInterfaces": [{"dtmi": "dtmi:city:[Pole"}]}`, expectedError: ErrInvalidDTMIPattern},
+		{name: "Missing DTMI pattern", content: `{"twinInterfaces": [{"service": {"enabled": false}}]}`, expectedError: ErrInvalidDTMIPattern},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadCLIConfig([]byte(tt.content))
+
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, config.TwinInterfaces, tt.expectedTwinInterfaces)
+		})
+	}
+
+	t.Run("Unknown fields", func(t *testing.T) {
+		_, err := LoadCLIConfig([]byte(`{"twinInterfaces": [{"dtmi": "dtmi:city:*", "services": {}}]}`))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCLIConfig_GetTwinInterfaceService(t *testing.T) {
+	config, err := LoadCLIConfig([]byte(testCLIConfig))
+	assert.Nil(t, err)
+
+	defaultService := &apiv0.TwinInterfaceService{
+		AutoScaling: apiv0.TwinInterfaceAutoScaling{
+			MaxScale:                    newIntPtr(20),
+			Target:                      newIntPtr(10),
+			Parallelism:                 newIntPtr(100),
+			TargetUtilizationPercentage: newIntPtr(65),
+			Metric:                      "concurrency",
+		},
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{
+					Name:            "dtmi-digitaltwins-city-pole-1",
+					Image:           naming.GetContainerRegistry("ktwin-dtmi-digitaltwins-city-pole-1-service:0.1"),
+					ImagePullPolicy: corev1.PullIfNotPresent,
+				},
+			}},
+		},
+	}
+
+	cityService := &apiv0.TwinInterfaceService{
+		AutoScaling: apiv0.TwinInterfaceAutoScaling{
+			MinScale:                    newIntPtr(1),
+			MaxScale:                    newIntPtr(5),
+			Target:                      newIntPtr(10),
+			Parallelism:                 newIntPtr(100),
+			TargetUtilizationPercentage: newIntPtr(65),
+			Metric:                      "concurrency",
+		},
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{
+					Name:            "dtmi-digitaltwins-ngsi-ld-city-streetlight-1",
+					Image:           "registry.local/ktwin-dtmi-digitaltwins-ngsi-ld-city-streetlight-1:1.0",
+					ImagePullPolicy: corev1.PullAlways,
+				},
+			}},
+		},
+	}
+
+	resourcesService := cityService.DeepCopy()
+	resourcesService.Template.Spec.Containers[0].Name = "dtmi-digitaltwins-ngsi-ld-city-streetlight-2"
+	resourcesService.Template.Spec.Containers[0].Image = "registry.local/ktwin-dtmi-digitaltwins-ngsi-ld-city-streetlight-2:1.0"
+	resourcesService.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	}
+
+	tests := []struct {
+		name            string
+		config          CLIConfig
+		dtmi            string
+		resourceName    string
+		expectedService *apiv0.TwinInterfaceService
+	}{
+		{
+			name:            "Default service without config",
+			config:          CLIConfig{},
+			dtmi:            "dtmi:digitaltwins:city:Pole;1",
+			resourceName:    "dtmi-digitaltwins-city-pole-1",
+			expectedService: defaultService,
+		},
+		{
+			name:            "Default service of interfaces not matched",
+			config:          config,
+			dtmi:            "dtmi:digitaltwins:city:Pole;1",
+			resourceName:    "dtmi-digitaltwins-city-pole-1",
+			expectedService: defaultService,
+		},
+		{
+			name:            "Overridden image and autoscaling settings",
+			config:          config,
+			dtmi:            "dtmi:digitaltwins:ngsi_ld:city:Streetlight;1",
+			resourceName:    "dtmi-digitaltwins-ngsi-ld-city-streetlight-1",
+			expectedService: cityService,
+		},
+		{
+			name:            "Later entries add settings",
+			config:          config,
+			dtmi:            "dtmi:digitaltwins:ngsi_ld:city:Streetlight;2",
+			resourceName:    "dtmi-digitaltwins-ngsi-ld-city-streetlight-2",
+			expectedService: resourcesService,
+		},
+		{
+			name:            "Disabled service of an unversioned pattern",
+			config:          config,
+			dtmi:            "dtmi:digitaltwins:ngsi_ld:city:ParkingSpot;1",
+			resourceName:    "dtmi-digitaltwins-ngsi-ld-city-parkingspot-1",
+			expectedService: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedService, tt.config.GetTwinInterfaceService(tt.dtmi, tt.resourceName))
+		})
+	}
+}
+
+func TestCLIConfig_GetTwinInterfaceEventStore(t *testing.T) {
+	config, err := LoadCLIConfig([]byte(testCLIConfig))
+	assert.Nil(t, err)
+
+	tests := []struct {
+		dtmi               string
+		expectedEventStore apiv0.TwinInterfaceEventStore
+	}{
+		{dtmi: "dtmi:digitaltwins:city:Pole;1", expectedEventStore: apiv0.TwinInterfaceEventStore{}},
+		{dtmi: "dtmi:digitaltwins:ngsi_ld:city:Streetlight;1", expectedEventStore: apiv0.TwinInterfaceEventStore{PersistRealEvent: true}},
+		{dtmi: "dtmi:digitaltwins:ngsi_ld:city:ParkingSpot;1", expectedEventStore: apiv0.TwinInterfaceEventStore{PersistRealEvent: true, PersistVirtualEvent: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dtmi, func(t *testing.T) {
+			assert.Equal(t, tt.expectedEventStore, config.GetTwinInterfaceEventStore(tt.dtmi))
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type DiffAction string
+
+const (
+	DiffActionCreate    DiffAction = "create"
+	DiffActionUpdate    DiffAction = "update"
+	DiffActionUnchanged DiffAction = "unchanged"
+)
+
+// A field whose generated value is different from the current one. Missing values are nil.
+type FieldDiff struct {
+	Path         string
+	DesiredValue interface{}
+	CurrentValue interface{}
+}
+
+// The differences between a generated object and its current state
+type ObjectDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Action    DiffAction
+	Fields    []FieldDiff
+}
+
+func (d ObjectDiff) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%s/%s %s", strings.ToLower(d.Kind), d.Name, d.Action)
+
+	for _, field := range d.Fields {
+		if field.CurrentValue != nil {
+			fmt.Fprintf(&builder, "\n  - %s: %s", field.Path, formatDiffValue(field.CurrentValue))
+		}
+		if field.DesiredValue != nil {
+			fmt.Fprintf(&builder, "\n  + %s: %s", field.Path, formatDiffValue(field.DesiredValue))
+		}
+	}
+
+	return builder.String()
+}
+
+func formatDiffValue(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
+
+// Compare generated TwinInterfaces and TwinInstances with their current state. The spec is compared as a whole,
+// while only the labels and annotations set in the generated objects are compared, since other managers may add
+// their own metadata.
+type Differ interface {
+	Diff(ctx context.Context, desiredObjects []client.Object) ([]ObjectDiff, error)
+}
+
+// Compare the generated objects with the objects of the cluster
+func NewClusterDiffer(c client.Reader) Differ {
+	return &differ{
+		getCurrentObject: func(ctx context.Context, desiredObject client.Object) (client.Object, error) {
+			currentObject := desiredObject.DeepCopyObject().(client.Object)
+
+			err := c.Get(ctx, client.ObjectKeyFromObject(desiredObject), currentObject)
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			return currentObject, nil
+		},
+	}
+}
+
+// Compare the generated objects with previously generated objects, such as the ones loaded with LoadManifests
+func NewManifestDiffer(currentObjects []client.Object) Differ {
+	currentObjectsByKey := map[string]client.Object{}
+	for _, currentObject := range currentObjects {
+		currentObjectsByKey[getObjectDiffKey(currentObject)] = currentObject
+	}
+
+	return &differ{
+		getCurrentObject: func(ctx context.Context, desiredObject client.Object) (client.Object, error) {
+			return currentObjectsByKey[getObjectDiffKey(desiredObject)], nil
+		},
+	}
+}
+
+type differ struct {
+	// Return nil when the object does not exist yet
+	getCurrentObject func(ctx context.Context, desiredObject client.Object) (client.Object, error)
+}
+
+func (d *differ) Diff(ctx context.Context, desiredObjects []client.Object) ([]ObjectDiff, error) {
+	var objectDiffs []ObjectDiff
+
+	for _, desiredObject := range desiredObjects {
+		currentObject, err := d.getCurrentObject(ctx, desiredObject)
+		if err != nil {
+			return nil, err
+		}
+
+		objectDiff, err := DiffObject(desiredObject, currentObject)
+		if err != nil {
+			return nil, err
+		}
+
+		objectDiffs = append(objectDiffs, objectDiff)
+	}
+
+	return objectDiffs, nil
+}
+
+// Compare a generated object with its current state, which is nil when the object does not exist yet
+func DiffObject(desiredObject client.Object, currentObject client.Object) (ObjectDiff, error) {
+	objectDiff := ObjectDiff{
+		Kind:      getObjectKind(desiredObject),
+		Namespace: desiredObject.GetNamespace(),
+		Name:      desiredObject.GetName(),
+		Action:    DiffActionUnchanged,
+	}
+
+	if currentObject == nil {
+		objectDiff.Action = DiffActionCreate
+		return objectDiff, nil
+	}
+
+	desiredSpec, err := getObjectSpec(desiredObject)
+	if err != nil {
+		return objectDiff, err
+	}
+
+	currentSpec, err := getObjectSpec(currentObject)
+	if err != nil {
+		return objectDiff, err
+	}
+
+	objectDiff.Fields = append(objectDiff.Fields, diffMetadata("/metadata/labels", desiredObject.GetLabels(), currentObject.GetLabels())...)
+	objectDiff.Fields = append(objectDiff.Fields, diffMetadata("/metadata/annotations", desiredObject.GetAnnotations(), currentObject.GetAnnotations())...)
+	objectDiff.Fields = append(objectDiff.Fields, diffValues("/spec", desiredSpec, currentSpec)...)
+
+	if len(objectDiff.Fields) > 0 {
+		objectDiff.Action = DiffActionUpdate
+	}
+
+	return objectDiff, nil
+}
+
+func diffMetadata(path string, desiredMetadata map[string]string, currentMetadata map[string]string) []FieldDiff {
+	var fieldDiffs []FieldDiff
+
+	for _, key := range getSortedKeys(desiredMetadata) {
+		currentValue, ok := currentMetadata[key]
+		if ok && currentValue == desiredMetadata[key] {
+			continue
+		}
+
+		fieldDiff := FieldDiff{Path: path + "/" + escapePathSegment(key), DesiredValue: desiredMetadata[key]}
+		if ok {
+			fieldDiff.CurrentValue = currentValue
+		}
+		fieldDiffs = append(fieldDiffs, fieldDiff)
+	}
+
+	return fieldDiffs
+}
+
+// Compare JSON values, reporting the differences of objects by field and of arrays by index
+func diffValues(path string, desiredValue interface{}, currentValue interface{}) []FieldDiff {
+	desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+	currentMap, currentIsMap := currentValue.(map[string]interface{})
+
+	if desiredIsMap && currentIsMap {
+		var keys []string
+		for key := range desiredMap {
+			keys = append(keys, key)
+		}
+		for key := range currentMap {
+			if _, ok := desiredMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		var fieldDiffs []FieldDiff
+		for _, key := range keys {
+			fieldDiffs = append(fieldDiffs, diffValues(path+"/"+escapePathSegment(key), desiredMap[key], currentMap[key])...)
+		}
+		return fieldDiffs
+	}
+
+	desiredArray, desiredIsArray := desiredValue.([]interface{})
+	currentArray, currentIsArray := currentValue.([]interface{})
+
+	if desiredIsArray && currentIsArray {
+		var fieldDiffs []FieldDiff
+		for index := 0; index < len(desiredArray) || index < len(currentArray); index++ {
+			var desiredItem, currentItem interface{}
+			if index < len(desiredArray) {
+				desiredItem = desiredArray[index]
+			}
+			if index < len(currentArray) {
+				currentItem = currentArray[index]
+			}
+			fieldDiffs = append(fieldDiffs, diffValues(fmt.Sprintf("%s/%d", path, index), desiredItem, currentItem)...)
+		}
+		return fieldDiffs
+	}
+
+	if reflect.DeepEqual(desiredValue, currentValue) {
+		return nil
+	}
+
+	return []FieldDiff{{Path: path, DesiredValue: desiredValue, CurrentValue: currentValue}}
+}
+
+// Return the spec of the object as a JSON value
+func getObjectSpec(object client.Object) (interface{}, error) {
+	unstructuredObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return unstructuredObject["spec"], nil
+}
+
+func getObjectKind(object client.Object) string {
+	switch object.(type) {
+	case *apiv0.TwinInterface:
+		return "TwinInterface"
+	case *apiv0.TwinInstance:
+		return "TwinInstance"
+	}
+
+	return object.GetObjectKind().GroupVersionKind().Kind
+}
+
+func getObjectDiffKey(object client.Object) string {
+	return getObjectKind(object) + "/" + object.GetNamespace() + "/" + object.GetName()
+}
+
+func escapePathSegment(segment string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+}
+
+// Return true when any of the objects would be created or updated
+func HasDifferences(objectDiffs []ObjectDiff) bool {
+	for _, objectDiff := range objectDiffs {
+		if objectDiff.Action != DiffActionUnchanged {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,221 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	apiv0.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func newDiffTwinInterface(name string, displayName string, annotations map[string]string) *apiv0.TwinInterface {
+	return &apiv0.TwinInterface{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "TwinInterface",
+			APIVersion: "dtd.ktwin/v0",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name:        name,
+			Namespace:   "ktwin",
+			Annotations: annotations,
+		},
+		Spec: apiv0.TwinInterfaceSpec{
+			Id:          name,
+			DisplayName: displayName,
+			Properties: []apiv0.TwinProperty{
+				{Name: "temperature", Schema: &apiv0.TwinSchema{PrimitiveType: apiv0.Double}},
+			},
+		},
+	}
+}
+
+func newDiffTwinInstance(name string, twinInterface string, relationships ...apiv0.TwinInstanceRelationship) *apiv0.TwinInstance {
+	return &apiv0.TwinInstance{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "TwinInstance",
+			APIVersion: "dtd.ktwin/v0",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: "ktwin",
+		},
+		Spec: apiv0.TwinInstanceSpec{
+			Interface:                 twinInterface,
+			TwinInstanceRelationships: relationships,
+		},
+	}
+}
+
+func TestDiffObject(t *testing.T) {
+	tests := []struct {
+		name           string
+		desiredObject  client.Object
+		currentObject  client.Object
+		expectedAction DiffAction
+		expectedFields []FieldDiff
+	}{
+		{
+			name:           "Missing object is created",
+			desiredObject:  newDiffTwinInterface("pole", "Pole", nil),
+			currentObject:  nil,
+			expectedAction: DiffActionCreate,
+		},
+		{
+			name:           "Equal object is unchanged",
+			desiredObject:  newDiffTwinInterface("pole", "Pole", map[string]string{"ktwin/dtmi": "dtmi:city:Pole;1"}),
+			currentObject:  newDiffTwinInterface("pole", "Pole", map[string]string{"ktwin/dtmi": "dtmi:city:Pole;1"}),
+			expectedAction: DiffActionUnchanged,
+		},
+		{
+			name:           "Annotations of other managers are ignored",
+			desiredObject:  newDiffTwinInterface("pole", "Pole", map[string]string{"ktwin/dtmi": "dtmi:city:Pole;1"}),
+			currentObject:  newDiffTwinInterface("pole", "Pole", map[string]string{"ktwin/dtmi": "dtmi:city:Pole;1", "other": "value"}),
+			expectedAction: DiffActionUnchanged,
+		},
+		{
+			name:           "Changed annotation and spec field",
+			desiredObject:  newDiffTwinInterface("pole", "Pole", map[string]string{"ktwin/dtmi": "dtmi:city:Pole;2"}),
+			currentObject:  newDiffTwinInterface("pole", "Old Pole", map[string]string{"ktwin/dtmi": "dtmi:city:Pole;1"}),
+			expectedAction: DiffActionUpdate,
+			expectedFields: []FieldDiff{
+				{Path: "/metadata/annotations/ktwin~1dtmi", DesiredValue: "dtmi:city:Pole;2", CurrentValue: "dtmi:city:Pole;1"},
+				{Path: "/spec/displayName", DesiredValue: "Pole", CurrentValue: "Old Pole"},
+			},
+		},
+		{
+			name:           "Removed spec field",
+			desiredObject:  newDiffTwinInterface("pole", "", nil),
+			currentObject:  newDiffTwinInterface("pole", "Pole", nil),
+			expectedAction: DiffActionUpdate,
+			expectedFields: []FieldDiff{
+				{Path: "/spec/displayName", CurrentValue: "Pole"},
+			},
+		},
+		{
+			name: "Added and changed array items",
+			desiredObject: newDiffTwinInstance("pole-001", "pole",
+				apiv0.TwinInstanceRelationship{Name: "refStreetlight", Interface: "streetlight", Instance: "streetlight-002"},
+				apiv0.TwinInstanceRelationship{Name: "refStreetlight", Interface: "streetlight", Instance: "streetlight-003"},
+			),
+			currentObject: newDiffTwinInstance("pole-001", "pole",
+				apiv0.TwinInstanceRelationship{Name: "refStreetlight", Interface: "streetlight", Instance: "streetlight-001"},
+			),
+			expectedAction: DiffActionUpdate,
+			expectedFields: []FieldDiff{
+				{Path: "/spec/twinInstanceRelationships/0/instance", DesiredValue: "streetlight-002", CurrentValue: "streetlight-001"},
+				{Path: "/spec/twinInstanceRelationships/1", DesiredValue: map[string]interface{}{"name": "refStreetlight", "interface": "streetlight", "instance": "streetlight-003"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objectDiff, err := DiffObject(tt.desiredObject, tt.currentObject)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.desiredObject.GetName(), objectDiff.Name)
+			assert.Equal(t, tt.expectedAction, objectDiff.Action)
+			assert.Equal(t, tt.expectedFields, objectDiff.Fields)
+		})
+	}
+}
+
+func TestObjectDiff_String(t *testing.T) {
+	objectDiff := ObjectDiff{
+		Kind:   "TwinInterface",
+		Name:   "pole",
+		Action: DiffActionUpdate,
+		Fields: []FieldDiff{
+			{Path: "/spec/displayName", DesiredValue: "Pole", CurrentValue: "Old Pole"},
+			{Path: "/spec/description", CurrentValue: "A pole"},
+		},
+	}
+
+	expected := "twininterface/pole update\n" +
+		"  - /spec/displayName: \"Old Pole\"\n" +
+		"  + /spec/displayName: \"Pole\"\n" +
+		"  - /spec/description: \"A pole\""
+
+	assert.Equal(t, expected, objectDiff.String())
+}
+
+func TestClusterDiffer_Diff(t *testing.T) {
+	fakeClient := newFakeClient(
+		newDiffTwinInterface("pole", "Pole", nil),
+		newDiffTwinInstance("pole-001", "pole"),
+		newDiffTwinInstance("pole-002", "streetlight"),
+	)
+
+	objectDiffs, err := NewClusterDiffer(fakeClient).Diff(context.Background(), []client.Object{
+		newDiffTwinInterface("pole", "Pole", nil),
+		newDiffTwinInstance("pole-001", "pole"),
+		newDiffTwinInstance("pole-002", "pole"),
+		newDiffTwinInstance("pole-003", "pole"),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ObjectDiff{
+		{Kind: "TwinInterface", Namespace: "ktwin", Name: "pole", Action: DiffActionUnchanged},
+		{Kind: "TwinInstance", Namespace: "ktwin", Name: "pole-001", Action: DiffActionUnchanged},
+		{Kind: "TwinInstance", Namespace: "ktwin", Name: "pole-002", Action: DiffActionUpdate, Fields: []FieldDiff{
+			{Path: "/spec/interface", DesiredValue: "pole", CurrentValue: "streetlight"},
+		}},
+		{Kind: "TwinInstance", Namespace: "ktwin", Name: "pole-003", Action: DiffActionCreate},
+	}, objectDiffs)
+	assert.True(t, HasDifferences(objectDiffs))
+}
+
+func TestManifestDiffer_Diff(t *testing.T) {
+	currentObjects, err := LoadManifests("manifests.yaml", []byte(`
+apiVersion: dtd.ktwin/v0
+kind: TwinInterface
+metadata:
+  name: pole
+  namespace: ktwin
+spec:
+  id: pole
+  displayName: Pole
+  properties:
+  - name: temperature
+    schema:
+      primitiveType: double
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: pole
+  namespace: ktwin
+---
+apiVersion: dtd.ktwin/v1
+kind: TwinInstance
+metadata:
+  name: pole-001
+  namespace: ktwin
+spec:
+  interface: pole
+---
+`))
+	assert.Nil(t, err)
+	assert.Len(t, currentObjects, 2)
+
+	objectDiffs, err := NewManifestDiffer(currentObjects).Diff(context.Background(), []client.Object{
+		newDiffTwinInterface("pole", "Pole", nil),
+		newDiffTwinInstance("pole-001", "pole"),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, DiffActionUnchanged, objectDiffs[0].Action)
+	assert.Equal(t, DiffActionUnchanged, objectDiffs[1].Action)
+	assert.False(t, HasDifferences(objectDiffs))
+}
@@ -0,0 +1,448 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdl "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/dtdl"
+)
+
+// Version of the QuantitativeTypes extension added to the context of interfaces with semantic types or units
+const QUANTITATIVE_TYPES_EXTENSION_CONTEXT = dtdl.QUANTITATIVE_TYPES_EXTENSION + ";1"
+
+// Resource names generated from a DTMI, whose last segment is the major version
+var versionedNameRegexp = regexp.MustCompile(`^dtmi-(.+)-([1-9][0-9]*)$`)
+
+// DTDL v3 documents, with the fields in the order they are usually written
+
+type dtdlInterfaceDocument struct {
+	Context     interface{}   `json:"@context"`
+	Id          string        `json:"@id"`
+	Type        string        `json:"@type"`
+	DisplayName string        `json:"displayName,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Comment     string        `json:"comment,omitempty"`
+	Extends     interface{}   `json:"extends,omitempty"`
+	Contents    []interface{} `json:"contents,omitempty"`
+}
+
+type dtdlPropertyDocument struct {
+	Type        interface{} `json:"@type"`
+	Id          string      `json:"@id,omitempty"`
+	Name        string      `json:"name"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Comment     string      `json:"comment,omitempty"`
+	Schema      interface{} `json:"schema"`
+	Unit        string      `json:"unit,omitempty"`
+	Writable    bool        `json:"writable,omitempty"`
+}
+
+type dtdlRelationshipDocument struct {
+	Type            string                 `json:"@type"`
+	Id              string                 `json:"@id,omitempty"`
+	Name            string                 `json:"name"`
+	DisplayName     string                 `json:"displayName,omitempty"`
+	Description     string                 `json:"description,omitempty"`
+	Comment         string                 `json:"comment,omitempty"`
+	Target          string                 `json:"target,omitempty"`
+	MinMultiplicity int                    `json:"minMultiplicity,omitempty"`
+	MaxMultiplicity int                    `json:"maxMultiplicity,omitempty"`
+	Properties      []dtdlPropertyDocument `json:"properties,omitempty"`
+	Writable        bool                   `json:"writable,omitempty"`
+}
+
+type dtdlComponentDocument struct {
+	Type        string `json:"@type"`
+	Id          string `json:"@id,omitempty"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type dtdlCommandDocument struct {
+	Type        string                      `json:"@type"`
+	Id          string                      `json:"@id,omitempty"`
+	Name        string                      `json:"name"`
+	DisplayName string                      `json:"displayName,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Comment     string                      `json:"comment,omitempty"`
+	Request     *dtdlCommandPayloadDocument `json:"request,omitempty"`
+	Response    *dtdlCommandPayloadDocument `json:"response,omitempty"`
+}
+
+type dtdlCommandPayloadDocument struct {
+	Type        string      `json:"@type"`
+	Name        string      `json:"name"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema"`
+}
+
+type dtdlEnumDocument struct {
+	Type        string                  `json:"@type"`
+	ValueSchema string                  `json:"valueSchema"`
+	EnumValues  []dtdlEnumValueDocument `json:"enumValues"`
+}
+
+type dtdlEnumValueDocument struct {
+	Name        string      `json:"name"`
+	DisplayName string      `json:"displayName,omitempty"`
+	EnumValue   interface{} `json:"enumValue"`
+}
+
+type dtdlObjectDocument struct {
+	Type   string              `json:"@type"`
+	Fields []dtdlFieldDocument `json:"fields"`
+}
+
+type dtdlFieldDocument struct {
+	Name        string      `json:"name"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema"`
+}
+
+type dtdlArrayDocument struct {
+	Type          string      `json:"@type"`
+	ElementSchema interface{} `json:"elementSchema"`
+}
+
+type dtdlMapDocument struct {
+	Type     string             `json:"@type"`
+	MapKey   dtdlMapKeyDocument `json:"mapKey"`
+	MapValue dtdlFieldDocument  `json:"mapValue"`
+}
+
+type dtdlMapKeyDocument struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// A TwinInterface exported as a DTDL interface
+type ExportedInterface struct {
+	TwinInterfaceName string
+	DTMI              string
+	Content           []byte
+	// Parts of the interface that could not be exported as they were in DTDL, such as DTMIs reconstructed from
+	// resource names
+	Warnings []string
+}
+
+type DTDLExporter interface {
+	// Export the TwinInterfaces as DTDL v3 interfaces. The TwinInterfaces referenced by relationships, components and
+	// extends are identified by the DTMI of the exported TwinInterfaces.
+	ExportInterfaces(twinInterfaces []apiv0.TwinInterface) []ExportedInterface
+}
+
+func NewDTDLExporter() DTDLExporter {
+	return &dtdlExporter{}
+}
+
+type dtdlExporter struct{}
+
+// State of the export of one TwinInterface
+type interfaceExport struct {
+	dtmis                 map[string]string
+	usesQuantitativeTypes bool
+	warnings              []string
+}
+
+func (e *dtdlExporter) ExportInterfaces(twinInterfaces []apiv0.TwinInterface) []ExportedInterface {
+	dtmis := map[string]string{}
+
+	for _, twinInterface := range twinInterfaces {
+		if dtmi := GetTwinInterfaceModelDTMI(twinInterface); dtmi != "" {
+			dtmis[twinInterface.Name] = dtmi
+		}
+	}
+
+	var exportedInterfaces []ExportedInterface
+
+	for _, twinInterface := range twinInterfaces {
+		exportedInterfaces = append(exportedInterfaces, e.exportInterface(twinInterface, dtmis))
+	}
+
+	sort.Slice(exportedInterfaces, func(i, j int) bool {
+		return exportedInterfaces[i].TwinInterfaceName < exportedInterfaces[j].TwinInterfaceName
+	})
+
+	return exportedInterfaces
+}
+
+func (e *dtdlExporter) exportInterface(twinInterface apiv0.TwinInterface, dtmis map[string]string) ExportedInterface {
+	export := &interfaceExport{dtmis: dtmis}
+	spec := twinInterface.Spec
+
+	document := dtdlInterfaceDocument{
+		Id:          export.getDTMI(twinInterface.Name),
+		Type:        "Interface",
+		DisplayName: spec.DisplayName,
+		Description: spec.Description,
+		Comment:     spec.Comment,
+	}
+
+	var extends []string
+	for _, parentName := range spec.GetExtends() {
+		extends = append(extends, export.getDTMI(parentName))
+	}
+
+	if len(extends) == 1 {
+		document.Extends = extends[0]
+	} else if len(extends) > 1 {
+		document.Extends = extends
+	}
+
+	for _, property := range spec.Properties {
+		document.Contents = append(document.Contents, export.exportProperty(property))
+	}
+
+	for _, telemetry := range spec.Telemetries {
+		document.Contents = append(document.Contents, export.exportTelemetry(telemetry))
+	}
+
+	for _, command := range spec.Commands {
+		document.Contents = append(document.Contents, export.exportCommand(command))
+	}
+
+	for _, relationship := range spec.Relationships {
+		document.Contents = append(document.Contents, export.exportRelationship(relationship))
+	}
+
+	for _, component := range spec.Components {
+		document.Contents = append(document.Contents, dtdlComponentDocument{
+			Type:        dtdl.ContentComponentType,
+			Id:          component.Id,
+			Name:        component.Name,
+			DisplayName: component.DisplayName,
+			Description: component.Description,
+			Comment:     component.Comment,
+			Schema:      export.getDTMI(component.Interface),
+		})
+	}
+
+	document.Context = dtdl.DTDL_V3_CONTEXT
+	if export.usesQuantitativeTypes {
+		document.Context = []string{dtdl.DTDL_V3_CONTEXT, QUANTITATIVE_TYPES_EXTENSION_CONTEXT}
+	}
+
+	content, err := json.MarshalIndent(document, "", "    ")
+	if err != nil {
+		export.warnings = append(export.warnings, err.Error())
+	}
+
+	return ExportedInterface{
+		TwinInterfaceName: twinInterface.Name,
+		DTMI:              document.Id,
+		Content:           append(content, '\n'),
+		Warnings:          export.warnings,
+	}
+}
+
+func (e *interfaceExport) exportProperty(property apiv0.TwinProperty) dtdlPropertyDocument {
+	return dtdlPropertyDocument{
+		Type:        e.getContentType(dtdl.ContentPropertyType, property.SemanticTypes, property.Unit),
+		Id:          property.Id,
+		Name:        property.Name,
+		DisplayName: property.DisplayName,
+		Description: property.Description,
+		Comment:     property.Comment,
+		Schema:      e.exportSchema(property.Schema, property.Name),
+		Unit:        property.Unit,
+		Writable:    property.Writeable,
+	}
+}
+
+func (e *interfaceExport) exportTelemetry(telemetry apiv0.TwinTelemetry) dtdlPropertyDocument {
+	return dtdlPropertyDocument{
+		Type:        e.getContentType(dtdl.ContentTelemetryType, telemetry.SemanticTypes, telemetry.Unit),
+		Id:          telemetry.Id,
+		Name:        telemetry.Name,
+		DisplayName: telemetry.DisplayName,
+		Description: telemetry.Description,
+		Comment:     telemetry.Comment,
+		Schema:      e.exportSchema(telemetry.Schema, telemetry.Name),
+		Unit:        telemetry.Unit,
+	}
+}
+
+func (e *interfaceExport) exportCommand(command apiv0.TwinCommand) dtdlCommandDocument {
+	document := dtdlCommandDocument{
+		Type:        dtdl.ContentCommandType,
+		Id:          command.Id,
+		Name:        command.Name,
+		DisplayName: command.DisplayName,
+		Description: command.Description,
+		Comment:     command.Comment,
+	}
+
+	if command.Request.Schema != nil {
+		document.Request = &dtdlCommandPayloadDocument{
+			Type:        "CommandRequest",
+			Name:        e.getPayloadName(command.Request.Name, command.Name+"Request"),
+			DisplayName: command.Request.DisplayName,
+			Description: command.Request.Description,
+			Schema:      e.exportSchema(command.Request.Schema, command.Name),
+		}
+	}
+
+	if command.Response.Schema != nil {
+		document.Response = &dtdlCommandPayloadDocument{
+			Type:        "CommandResponse",
+			Name:        e.getPayloadName(command.Response.Name, command.Name+"Response"),
+			DisplayName: command.Response.DisplayName,
+			Description: command.Response.Description,
+			Schema:      e.exportSchema(command.Response.Schema, command.Name),
+		}
+	}
+
+	return document
+}
+
+func (e *interfaceExport) exportRelationship(relationship apiv0.TwinRelationship) dtdlRelationshipDocument {
+	document := dtdlRelationshipDocument{
+		Type:            dtdl.ContentRelationshipType,
+		Id:              relationship.Id,
+		Name:            relationship.Name,
+		DisplayName:     relationship.DisplayName,
+		Description:     relationship.Description,
+		Comment:         relationship.Comment,
+		MinMultiplicity: relationship.MinMultiplicity,
+		MaxMultiplicity: relationship.MaxMultiplicity,
+		Writable:        relationship.Writeable,
+	}
+
+	if relationship.Interface != "" {
+		document.Target = e.getDTMI(relationship.Interface)
+	}
+
+	for _, property := range relationship.Properties {
+		document.Properties = append(document.Properties, e.exportProperty(property))
+	}
+
+	return document
+}
+
+// Return the @type of a property or telemetry, which lists the semantic types after the content type
+func (e *interfaceExport) getContentType(contentType string, semanticTypes []string, unit string) interface{} {
+	if len(semanticTypes) == 0 && unit == "" {
+		return contentType
+	}
+
+	e.usesQuantitativeTypes = true
+
+	if len(semanticTypes) == 0 {
+		return contentType
+	}
+
+	return append([]string{contentType}, semanticTypes...)
+}
+
+func (e *interfaceExport) exportSchema(schema *apiv0.TwinSchema, contentName string) interface{} {
+	switch {
+	case schema == nil || (schema.PrimitiveType == "" && schema.EnumType == nil && schema.ComplexType == nil && schema.ArrayType == nil && schema.MapType == nil):
+		e.warnings = append(e.warnings, fmt.Sprintf("%s has no schema, exported as %s", contentName, apiv0.String))
+		return string(apiv0.String)
+	case schema.EnumType != nil:
+		document := dtdlEnumDocument{
+			Type:        dtdl.ENUM_SCHEMA_TYPE,
+			ValueSchema: string(schema.EnumType.ValueSchema),
+			EnumValues:  []dtdlEnumValueDocument{},
+		}
+		for _, enumValue := range schema.EnumType.EnumValues {
+			document.EnumValues = append(document.EnumValues, dtdlEnumValueDocument{
+				Name:        enumValue.Name,
+				DisplayName: enumValue.DisplayName,
+				EnumValue:   getEnumValue(schema.EnumType.ValueSchema, enumValue.EnumValue),
+			})
+		}
+		return document
+	case schema.ComplexType != nil:
+		document := dtdlObjectDocument{Type: dtdl.OBJECT_SCHEMA_TYPE, Fields: []dtdlFieldDocument{}}
+		for _, field := range schema.ComplexType.Fields {
+			document.Fields = append(document.Fields, dtdlFieldDocument{
+				Name:        field.Name,
+				DisplayName: field.DisplayName,
+				Description: field.Description,
+				Schema:      e.exportSchema(field.Schema, contentName+"."+field.Name),
+			})
+		}
+		return document
+	case schema.ArrayType != nil:
+		return dtdlArrayDocument{
+			Type:          dtdl.ARRAY_SCHEMA_TYPE,
+			ElementSchema: e.exportSchema(schema.ArrayType.ElementSchema, contentName),
+		}
+	case schema.MapType != nil:
+		return dtdlMapDocument{
+			Type:   dtdl.MAP_SCHEMA_TYPE,
+			MapKey: dtdlMapKeyDocument{Name: e.getPayloadName(schema.MapType.MapKey.Name, "key"), Schema: string(apiv0.String)},
+			MapValue: dtdlFieldDocument{
+				Name:   e.getPayloadName(schema.MapType.MapValue.Name, "value"),
+				Schema: e.exportSchema(schema.MapType.MapValue.Schema, contentName),
+			},
+		}
+	}
+
+	return string(schema.PrimitiveType)
+}
+
+func (e *interfaceExport) getPayloadName(name string, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
+
+// Return the DTMI of a TwinInterface by its resource name. The DTMI of TwinInterfaces not exported is reconstructed
+// from the resource name, which does not keep the case of the DTMI.
+func (e *interfaceExport) getDTMI(twinInterfaceName string) string {
+	if dtmi, ok := e.dtmis[twinInterfaceName]; ok {
+		return dtmi
+	}
+
+	dtmi := GetDTMIFromResourceName(twinInterfaceName)
+	e.warnings = append(e.warnings, fmt.Sprintf("DTMI of %s reconstructed from its resource name as %s", twinInterfaceName, dtmi))
+	return dtmi
+}
+
+// Integer enum values are exported as numbers
+func getEnumValue(valueSchema apiv0.PrimitiveType, enumValue string) interface{} {
+	if valueSchema == apiv0.Integer {
+		if value, err := strconv.Atoi(enumValue); err == nil {
+			return value
+		}
+	}
+	return enumValue
+}
+
+// Return the DTMI of a TwinInterface generated from a DTDL interface or, for TwinInterfaces created in the
+// cluster, the DTMI of its model and version
+func GetTwinInterfaceModelDTMI(twinInterface apiv0.TwinInterface) string {
+	if dtmi := GetTwinInterfaceDTMI(twinInterface); dtmi != "" {
+		return dtmi
+	}
+
+	if twinInterface.Spec.ModelId != "" && twinInterface.Spec.Version > 0 {
+		return fmt.Sprintf("%s;%d", twinInterface.Spec.ModelId, twinInterface.Spec.Version)
+	}
+
+	return ""
+}
+
+// Reconstruct a DTMI from a resource name, such as dtmi:city:pole;1 from dtmi-city-pole-1. Hyphens are converted
+// into segment separators, since the characters replaced in the resource names are not known.
+func GetDTMIFromResourceName(resourceName string) string {
+	if matches := versionedNameRegexp.FindStringSubmatch(resourceName); matches != nil {
+		return fmt.Sprintf("dtmi:%s;%s", strings.ReplaceAll(matches[1], "-", ":"), matches[2])
+	}
+
+	return fmt.Sprintf("dtmi:%s;1", strings.ReplaceAll(strings.TrimPrefix(resourceName, "dtmi-"), "-", ":"))
+}
@@ -0,0 +1,454 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/utils"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/graph"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Separator of the ids of a foreign key column that references more than one row
+	INVENTORY_ID_SEPARATOR = ";"
+)
+
+var (
+	ErrInventoryFormat           = errors.New("Inventory must be a CSV file or a JSON array of objects")
+	ErrMissingIdColumn           = errors.New("Row has no id")
+	ErrDuplicateRowId            = errors.New("Row id is used by another row of the TwinInterface")
+	ErrUnknownProperty           = errors.New("TwinInterface has no property")
+	ErrUnknownInventoryColumn    = errors.New("Inventory has no column")
+	ErrUnmappedRelationship      = errors.New("Relationship target TwinInterface is not mapped")
+	ErrUnknownRelationshipTarget = errors.New("Relationship references an unknown row")
+	ErrInventoryMultiplicity     = errors.New("Number of related rows is out of the relationship multiplicity")
+	ErrNoRowsSelected            = errors.New("Mapping selects no rows of the inventory")
+)
+
+// A table of devices, assets or places, such as the poles of a city, with one TwinInstance for each row
+type Inventory struct {
+	FilePath string
+	Columns  []string
+	Rows     []map[string]string
+	// Line of the first row in a CSV file, zero for JSON files
+	firstRowLine int
+}
+
+// Return the location of a row in the inventory file, which is its line in CSV files and its index in JSON files
+func (i Inventory) RowLocation(rowIndex int) string {
+	if i.firstRowLine > 0 {
+		return fmt.Sprintf("%s:%d", i.FilePath, i.firstRowLine+rowIndex)
+	}
+	return fmt.Sprintf("%s[%d]", i.FilePath, rowIndex)
+}
+
+// The mapping of the inventory rows to TwinInstances
+type InventoryMapping struct {
+	TwinInterfaces []InventoryTwinInterfaceMapping `json:"twinInterfaces"`
+}
+
+type InventoryTwinInterfaceMapping struct {
+	// The TwinInterface id, DTMI or trailing segments of the DTMI
+	Interface string `json:"interface"`
+	// Column and value that select the rows of the TwinInterface. All the rows are selected when not informed.
+	TypeColumn string `json:"typeColumn,omitempty"`
+	TypeValue  string `json:"typeValue,omitempty"`
+	// Column with the row id, which names the TwinInstance and is referenced by foreign key columns
+	IdColumn string `json:"idColumn"`
+	// Column of each property, keyed by the property name. Properties not listed are read from the column with the
+	// property name, when the inventory has it.
+	Properties map[string]string `json:"properties,omitempty"`
+	// Foreign key column of each relationship, keyed by the relationship name. The column has the ids of the target
+	// rows, separated by INVENTORY_ID_SEPARATOR.
+	Relationships map[string]string `json:"relationships,omitempty"`
+}
+
+// Load an inventory, which is a CSV file with a header row or a JSON array of objects
+func LoadInventory(filePath string, content []byte) (Inventory, error) {
+	if filepath.Ext(filePath) == ".csv" {
+		return loadCsvInventory(filePath, content)
+	}
+
+	if IsJsonFile(filePath) {
+		return loadJsonInventory(filePath, content)
+	}
+
+	return Inventory{}, fmt.Errorf("%s: %w", filePath, ErrInventoryFormat)
+}
+
+func loadCsvInventory(filePath string, content []byte) (Inventory, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+
+	if err != nil {
+		return Inventory{}, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	if len(records) == 0 {
+		return Inventory{}, fmt.Errorf("%s: %w", filePath, ErrInventoryFormat)
+	}
+
+	inventory := Inventory{FilePath: filePath, Columns: records[0], firstRowLine: 2}
+
+	for _, record := range records[1:] {
+		row := map[string]string{}
+		for index, column := range inventory.Columns {
+			row[column] = strings.TrimSpace(record[index])
+		}
+		inventory.Rows = append(inventory.Rows, row)
+	}
+
+	return inventory, nil
+}
+
+func loadJsonInventory(filePath string, content []byte) (Inventory, error) {
+	var objects []map[string]json.RawMessage
+
+	if err := json.Unmarshal(content, &objects); err != nil {
+		return Inventory{}, fmt.Errorf("%s: %w: %s", filePath, ErrInventoryFormat, err)
+	}
+
+	inventory := Inventory{FilePath: filePath}
+	columns := map[string]bool{}
+
+	for _, object := range objects {
+		row := map[string]string{}
+
+		for column, rawValue := range object {
+			var value string
+
+			// Strings are unquoted and other values, such as numbers and GeoJSON geometries, keep their JSON form
+			if err := json.Unmarshal(rawValue, &value); err != nil {
+				value = string(rawValue)
+			}
+
+			row[column] = value
+			columns[column] = true
+		}
+
+		inventory.Rows = append(inventory.Rows, row)
+	}
+
+	for column := range columns {
+		inventory.Columns = append(inventory.Columns, column)
+	}
+	sort.Strings(inventory.Columns)
+
+	return inventory, nil
+}
+
+func LoadInventoryMapping(content []byte) (InventoryMapping, error) {
+	var mapping InventoryMapping
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&mapping); err != nil {
+		return InventoryMapping{}, err
+	}
+
+	return mapping, nil
+}
+
+type InventoryBuilder interface {
+	// Create the TwinInstances of the inventory rows, keyed by TwinInterface id
+	CreateTwinInstances(inventory Inventory, mapping InventoryMapping) (map[string][]apiv0.TwinInstance, []error)
+}
+
+// Create an InventoryBuilder whose TwinInstances are created by the ResourceBuilder of the CLI config
+func NewInventoryBuilder(twinInterfaceGraph graph.TwinInterfaceGraph, resourceBuilder ResourceBuilder) InventoryBuilder {
+	return &inventoryBuilder{
+		twinInterfaceGraph: twinInterfaceGraph,
+		resourceBuilder:    resourceBuilder,
+		hostUtils:          utils.NewHostUtils(),
+	}
+}
+
+type inventoryBuilder struct {
+	twinInterfaceGraph graph.TwinInterfaceGraph
+	resourceBuilder    ResourceBuilder
+	hostUtils          utils.HostUtils
+}
+
+// The rows of the inventory selected by a TwinInterface mapping
+type inventoryTwinInterfaceRows struct {
+	mapping         InventoryTwinInterfaceMapping
+	mappingPointer  string
+	twinInterfaceId string
+	rowIndexes      []int
+	// The TwinInstance name of each row id
+	instanceNames map[string]string
+}
+
+func (b *inventoryBuilder) CreateTwinInstances(inventory Inventory, mapping InventoryMapping) (map[string][]apiv0.TwinInstance, []error) {
+	var errs []error
+	twinInterfaces := b.twinInterfaceGraph.GetTwinInterfaces()
+	interfaceRows := map[string]*inventoryTwinInterfaceRows{}
+	var twinInterfaceIds []string
+
+	for index, twinInterfaceMapping := range mapping.TwinInterfaces {
+		rows, rowErrs := b.selectRows(inventory, twinInterfaces, twinInterfaceMapping, fmt.Sprintf("/twinInterfaces/%d", index))
+		errs = append(errs, rowErrs...)
+
+		if rows == nil {
+			continue
+		}
+
+		if previousRows, ok := interfaceRows[rows.twinInterfaceId]; ok {
+			errs = append(errs, fmt.Errorf("mapping %s/interface: %w %q, also in %s", rows.mappingPointer, graph.ErrDuplicateTwinInterface, rows.twinInterfaceId, previousRows.mappingPointer))
+			continue
+		}
+
+		interfaceRows[rows.twinInterfaceId] = rows
+		twinInterfaceIds = append(twinInterfaceIds, rows.twinInterfaceId)
+	}
+
+	twinInstances := map[string][]apiv0.TwinInstance{}
+
+	for _, twinInterfaceId := range twinInterfaceIds {
+		rows := interfaceRows[twinInterfaceId]
+		twinInterface := b.twinInterfaceGraph.GetVertex(twinInterfaceId)
+		chain := b.getExtendsChain(*twinInterface)
+		componentChains := b.getComponentChains(chain)
+		effectiveSpec := inheritance.GetEffectiveSpec(chain)
+		propertySchemas := getPropertySchemas(chain, componentChains)
+
+		for _, propertyName := range getSortedKeys(rows.mapping.Properties) {
+			column := rows.mapping.Properties[propertyName]
+			if _, ok := propertySchemas[propertyName]; !ok {
+				errs = append(errs, fmt.Errorf("mapping %s/properties: %w %q", rows.mappingPointer, ErrUnknownProperty, propertyName))
+			}
+			if !containsColumn(inventory.Columns, column) {
+				errs = append(errs, fmt.Errorf("mapping %s/properties/%s: %w %q", rows.mappingPointer, propertyName, ErrUnknownInventoryColumn, column))
+			}
+		}
+
+		for _, relationshipName := range getSortedKeys(rows.mapping.Relationships) {
+			column := rows.mapping.Relationships[relationshipName]
+			if !containsColumn(inventory.Columns, column) {
+				errs = append(errs, fmt.Errorf("mapping %s/relationships/%s: %w %q", rows.mappingPointer, relationshipName, ErrUnknownInventoryColumn, column))
+			}
+		}
+
+		for _, rowIndex := range rows.rowIndexes {
+			row := inventory.Rows[rowIndex]
+			location := inventory.RowLocation(rowIndex)
+
+			twinInstance := b.resourceBuilder.CreateTwinInstance(apiv0.TwinInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: rows.instanceNames[row[rows.mapping.IdColumn]],
+				},
+				Spec: apiv0.TwinInstanceSpec{
+					Interface: twinInterfaceId,
+				},
+			}, chain, componentChains)
+
+			if twinInstance.Spec.Data != nil {
+				for index, propertyData := range twinInstance.Spec.Data.Properties {
+					column := propertyData.Name
+					if mappedColumn, ok := rows.mapping.Properties[propertyData.Name]; ok {
+						column = mappedColumn
+					}
+
+					value, err := ConvertPropertyValue(propertySchemas[propertyData.Name], row[column])
+					if err != nil {
+						errs = append(errs, fmt.Errorf("%s column %q: %w", location, column, err))
+						continue
+					}
+
+					twinInstance.Spec.Data.Properties[index].Value = value
+				}
+			}
+
+			relationships, relationshipErrs := getInventoryRelationships(row, location, rows.mapping, effectiveSpec.Relationships, interfaceRows)
+			errs = append(errs, relationshipErrs...)
+			twinInstance.Spec.TwinInstanceRelationships = relationships
+
+			twinInstances[twinInterfaceId] = append(twinInstances[twinInterfaceId], twinInstance)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return twinInstances, nil
+}
+
+// Select the rows of a TwinInterface mapping and name their TwinInstances
+func (b *inventoryBuilder) selectRows(inventory Inventory, twinInterfaces []apiv0.TwinInterface, mapping InventoryTwinInterfaceMapping, pointer string) (*inventoryTwinInterfaceRows, []error) {
+	var errs []error
+
+	twinInterfaceId, err := graph.ResolveTwinInterface(twinInterfaces, mapping.Interface)
+	if err != nil {
+		return nil, []error{fmt.Errorf("mapping %s/interface: %w", pointer, err)}
+	}
+
+	if !containsColumn(inventory.Columns, mapping.IdColumn) {
+		return nil, []error{fmt.Errorf("mapping %s/idColumn: %w %q", pointer, ErrUnknownInventoryColumn, mapping.IdColumn)}
+	}
+
+	if mapping.TypeColumn != "" && !containsColumn(inventory.Columns, mapping.TypeColumn) {
+		return nil, []error{fmt.Errorf("mapping %s/typeColumn: %w %q", pointer, ErrUnknownInventoryColumn, mapping.TypeColumn)}
+	}
+
+	rows := &inventoryTwinInterfaceRows{
+		mapping:         mapping,
+		mappingPointer:  pointer,
+		twinInterfaceId: twinInterfaceId,
+		instanceNames:   map[string]string{},
+	}
+
+	for rowIndex, row := range inventory.Rows {
+		if mapping.TypeColumn != "" && row[mapping.TypeColumn] != mapping.TypeValue {
+			continue
+		}
+
+		location := inventory.RowLocation(rowIndex)
+		rowId := row[mapping.IdColumn]
+
+		if rowId == "" {
+			errs = append(errs, fmt.Errorf("%s column %q: %w", location, mapping.IdColumn, ErrMissingIdColumn))
+			continue
+		}
+
+		if _, ok := rows.instanceNames[rowId]; ok {
+			errs = append(errs, fmt.Errorf("%s column %q: %w %q", location, mapping.IdColumn, ErrDuplicateRowId, rowId))
+			continue
+		}
+
+		instanceName := twinInterfaceId + "-" + b.hostUtils.ParseHostName(rowId)
+
+		if err := b.hostUtils.ValidateHostName(instanceName); err != nil {
+			errs = append(errs, fmt.Errorf("%s column %q: TwinInstance name %q: %s", location, mapping.IdColumn, instanceName, err))
+			continue
+		}
+
+		rows.instanceNames[rowId] = instanceName
+		rows.rowIndexes = append(rows.rowIndexes, rowIndex)
+	}
+
+	if len(rows.rowIndexes) == 0 && len(errs) == 0 {
+		errs = append(errs, fmt.Errorf("mapping %s: %w", pointer, ErrNoRowsSelected))
+	}
+
+	return rows, errs
+}
+
+// Create the relationships of a row from its foreign key columns, which reference rows of the relationship target
+func getInventoryRelationships(row map[string]string, location string, mapping InventoryTwinInterfaceMapping, twinRelationships []apiv0.TwinRelationship, interfaceRows map[string]*inventoryTwinInterfaceRows) ([]apiv0.TwinInstanceRelationship, []error) {
+	var errs []error
+	var relationships []apiv0.TwinInstanceRelationship
+
+	for _, twinRelationship := range twinRelationships {
+		column, ok := mapping.Relationships[twinRelationship.Name]
+		if !ok {
+			continue
+		}
+
+		var targetIds []string
+		for _, targetId := range strings.Split(row[column], INVENTORY_ID_SEPARATOR) {
+			if targetId = strings.TrimSpace(targetId); targetId != "" {
+				targetIds = append(targetIds, targetId)
+			}
+		}
+
+		if len(targetIds) < twinRelationship.MinMultiplicity || (twinRelationship.MaxMultiplicity > 0 && len(targetIds) > twinRelationship.MaxMultiplicity) {
+			errs = append(errs, fmt.Errorf("%s column %q: %w [%d, %d]", location, column, ErrInventoryMultiplicity, twinRelationship.MinMultiplicity, twinRelationship.MaxMultiplicity))
+			continue
+		}
+
+		targetRows := interfaceRows[twinRelationship.Interface]
+
+		if targetRows == nil {
+			if len(targetIds) > 0 {
+				errs = append(errs, fmt.Errorf("%s column %q: %w %q", location, column, ErrUnmappedRelationship, twinRelationship.Interface))
+			}
+			continue
+		}
+
+		for _, targetId := range targetIds {
+			instanceName, ok := targetRows.instanceNames[targetId]
+
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s column %q: %w %q of %s", location, column, ErrUnknownRelationshipTarget, targetId, twinRelationship.Interface))
+				continue
+			}
+
+			relationships = append(relationships, apiv0.TwinInstanceRelationship{
+				Name:      twinRelationship.Name,
+				Interface: twinRelationship.Interface,
+				Instance:  instanceName,
+			})
+		}
+	}
+
+	return relationships, errs
+}
+
+func (b *inventoryBuilder) getExtendsChain(twinInterface apiv0.TwinInterface) []apiv0.TwinInterface {
+	if chain := b.twinInterfaceGraph.GetExtendsChain(twinInterface.Spec.Id); chain != nil {
+		return chain
+	}
+	return []apiv0.TwinInterface{twinInterface}
+}
+
+// Return the TwinInterface chain of each component of the TwinInterfaces, keyed by the component name
+func (b *inventoryBuilder) getComponentChains(twinInterfaces []apiv0.TwinInterface) map[string][]apiv0.TwinInterface {
+	componentChains := map[string][]apiv0.TwinInterface{}
+
+	for _, twinInterface := range twinInterfaces {
+		for _, component := range twinInterface.Spec.Components {
+			if componentInterface := b.twinInterfaceGraph.GetVertex(component.Interface); componentInterface != nil {
+				componentChains[component.Name] = b.getExtendsChain(*componentInterface)
+			}
+		}
+	}
+
+	return componentChains
+}
+
+// Return the schema of each property of the TwinInstance data, keyed by the property name. The properties of
+// components are keyed by their qualified name.
+func getPropertySchemas(chain []apiv0.TwinInterface, componentChains map[string][]apiv0.TwinInterface) map[string]*apiv0.TwinSchema {
+	propertySchemas := map[string]*apiv0.TwinSchema{}
+
+	for _, property := range inheritance.GetEffectiveSpec(chain).Properties {
+		propertySchemas[property.Name] = property.Schema
+	}
+
+	for componentName, componentChain := range componentChains {
+		for _, property := range inheritance.GetEffectiveSpec(componentChain).Properties {
+			propertySchemas[inheritance.GetComponentQualifiedName(componentName, property.Name)] = property.Schema
+		}
+	}
+
+	return propertySchemas
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, inventoryColumn := range columns {
+		if inventoryColumn == column {
+			return true
+		}
+	}
+	return false
+}
+
+func getSortedKeys(values map[string]string) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
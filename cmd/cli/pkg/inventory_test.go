@@ -0,0 +1,234 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/graph"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testInventoryMapping = `{
+	"twinInterfaces": [
+		{"interface": "city-pole", "typeColumn": "type", "typeValue": "pole", "idColumn": "id", "relationships": {"street": "street"}},
+		{"interface": "city-street", "typeColumn": "type", "typeValue": "street", "idColumn": "id"}
+	]
+}`
+
+func newInventoryTwinInterfaceGraph() graph.TwinInterfaceGraph {
+	twinInterfaceGraph := graph.NewTwinInterfaceGraph()
+
+	twinInterfaceGraph.AddVertex(apiv0.TwinInterface{
+		Spec: apiv0.TwinInterfaceSpec{
+			Id: "city-pole",
+			Properties: []apiv0.TwinProperty{
+				{Name: "height", Schema: &apiv0.TwinSchema{PrimitiveType: apiv0.Double}},
+				{Name: "installed", Schema: &apiv0.TwinSchema{PrimitiveType: apiv0.Date}},
+			},
+			Relationships: []apiv0.TwinRelationship{
+				{Name: "street", Interface: "city-street", MaxMultiplicity: 1},
+			},
+		},
+	})
+	twinInterfaceGraph.AddVertex(apiv0.TwinInterface{
+		Spec: apiv0.TwinInterfaceSpec{
+			Id: "city-street",
+			Properties: []apiv0.TwinProperty{
+				{Name: "name", Schema: &apiv0.TwinSchema{PrimitiveType: apiv0.String}},
+			},
+		},
+	})
+
+	return twinInterfaceGraph
+}
+
+func TestLoadInventory(t *testing.T) {
+	tests := []struct {
+		name             string
+		filePath         string
+		content          string
+		expectedColumns  []string
+		expectedRows     []map[string]string
+		expectedLocation string
+		expectedError    error
+	}{
+		{
+			name:             "CSV inventory",
+			filePath:         "poles.csv",
+			content:          "id,height\np1, 10.5\np2,12\n",
+			expectedColumns:  []string{"id", "height"},
+			expectedRows:     []map[string]string{{"id": "p1", "height": "10.5"}, {"id": "p2", "height": "12"}},
+			expectedLocation: "poles.csv:3",
+		},
+		{
+			name:             "JSON inventory",
+			filePath:         "poles.json",
+			content:          `[{"id": "p1", "height": 10.5}, {"id": "p2", "location": {"type": "Point"}}]`,
+			expectedColumns:  []string{"height", "id", "location"},
+			expectedRows:     []map[string]string{{"id": "p1", "height": "10.5"}, {"id": "p2", "location": `{"type": "Point"}`}},
+			expectedLocation: "poles.json[1]",
+		},
+		{name: "Unknown file extension", filePath: "poles.xlsx", content: "id\np1\n", expectedError: ErrInventoryFormat},
+		{name: "Empty CSV inventory", filePath: "poles.csv", content: "", expectedError: ErrInventoryFormat},
+		{name: "JSON inventory of other values", filePath: "poles.json", content: `{"id": "p1"}`, expectedError: ErrInventoryFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventory, err := LoadInventory(tt.filePath, []byte(tt.content))
+
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedColumns, inventory.Columns)
+			assert.Equal(t, tt.expectedRows, inventory.Rows)
+			assert.Equal(t, tt.expectedLocation, inventory.RowLocation(1))
+		})
+	}
+
+	t.Run("CSV rows with a different number of columns", func(t *testing.T) {
+		_, err := LoadInventory("poles.csv", []byte("id,height\np1\n"))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestLoadInventoryMapping(t *testing.T) {
+	mapping, err := LoadInventoryMapping([]byte(testInventoryMapping))
+	assert.Nil(t, err)
+	assert.Len(t, mapping.TwinInterfaces, 2)
+
+	_, err = LoadInventoryMapping([]byte(`{"twinInterfaces": [{"interface": "city-pole", "idColumns": "id"}]}`))
+	assert.NotNil(t, err)
+}
+
+func TestInventoryBuilder_CreateTwinInstances(t *testing.T) {
+	inventory, err := LoadInventory("city.csv", []byte("type,id,height,installed,name,street\n"+
+		"street,s1,,,Main Street,\n"+
+		"pole,p1,10.50,2023-01-02,,s1\n"+
+		"pole,p2,12,,,\n"))
+	assert.Nil(t, err)
+
+	mapping, err := LoadInventoryMapping([]byte(testInventoryMapping))
+	assert.Nil(t, err)
+
+	twinInstances, errs := NewInventoryBuilder(newInventoryTwinInterfaceGraph(), NewResourceBuilder()).CreateTwinInstances(inventory, mapping)
+	assert.Empty(t, errs)
+
+	assert.Len(t, twinInstances["city-street"], 1)
+	assert.Equal(t, "city-street-s1", twinInstances["city-street"][0].Name)
+	assert.Equal(t, []apiv0.TwinInstancePropertyData{{Name: "name", Value: "Main Street"}}, twinInstances["city-street"][0].Spec.Data.Properties)
+
+	assert.Len(t, twinInstances["city-pole"], 2)
+	assert.Equal(t, "city-pole-p1", twinInstances["city-pole"][0].Name)
+	assert.Equal(t, "city-pole", twinInstances["city-pole"][0].Spec.Interface)
+	assert.Equal(t, []apiv0.TwinInstancePropertyData{{Name: "height", Value: "10.5"}, {Name: "installed", Value: "2023-01-02"}}, twinInstances["city-pole"][0].Spec.Data.Properties)
+	assert.Equal(t, []apiv0.TwinInstanceRelationship{{Name: "street", Interface: "city-street", Instance: "city-street-s1"}}, twinInstances["city-pole"][0].Spec.TwinInstanceRelationships)
+	assert.Empty(t, twinInstances["city-pole"][1].Spec.TwinInstanceRelationships)
+}
+
+func TestInventoryBuilder_CreateTwinInstancesErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		inventory string
+		mapping   string
+		expected  []error
+	}{
+		{
+			name:      "Values not matching the property schemas",
+			inventory: "type,id,height,installed,street\npole,p1,tall,2023-13-01,\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-pole", "idColumn": "id"}]}`,
+			expected:  []error{ErrInvalidValue, ErrInvalidValue},
+		},
+		{
+			name:      "Missing id column",
+			inventory: "type,code,height\npole,p1,10\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-pole", "idColumn": "id"}]}`,
+			expected:  []error{ErrUnknownInventoryColumn},
+		},
+		{
+			name:      "Missing type column",
+			inventory: "id,height\np1,10\n",
+			mapping:   testInventoryMapping,
+			expected:  []error{ErrUnknownInventoryColumn, ErrUnknownInventoryColumn},
+		},
+		{
+			name:      "Missing property and relationship columns",
+			inventory: "type,id\npole,p1\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-pole", "idColumn": "id", "properties": {"height": "heightInMeters"}, "relationships": {"street": "street"}}]}`,
+			expected:  []error{ErrUnknownInventoryColumn, ErrUnknownInventoryColumn},
+		},
+		{
+			name:      "Unknown property",
+			inventory: "type,id,weight\npole,p1,10\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-pole", "idColumn": "id", "properties": {"weight": "weight"}}]}`,
+			expected:  []error{ErrUnknownProperty},
+		},
+		{
+			name:      "Unknown TwinInterface",
+			inventory: "type,id\nlight,l1\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-light", "idColumn": "id"}]}`,
+			expected:  []error{graph.ErrUnknownTwinInterface},
+		},
+		{
+			name:      "TwinInterface mapped more than once",
+			inventory: "type,id\npole,p1\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-pole", "idColumn": "id"}, {"interface": "city-pole", "idColumn": "id"}]}`,
+			expected:  []error{graph.ErrDuplicateTwinInterface},
+		},
+		{
+			name:      "Unknown relationship target",
+			inventory: "type,id,street\nstreet,s1,\npole,p1,s2\n",
+			mapping:   testInventoryMapping,
+			expected:  []error{ErrUnknownRelationshipTarget},
+		},
+		{
+			name:      "Relationship target TwinInterface not mapped",
+			inventory: "type,id,street\npole,p1,s1\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-pole", "idColumn": "id", "relationships": {"street": "street"}}]}`,
+			expected:  []error{ErrUnmappedRelationship},
+		},
+		{
+			name:      "Number of related rows out of the multiplicity",
+			inventory: "type,id,street\nstreet,s1,\nstreet,s2,\npole,p1,s1;s2\n",
+			mapping:   testInventoryMapping,
+			expected:  []error{ErrInventoryMultiplicity},
+		},
+		{
+			name:      "Duplicate and missing row ids",
+			inventory: "type,id\npole,p1\npole,p1\npole,\n",
+			mapping:   `{"twinInterfaces": [{"interface": "city-pole", "idColumn": "id"}]}`,
+			expected:  []error{ErrDuplicateRowId, ErrMissingIdColumn},
+		},
+		{
+			name:      "No rows selected",
+			inventory: "type,id,street\npole,p1,\n",
+			mapping:   testInventoryMapping,
+			expected:  []error{ErrNoRowsSelected},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventory, err := LoadInventory("city.csv", []byte(tt.inventory))
+			assert.Nil(t, err)
+
+			mapping, err := LoadInventoryMapping([]byte(tt.mapping))
+			assert.Nil(t, err)
+
+			twinInstances, errs := NewInventoryBuilder(newInventoryTwinInterfaceGraph(), NewResourceBuilder()).CreateTwinInstances(inventory, mapping)
+			assert.Nil(t, twinInstances)
+			assert.Equal(t, len(tt.expected), len(errs), errs)
+
+			for index, err := range errs {
+				if index < len(tt.expected) {
+					assert.True(t, errors.Is(err, tt.expected[index]), err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	apiv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Load the TwinInterfaces of a YAML or JSON file with one or more documents. Documents of other kinds are skipped
+// and TwinInterfaces of the dtd.ktwin/v1 API are converted to dtd.ktwin/v0.
+func LoadTwinInterfaces(filePath string, content []byte) ([]apiv0.TwinInterface, error) {
+	objects, err := LoadManifests(filePath, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var twinInterfaces []apiv0.TwinInterface
+	for _, object := range objects {
+		if twinInterface, ok := object.(*apiv0.TwinInterface); ok {
+			twinInterfaces = append(twinInterfaces, *twinInterface)
+		}
+	}
+
+	return twinInterfaces, nil
+}
+
+// Load the TwinInterfaces and TwinInstances of a YAML or JSON file with one or more documents, which may be Lists.
+// Documents of other kinds are skipped and resources of the dtd.ktwin/v1 API are converted to dtd.ktwin/v0.
+func LoadManifests(filePath string, content []byte) ([]client.Object, error) {
+	var objects []client.Object
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+
+	for {
+		var document json.RawMessage
+		err := decoder.Decode(&document)
+
+		if err == io.EOF {
+			return objects, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filePath, err)
+		}
+
+		if len(document) == 0 || string(document) == "null" {
+			continue
+		}
+
+		var typeMeta v1.TypeMeta
+		if err := json.Unmarshal(document, &typeMeta); err != nil {
+			return nil, fmt.Errorf("%s: %w", filePath, err)
+		}
+
+		var object client.Object
+
+		switch typeMeta.Kind {
+		case "TwinInterface":
+			object, err = loadTwinInterface(typeMeta.APIVersion, document)
+		case "TwinInstance":
+			object, err = loadTwinInstance(typeMeta.APIVersion, document)
+		case "List":
+			var list struct {
+				Items []json.RawMessage `json:"items"`
+			}
+			if err := json.Unmarshal(document, &list); err != nil {
+				return nil, fmt.Errorf("%s: List: %w", filePath, err)
+			}
+			for _, item := range list.Items {
+				itemObjects, err := LoadManifests(filePath, item)
+				if err != nil {
+					return nil, err
+				}
+				objects = append(objects, itemObjects...)
+			}
+			continue
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", filePath, typeMeta.Kind, err)
+		}
+
+		if object != nil {
+			objects = append(objects, object)
+		}
+	}
+}
+
+// Return nil when the document is not of a known API version
+func loadTwinInterface(apiVersion string, document []byte) (client.Object, error) {
+	twinInterface := &apiv0.TwinInterface{}
+
+	switch apiVersion {
+	case apiv0.GroupVersion.String():
+		if err := json.Unmarshal(document, twinInterface); err != nil {
+			return nil, err
+		}
+	case apiv1.GroupVersion.String():
+		hubTwinInterface := &apiv1.TwinInterface{}
+		if err := json.Unmarshal(document, hubTwinInterface); err != nil {
+			return nil, err
+		}
+		if err := twinInterface.ConvertFrom(hubTwinInterface); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	return twinInterface, nil
+}
+
+// Return nil when the document is not of a known API version
+func loadTwinInstance(apiVersion string, document []byte) (client.Object, error) {
+	twinInstance := &apiv0.TwinInstance{}
+
+	switch apiVersion {
+	case apiv0.GroupVersion.String():
+		if err := json.Unmarshal(document, twinInstance); err != nil {
+			return nil, err
+		}
+	case apiv1.GroupVersion.String():
+		hubTwinInstance := &apiv1.TwinInstance{}
+		if err := json.Unmarshal(document, hubTwinInstance); err != nil {
+			return nil, err
+		}
+		if err := twinInstance.ConvertFrom(hubTwinInstance); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	return twinInstance, nil
+}
+
+// Create a client of the cluster of the kubeconfig, informed by the --kubeconfig flag, the KUBECONFIG environment
+// variable or the default location
+func NewClusterClient() (client.Client, error) {
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apiv0.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return client.New(restConfig, client.Options{Scheme: scheme})
+}
+
+// List the TwinInterfaces of a namespace of the cluster
+func ListTwinInterfaces(ctx context.Context, c client.Client, namespace string) ([]apiv0.TwinInterface, error) {
+	twinInterfaceList := &apiv0.TwinInterfaceList{}
+
+	if err := c.List(ctx, twinInterfaceList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	return twinInterfaceList.Items, nil
+}
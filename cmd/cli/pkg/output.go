@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sJson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type OutputFormat string
+
+const (
+	OutputFormatYAML OutputFormat = "yaml"
+	OutputFormatJSON OutputFormat = "json"
+)
+
+const (
+	KUSTOMIZATION_FILE_NAME = "kustomization.yaml"
+)
+
+var (
+	ErrInvalidOutputFormat = errors.New("Output format must be yaml or json")
+)
+
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch OutputFormat(value) {
+	case OutputFormatYAML, OutputFormatJSON:
+		return OutputFormat(value), nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidOutputFormat, value)
+}
+
+// Return the file extension of the format, with the leading dot
+func (f OutputFormat) Extension() string {
+	return "." + string(f)
+}
+
+// Encode the resources as a YAML stream with one document for each resource, or as a JSON document. A single
+// resource is encoded as a JSON object and more than one resource as a List, so the output can be applied with
+// kubectl. The resources must have their TypeMeta set.
+func EncodeManifests(objects []client.Object, format OutputFormat) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+
+	if format == OutputFormatJSON {
+		serializer := k8sJson.NewSerializerWithOptions(k8sJson.DefaultMetaFactory, nil, nil, k8sJson.SerializerOptions{Pretty: true})
+
+		if len(objects) == 1 {
+			err := serializer.Encode(objects[0], buffer)
+			return buffer.Bytes(), err
+		}
+
+		list := &corev1.List{
+			TypeMeta: v1.TypeMeta{Kind: "List", APIVersion: "v1"},
+			Items:    []runtime.RawExtension{},
+		}
+
+		for _, object := range objects {
+			content, err := json.Marshal(object)
+			if err != nil {
+				return nil, err
+			}
+			list.Items = append(list.Items, runtime.RawExtension{Raw: content})
+		}
+
+		err := serializer.Encode(list, buffer)
+		return buffer.Bytes(), err
+	}
+
+	serializer := k8sJson.NewYAMLSerializer(k8sJson.DefaultMetaFactory, nil, nil)
+
+	for index, object := range objects {
+		if index > 0 {
+			buffer.WriteString("---\n")
+		}
+
+		if err := serializer.Encode(object, buffer); err != nil {
+			return nil, err
+		}
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Create a kustomization file with the resource files, relative to the folder of the kustomization file, and the
+// namespace of the resources
+func CreateKustomization(resourceFiles []string, namespace string) []byte {
+	buffer := new(bytes.Buffer)
+
+	buffer.WriteString("apiVersion: kustomize.config.k8s.io/v1beta1\n")
+	buffer.WriteString("kind: Kustomization\n")
+
+	if namespace != "" {
+		fmt.Fprintf(buffer, "namespace: %s\n", quoteYAMLString(namespace))
+	}
+
+	buffer.WriteString("resources:\n")
+	for _, resourceFile := range resourceFiles {
+		fmt.Fprintf(buffer, "- %s\n", quoteYAMLString(resourceFile))
+	}
+
+	return buffer.Bytes()
+}
+
+// JSON strings are valid YAML double-quoted scalars
+func quoteYAMLString(value string) string {
+	content, _ := json.Marshal(value)
+	return string(content)
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		value          string
+		expectedFormat OutputFormat
+		expectedError  error
+	}{
+		{value: "yaml", expectedFormat: OutputFormatYAML},
+		{value: "json", expectedFormat: OutputFormatJSON},
+		{value: "xml", expectedError: ErrInvalidOutputFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			format, err := ParseOutputFormat(tt.value)
+
+			assert.Equal(t, tt.expectedFormat, format)
+			assert.True(t, errors.Is(err, tt.expectedError))
+		})
+	}
+}
+
+func TestEncodeManifests(t *testing.T) {
+	objects := []client.Object{
+		newDiffTwinInterface("pole", "Pole", map[string]string{"ktwin/dtmi": "dtmi:city:Pole;1"}),
+		newDiffTwinInstance("pole-001", "pole"),
+		newDiffTwinInstance("pole-002", "pole"),
+	}
+
+	tests := []struct {
+		name            string
+		objects         []client.Object
+		format          OutputFormat
+		expectedPrefix  string
+		expectedObjects int
+	}{
+		{name: "YAML documents", objects: objects, format: OutputFormatYAML, expectedPrefix: "apiVersion: dtd.ktwin/v0\nkind: TwinInterface\n", expectedObjects: 3},
+		{name: "JSON list", objects: objects, format: OutputFormatJSON, expectedPrefix: "{\n  \"kind\": \"List\",\n  \"apiVersion\": \"v1\",", expectedObjects: 3},
+		{name: "JSON object", objects: objects[:1], format: OutputFormatJSON, expectedPrefix: "{\n  \"kind\": \"TwinInterface\",\n  \"apiVersion\": \"dtd.ktwin/v0\",", expectedObjects: 1},
+		{name: "Empty JSON list", objects: nil, format: OutputFormatJSON, expectedPrefix: "{\n  \"kind\": \"List\",", expectedObjects: 0},
+		{name: "Empty YAML", objects: nil, format: OutputFormatYAML, expectedPrefix: "", expectedObjects: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := EncodeManifests(tt.objects, tt.format)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedPrefix, string(content[:len(tt.expectedPrefix)]))
+
+			loadedObjects, err := LoadManifests("manifests"+tt.format.Extension(), content)
+			assert.Nil(t, err)
+			assert.Len(t, loadedObjects, tt.expectedObjects)
+
+			objectDiffs, err := NewManifestDiffer(loadedObjects).Diff(context.Background(), tt.objects)
+			assert.Nil(t, err)
+			assert.False(t, HasDifferences(objectDiffs))
+		})
+	}
+}
+
+func TestCreateKustomization(t *testing.T) {
+	expected := "apiVersion: kustomize.config.k8s.io/v1beta1\n" +
+		"kind: Kustomization\n" +
+		"namespace: \"smart-city\"\n" +
+		"resources:\n" +
+		"- \"resources.yaml\"\n" +
+		"- \"city/01-city-interface.yaml\"\n"
+
+	assert.Equal(t, expected, string(CreateKustomization([]string{"resources.yaml", "city/01-city-interface.yaml"}, "smart-city")))
+}
@@ -5,31 +5,55 @@ import (
 
 	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
 	dtdl "github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/dtdl"
-	"github.com/Open-Digital-Twin/ktwin-operator/pkg/naming"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
 
-	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/Open-Digital-Twin/ktwin-operator/cmd/cli/utils"
 )
 
 const (
-	INSTANCE_SUFFIX = "-001"
+	// Annotation with the DTMI of the TwinInterface, since resource names do not keep all its characters
+	DTMI_ANNOTATION = "ktwin/dtmi"
+
+	// Namespace of the generated TwinInterfaces and TwinInstances
+	DEFAULT_NAMESPACE = "ktwin"
 )
 
+// TwinInterfaces support the primitive schemas of DTDL v2. The ones added by DTDL v3 are
+// generated as the v2 primitive schema able to hold their values.
+var v3PrimitiveSchemas = map[string]apiv0.PrimitiveType{
+	"byte":            apiv0.Integer,
+	"short":           apiv0.Integer,
+	"unsignedByte":    apiv0.Integer,
+	"unsignedShort":   apiv0.Integer,
+	"unsignedInteger": apiv0.Long,
+	"unsignedLong":    apiv0.String,
+	"decimal":         apiv0.String,
+	"bytes":           apiv0.String,
+	"uuid":            apiv0.String,
+}
+
 type ResourceBuilder interface {
 	CreateTwinInterface(tInterface dtdl.Interface) apiv0.TwinInterface
-	CreateTwinInstance(twinInterface apiv0.TwinInterface, parentTwinInterfaces []apiv0.TwinInterface) apiv0.TwinInstance
+	CreateTwinInstance(twinInstance apiv0.TwinInstance, parentTwinInterfaces []apiv0.TwinInterface, componentTwinInterfaces map[string][]apiv0.TwinInterface) apiv0.TwinInstance
 }
 
 func NewResourceBuilder() ResourceBuilder {
+	return NewResourceBuilderWithConfig(CLIConfig{})
+}
+
+// Create a ResourceBuilder whose TwinInterfaces have the service and event store settings of the CLI config
+func NewResourceBuilderWithConfig(config CLIConfig) ResourceBuilder {
 	return &resourceBuilder{
 		hostUtils: utils.NewHostUtils(),
+		config:    config,
 	}
 }
 
 type resourceBuilder struct {
 	hostUtils utils.HostUtils
+	config    CLIConfig
 }
 
 func (r *resourceBuilder) CreateTwinInterface(tInterface dtdl.Interface) apiv0.TwinInterface {
@@ -37,7 +61,8 @@ func (r *resourceBuilder) CreateTwinInterface(tInterface dtdl.Interface) apiv0.T
 	var relationships []apiv0.TwinRelationship
 	var telemetries []apiv0.TwinTelemetry
 	var commands []apiv0.TwinCommand
-	var interfaceExtends string
+	var components []apiv0.TwinComponent
+	var interfaceExtends []string
 
 	for _, content := range tInterface.Contents {
 		if content.Property != nil {
@@ -52,15 +77,25 @@ func (r *resourceBuilder) CreateTwinInterface(tInterface dtdl.Interface) apiv0.T
 		if content.Command != nil {
 			commands = r.processCommand(*content.Command, commands)
 		}
+		if content.Component != nil {
+			components = r.processComponent(*content.Component, components)
+		}
 	}
 
-	// Only supports one parent interface
-	if len(tInterface.Extends) > 0 {
-		interfaceExtends = r.hostUtils.ParseHostName(tInterface.Extends[0])
+	for _, extends := range tInterface.Extends {
+		interfaceExtends = append(interfaceExtends, r.hostUtils.ParseHostName(extends))
 	}
 
 	normalizedInterfaceId := r.hostUtils.ParseHostName(string(tInterface.Id))
 
+	// The versions of a model are generated as TwinInterfaces that share the model and coexist in the cluster
+	var modelId string
+	var version int
+	if parsedDTMI, err := dtdl.ParseDTMI(string(tInterface.Id)); err == nil && parsedDTMI.HasVersion() {
+		modelId = parsedDTMI.Unversioned()
+		version = parsedDTMI.MajorVersion
+	}
+
 	twinInterface := apiv0.TwinInterface{
 		TypeMeta: v1.TypeMeta{
 			Kind:       "TwinInterface",
@@ -68,110 +103,99 @@ func (r *resourceBuilder) CreateTwinInterface(tInterface dtdl.Interface) apiv0.T
 		},
 		ObjectMeta: v1.ObjectMeta{
 			Name:      normalizedInterfaceId,
-			Namespace: "ktwin",
+			Namespace: DEFAULT_NAMESPACE,
+			Annotations: map[string]string{
+				DTMI_ANNOTATION: string(tInterface.Id),
+			},
 		},
 		Spec: apiv0.TwinInterfaceSpec{
-			Id:               normalizedInterfaceId,
-			DisplayName:      string(tInterface.DisplayName),
-			Description:      string(tInterface.Description),
-			Comment:          string(tInterface.Comment),
-			Properties:       properties,
-			Relationships:    relationships,
-			Commands:         commands,
-			Telemetries:      telemetries,
-			ExtendsInterface: interfaceExtends,
-			Service: &apiv0.TwinInterfaceService{
-				AutoScaling: apiv0.TwinInterfaceAutoScaling{
-					MaxScale:                    newIntPtr(20),
-					Target:                      newIntPtr(10),
-					Parallelism:                 newIntPtr(100),
-					TargetUtilizationPercentage: newIntPtr(65),
-					Metric:                      "concurrency",
-				},
-				Template: corev1.PodTemplateSpec{
-					Spec: corev1.PodSpec{Containers: []corev1.Container{
-						{
-							Name:            normalizedInterfaceId,
-							Image:           naming.GetContainerRegistry("ktwin-" + normalizedInterfaceId + "-service:0.1"),
-							ImagePullPolicy: corev1.PullIfNotPresent,
-						},
-					}},
-				},
-			},
+			Id:            normalizedInterfaceId,
+			ModelId:       modelId,
+			Version:       version,
+			DisplayName:   string(tInterface.DisplayName),
+			Description:   string(tInterface.Description),
+			Comment:       string(tInterface.Comment),
+			Properties:    properties,
+			Relationships: relationships,
+			Commands:      commands,
+			Telemetries:   telemetries,
+			Components:    components,
+			Extends:       interfaceExtends,
+			EventStore:    r.config.GetTwinInterfaceEventStore(string(tInterface.Id)),
+			Service:       r.config.GetTwinInterfaceService(string(tInterface.Id), normalizedInterfaceId),
 		},
 	}
 
 	return twinInterface
 }
 
-func (r *resourceBuilder) CreateTwinInstance(twinInterface apiv0.TwinInterface, parentTwinInterfaces []apiv0.TwinInterface) apiv0.TwinInstance {
-	normalizeTwinInterfacedId := r.hostUtils.ParseHostName(string(twinInterface.Spec.Id))
-	normalizeTwinInstanceId := normalizeTwinInterfacedId + INSTANCE_SUFFIX
-
-	twinInstance := apiv0.TwinInstance{
+// Create the resource of a TwinInstance generated by the instance graph, which informs its name, TwinInterface and
+// relationships, with example data. The componentTwinInterfaces contains the TwinInterface chain of each component
+// of the TwinInterface, keyed by the component name.
+func (r *resourceBuilder) CreateTwinInstance(twinInstance apiv0.TwinInstance, parentTwinInterfaces []apiv0.TwinInterface, componentTwinInterfaces map[string][]apiv0.TwinInterface) apiv0.TwinInstance {
+	return apiv0.TwinInstance{
 		TypeMeta: v1.TypeMeta{
 			Kind:       "TwinInstance",
 			APIVersion: "dtd.ktwin/v0",
 		},
 		ObjectMeta: v1.ObjectMeta{
-			Name:      normalizeTwinInstanceId,
-			Namespace: "ktwin",
+			Name:      twinInstance.Name,
+			Namespace: DEFAULT_NAMESPACE,
 		},
 		Spec: apiv0.TwinInstanceSpec{
-			Interface:                 normalizeTwinInterfacedId,
-			TwinInstanceRelationships: r.getTwinInstanceRelationships(parentTwinInterfaces),
-			Data:                      r.getTwinData(parentTwinInterfaces),
+			Interface:                 twinInstance.Spec.Interface,
+			TwinInstanceRelationships: twinInstance.Spec.TwinInstanceRelationships,
+			Data:                      r.getTwinData(parentTwinInterfaces, componentTwinInterfaces),
 		},
 	}
-
-	return twinInstance
 }
 
-func (r *resourceBuilder) getTwinInstanceRelationships(parentTwinInterfaces []apiv0.TwinInterface) []apiv0.TwinInstanceRelationship {
-	var twinInstanceRelationship []apiv0.TwinInstanceRelationship
+func (r *resourceBuilder) getTwinData(twinInterfaces []apiv0.TwinInterface, componentTwinInterfaces map[string][]apiv0.TwinInterface) *apiv0.TwinInstanceDataSpec {
+	var twinInstanceData *apiv0.TwinInstanceDataSpec
 
-	for _, twinInterface := range parentTwinInterfaces {
-		if len(twinInterface.Spec.Relationships) == 0 {
-			continue
-		}
+	for _, twinInterface := range twinInterfaces {
+		twinInstanceData = r.addTwinData(twinInstanceData, twinInterface, "")
 
-		for _, twinRelationship := range twinInterface.Spec.Relationships {
-			twinInstanceRelationship = append(twinInstanceRelationship, apiv0.TwinInstanceRelationship{
-				Name:      twinRelationship.Name + INSTANCE_SUFFIX,
-				Interface: twinRelationship.Interface,
-				Instance:  twinRelationship.Interface + INSTANCE_SUFFIX,
-			})
+		for _, twinComponent := range twinInterface.Spec.Components {
+			for _, componentTwinInterface := range componentTwinInterfaces[twinComponent.Name] {
+				twinInstanceData = r.addTwinData(twinInstanceData, componentTwinInterface, twinComponent.Name)
+			}
 		}
 	}
 
-	return twinInstanceRelationship
+	return twinInstanceData
 }
 
-func (r *resourceBuilder) getTwinData(twinInterfaces []apiv0.TwinInterface) *apiv0.TwinInstanceDataSpec {
-	var twinInstanceData *apiv0.TwinInstanceDataSpec
+// Add the properties and telemetries of the TwinInterface to the TwinInstance data. The contents of components
+// are named with the component name as prefix.
+func (r *resourceBuilder) addTwinData(twinInstanceData *apiv0.TwinInstanceDataSpec, twinInterface apiv0.TwinInterface, componentName string) *apiv0.TwinInstanceDataSpec {
+	if len(twinInterface.Spec.Properties) == 0 && len(twinInterface.Spec.Telemetries) == 0 {
+		return twinInstanceData
+	}
 
-	for _, twinInterface := range twinInterfaces {
-		if len(twinInterface.Spec.Properties) == 0 && len(twinInterface.Spec.Telemetries) == 0 {
-			continue
-		}
+	if twinInstanceData == nil {
+		twinInstanceData = &apiv0.TwinInstanceDataSpec{}
+	}
 
-		if twinInstanceData == nil {
-			twinInstanceData = &apiv0.TwinInstanceDataSpec{}
+	getName := func(name string) string {
+		if componentName == "" {
+			return name
 		}
+		return inheritance.GetComponentQualifiedName(componentName, name)
+	}
 
-		for _, twinProperty := range twinInterface.Spec.Properties {
-			twinInstanceData.Properties = append(twinInstanceData.Properties, apiv0.TwinInstancePropertyData{
-				Id:   twinProperty.Id,
-				Name: twinProperty.Name,
-			})
-		}
+	for _, twinProperty := range twinInterface.Spec.Properties {
+		twinInstanceData.Properties = append(twinInstanceData.Properties, apiv0.TwinInstancePropertyData{
+			Id:   twinProperty.Id,
+			Name: getName(twinProperty.Name),
+		})
+	}
 
-		for _, twinTelemetry := range twinInterface.Spec.Telemetries {
-			twinInstanceData.Telemetries = append(twinInstanceData.Telemetries, apiv0.TwinInstanceTelemetryData{
-				Id:   twinTelemetry.Id,
-				Name: twinTelemetry.Name,
-			})
-		}
+	for _, twinTelemetry := range twinInterface.Spec.Telemetries {
+		twinInstanceData.Telemetries = append(twinInstanceData.Telemetries, apiv0.TwinInstanceTelemetryData{
+			Id:   twinTelemetry.Id,
+			Name: getName(twinTelemetry.Name),
+		})
 	}
 
 	return twinInstanceData
@@ -203,15 +227,31 @@ func (r *resourceBuilder) processCommand(command dtdl.Command, commands []apiv0.
 	return commands
 }
 
+func (r *resourceBuilder) processComponent(component dtdl.Component, components []apiv0.TwinComponent) []apiv0.TwinComponent {
+	// The component schema is the DTMI of the component interface
+	newComponent := apiv0.TwinComponent{
+		Id:          string(component.Id),
+		Comment:     component.Comment,
+		Description: string(component.Description),
+		DisplayName: string(component.DisplayName),
+		Name:        component.Name,
+		Interface:   r.hostUtils.ParseHostName(component.Schema.DefaultSchemaValue),
+	}
+
+	return append(components, newComponent)
+}
+
 func (r *resourceBuilder) processTelemetry(telemetry dtdl.Telemetry, telemetries []apiv0.TwinTelemetry) []apiv0.TwinTelemetry {
 	twinSchema := r.createTwinSchema(telemetry.Schema)
 	newTelemetry := apiv0.TwinTelemetry{
-		Id:          string(telemetry.Id),
-		Comment:     telemetry.Comment,
-		Description: string(telemetry.Description),
-		DisplayName: string(telemetry.DisplayName),
-		Name:        telemetry.Name,
-		Schema:      twinSchema,
+		Id:            string(telemetry.Id),
+		Comment:       telemetry.Comment,
+		Description:   string(telemetry.Description),
+		DisplayName:   string(telemetry.DisplayName),
+		Name:          telemetry.Name,
+		Schema:        twinSchema,
+		SemanticTypes: dtdl.GetSemanticTypes(telemetry.Type),
+		Unit:          telemetry.Unit,
 	}
 	telemetries = append(telemetries, newTelemetry)
 	return telemetries
@@ -248,13 +288,15 @@ func (r *resourceBuilder) processRelationship(relationship dtdl.Relationship, re
 func (r *resourceBuilder) processProperty(property dtdl.Property, properties []apiv0.TwinProperty) []apiv0.TwinProperty {
 	twinSchema := r.createTwinSchema(property.Schema)
 	newProperty := apiv0.TwinProperty{
-		Id:          string(property.Id),
-		Comment:     property.Comment,
-		Description: string(property.Description),
-		DisplayName: string(property.DisplayName),
-		Name:        property.Name,
-		Writeable:   property.Writeable,
-		Schema:      twinSchema,
+		Id:            string(property.Id),
+		Comment:       property.Comment,
+		Description:   string(property.Description),
+		DisplayName:   string(property.DisplayName),
+		Name:          property.Name,
+		Writeable:     property.Writeable,
+		Schema:        twinSchema,
+		SemanticTypes: dtdl.GetSemanticTypes(property.Type),
+		Unit:          property.Unit,
 	}
 	properties = append(properties, newProperty)
 	return properties
@@ -280,7 +322,7 @@ func (r *resourceBuilder) createTwinSchema(schema dtdl.Schema) *apiv0.TwinSchema
 
 	if len(twinEnumSchemaValues) > 1 || schema.EnumSchema.ValueSchema != "" {
 		twinEnumSchema = &apiv0.TwinEnumSchema{
-			ValueSchema: apiv0.PrimitiveType(schema.EnumSchema.ValueSchema),
+			ValueSchema: r.createPrimitiveType(schema.EnumSchema.ValueSchema),
 			EnumValues:  twinEnumSchemaValues,
 		}
 	}
@@ -290,10 +332,10 @@ func (r *resourceBuilder) createTwinSchema(schema dtdl.Schema) *apiv0.TwinSchema
 
 	for _, fieldValue := range schema.ObjectSchema.Fields {
 		twinObjectField := apiv0.TwinComplexTypeFields{
-			Name: fieldValue.Name,
-			Schema: &apiv0.TwinComplexTypeSchema{
-				PrimitiveType: apiv0.PrimitiveType(fieldValue.Schema),
-			},
+			Name:        fieldValue.Name,
+			DisplayName: fieldValue.DisplayName,
+			Description: fieldValue.Description,
+			Schema:      r.createTwinSchema(fieldValue.Schema),
 		}
 		twinComplexTypeFields = append(twinComplexTypeFields, twinObjectField)
 	}
@@ -305,15 +347,50 @@ func (r *resourceBuilder) createTwinSchema(schema dtdl.Schema) *apiv0.TwinSchema
 		}
 	}
 
+	var twinArraySchema *apiv0.TwinArraySchema
+
+	if schema.ArraySchema.Type != "" {
+		twinArraySchema = &apiv0.TwinArraySchema{}
+		if schema.ArraySchema.ElementSchema != nil {
+			twinArraySchema.ElementSchema = r.createTwinSchema(*schema.ArraySchema.ElementSchema)
+		}
+	}
+
+	var twinMapSchema *apiv0.TwinMapSchema
+
+	if schema.MapSchema.Type != "" {
+		twinMapSchema = &apiv0.TwinMapSchema{
+			MapKey: apiv0.TwinMapKey{
+				Name: schema.MapSchema.MapKey.Name,
+			},
+			MapValue: apiv0.TwinMapValue{
+				Name: schema.MapSchema.MapValue.Name,
+			},
+		}
+		if schema.MapSchema.MapValue.Schema != nil {
+			twinMapSchema.MapValue.Schema = r.createTwinSchema(*schema.MapSchema.MapValue.Schema)
+		}
+	}
+
 	twinSchema := &apiv0.TwinSchema{
-		PrimitiveType: apiv0.PrimitiveType(schema.DefaultSchemaValue),
+		PrimitiveType: r.createPrimitiveType(schema.DefaultSchemaValue),
 		EnumType:      twinEnumSchema,
 		ComplexType:   twinComplexTypeSchema,
+		ArrayType:     twinArraySchema,
+		MapType:       twinMapSchema,
 	}
 
 	return twinSchema
 }
 
-func newIntPtr(value int) *int {
-	return &value
+func (r *resourceBuilder) createPrimitiveType(schema string) apiv0.PrimitiveType {
+	if primitiveType, ok := v3PrimitiveSchemas[schema]; ok {
+		return primitiveType
+	}
+	return apiv0.PrimitiveType(schema)
+}
+
+// Return the DTMI of a TwinInterface generated from a DTDL interface
+func GetTwinInterfaceDTMI(twinInterface apiv0.TwinInterface) string {
+	return twinInterface.Annotations[DTMI_ANNOTATION]
 }
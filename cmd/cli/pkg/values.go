@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	apiv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+)
+
+var (
+	ErrInvalidValue     = errors.New("Value does not match the schema")
+	ErrInvalidEnumValue = errors.New("Value is not one of the enum values")
+)
+
+// ISO 8601 durations, such as P1DT2H or PT0.5S
+var durationRegexp = regexp.MustCompile(`^P(?:\d+Y)?(?:\d+M)?(?:\d+W)?(?:\d+D)?(?:T(?:\d+H)?(?:\d+M)?(?:\d+(?:\.\d+)?S)?)?$`)
+
+// Convert a value to the representation of the property schema in the TwinInstance data. Primitive values are
+// parsed and formatted back, enum values are informed by name or value and complex values must be JSON documents.
+// Empty values are kept empty.
+func ConvertPropertyValue(schema *apiv0.TwinSchema, value string) (string, error) {
+	if value == "" || schema == nil {
+		return value, nil
+	}
+
+	if schema.EnumType != nil {
+		for _, enumValue := range schema.EnumType.EnumValues {
+			if value == enumValue.Name || value == enumValue.EnumValue {
+				return enumValue.EnumValue, nil
+			}
+		}
+		return "", fmt.Errorf("%w %q", ErrInvalidEnumValue, value)
+	}
+
+	if schema.ComplexType != nil || schema.ArrayType != nil || schema.MapType != nil {
+		return convertJsonValue(value)
+	}
+
+	return convertPrimitiveValue(schema.PrimitiveType, value)
+}
+
+func convertPrimitiveValue(primitiveType apiv0.PrimitiveType, value string) (string, error) {
+	invalidValue := fmt.Errorf("%w %s: %q", ErrInvalidValue, primitiveType, value)
+
+	switch primitiveType {
+	case apiv0.Boolean:
+		parsedValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", invalidValue
+		}
+		return strconv.FormatBool(parsedValue), nil
+	case apiv0.Integer, apiv0.Long:
+		bitSize := 64
+		if primitiveType == apiv0.Integer {
+			bitSize = 32
+		}
+		parsedValue, err := strconv.ParseInt(value, 10, bitSize)
+		if err != nil {
+			return "", invalidValue
+		}
+		return strconv.FormatInt(parsedValue, 10), nil
+	case apiv0.Double, apiv0.Float:
+		bitSize := 64
+		if primitiveType == apiv0.Float {
+			bitSize = 32
+		}
+		parsedValue, err := strconv.ParseFloat(value, bitSize)
+		if err != nil {
+			return "", invalidValue
+		}
+		return strconv.FormatFloat(parsedValue, 'g', -1, bitSize), nil
+	case apiv0.Date:
+		return convertTimeValue("2006-01-02", value, invalidValue)
+	case apiv0.DateTime:
+		return convertTimeValue(time.RFC3339Nano, value, invalidValue)
+	case apiv0.Time:
+		return convertTimeValue("15:04:05.999999999", value, invalidValue)
+	case apiv0.Duration:
+		if !durationRegexp.MatchString(value) || value == "P" || value[len(value)-1] == 'T' {
+			return "", invalidValue
+		}
+		return value, nil
+	case apiv0.Point, apiv0.MultiPoint, apiv0.LineString, apiv0.MultiLineString, apiv0.Polygon, apiv0.MultiPolygon:
+		// Geospatial values are GeoJSON geometries
+		return convertJsonValue(value)
+	}
+
+	return value, nil
+}
+
+func convertTimeValue(layout string, value string, invalidValue error) (string, error) {
+	parsedValue, err := time.Parse(layout, value)
+	if err != nil {
+		return "", invalidValue
+	}
+	return parsedValue.Format(layout), nil
+}
+
+func convertJsonValue(value string) (string, error) {
+	compactValue := new(bytes.Buffer)
+
+	if err := json.Compact(compactValue, []byte(value)); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidValue, err)
+	}
+
+	return compactValue.String(), nil
+}
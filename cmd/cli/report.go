@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func printParseErrorsReport(parseErrors []error) {
+	fmt.Fprintf(os.Stderr, "\nFound %d error(s) in the DTDL files:\n", len(parseErrors))
+
+	for _, err := range parseErrors {
+		fmt.Fprintf(os.Stderr, "  %s\n", err)
+	}
+}
+
+func printInstanceGraphErrorsReport(instanceGraphFile string, instanceGraphErrors []error) {
+	fmt.Fprintf(os.Stderr, "\nFound %d error(s) in the instance graph file %s:\n", len(instanceGraphErrors), instanceGraphFile)
+
+	for _, err := range instanceGraphErrors {
+		fmt.Fprintf(os.Stderr, "  %s\n", err)
+	}
+}
+
+func printExportErrorsReport(exportErrors []error) {
+	fmt.Fprintf(os.Stderr, "\nFound %d error(s) while exporting the TwinInterfaces:\n", len(exportErrors))
+
+	for _, err := range exportErrors {
+		fmt.Fprintf(os.Stderr, "  %s\n", err)
+	}
+}
+
+func printInventoryErrorsReport(inventoryFile string, inventoryErrors []error) {
+	fmt.Fprintf(os.Stderr, "\nFound %d error(s) in the inventory file %s:\n", len(inventoryErrors), inventoryFile)
+
+	for _, err := range inventoryErrors {
+		fmt.Fprintf(os.Stderr, "  %s\n", err)
+	}
+}
+
+func printConfigErrorReport(configFile string, err error) {
+	fmt.Fprintf(os.Stderr, "\nFound an error in the config file %s:\n  %s\n", configFile, err)
+}
@@ -29,6 +29,13 @@ func (sa *StringArray) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	*sa = []string{stringValue}
+	var value string
+	err = json.Unmarshal(data, &value)
+
+	if err != nil {
+		return ErrUnmarshalNotSupported
+	}
+
+	*sa = []string{value}
 	return nil
 }
@@ -1,9 +1,10 @@
 package utils
 
 import (
-	"log"
-	"regexp"
+	"errors"
 	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func NewHostUtils() HostUtils {
@@ -12,16 +13,18 @@ func NewHostUtils() HostUtils {
 
 type HostUtils interface {
 	ParseHostName(hostName string) string
+	ValidateHostName(hostName string) error
 }
 
 type hostUtils struct{}
 
-// Parse the string and make it compliant with RFC 1123 host names, by removing invalid characters
+// Parse the string and make it compliant with RFC 1123 host names, by removing invalid characters.
+// Distinct strings may result in the same host name, such as dtmi:a:b;1 and dtmi:a_b;1.
 func (r *hostUtils) ParseHostName(name string) string {
 	newName := strings.ToLower(name)
 
 	// Replace character by hyphen
-	invalidCharacters := []string{":", ";", "_"}
+	invalidCharacters := []string{":", ";", "_", "."}
 	for _, invalidString := range invalidCharacters {
 		newName = strings.Replace(newName, invalidString, "-", -1)
 	}
@@ -32,11 +35,14 @@ func (r *hostUtils) ParseHostName(name string) string {
 		newName = strings.Replace(newName, nowAllowedString, "", -1)
 	}
 
-	_, err := regexp.MatchString("[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*", newName)
+	return newName
+}
 
-	if err != nil {
-		log.Fatal("Error matching host name:", err.Error())
+// Validate that the host name can name the TwinInterface resources, including its Knative service,
+// which requires a RFC 1035 label
+func (r *hostUtils) ValidateHostName(hostName string) error {
+	if messages := validation.IsDNS1035Label(hostName); len(messages) > 0 {
+		return errors.New(strings.Join(messages, ", "))
 	}
-
-	return newName
+	return nil
 }
@@ -33,8 +33,10 @@ import (
 
 	corev0 "github.com/Open-Digital-Twin/ktwin-operator/api/core/v0"
 	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
 	corecontroller "github.com/Open-Digital-Twin/ktwin-operator/internal/controller/core"
 	dtdcontroller "github.com/Open-Digital-Twin/ktwin-operator/internal/controller/dtd"
+	dtdwebhook "github.com/Open-Digital-Twin/ktwin-operator/internal/webhook/dtd"
 	"github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
 	eventStore "github.com/Open-Digital-Twin/ktwin-operator/pkg/event-store"
 	"github.com/Open-Digital-Twin/ktwin-operator/pkg/service"
@@ -54,6 +56,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(dtdv0.AddToScheme(scheme))
+	utilruntime.Must(dtdv1.AddToScheme(scheme))
 	utilruntime.Must(corev0.AddToScheme(scheme))
 
 	// Third party
@@ -84,11 +87,17 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var pruneDryRun bool
+	var allowPendingRelationships bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&pruneDryRun, "prune-dry-run", false,
+		"Only report the stale TwinInterface bindings instead of deleting them.")
+	flag.BoolVar(&allowPendingRelationships, "allow-pending-relationships", false,
+		"Accept TwinInterface relationships to TwinInterfaces not created yet with a warning instead of rejecting them.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -127,6 +136,8 @@ func main() {
 		TwinService: service.NewTwinService(),
 		TwinEvent:   event.NewTwinEvent(),
 		EventStore:  eventStore.NewEventStore(),
+		Recorder:    mgr.GetEventRecorderFor("twininterface-controller"),
+		PruneDryRun: pruneDryRun,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TwinInterface")
 		os.Exit(1)
@@ -159,10 +170,35 @@ func main() {
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
 		EventStore: eventStore.NewEventStore(),
+		Recorder:   mgr.GetEventRecorderFor("eventstore-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EventStore")
 		os.Exit(1)
 	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&dtdwebhook.TwinInterfaceValidator{
+			Client:                    mgr.GetClient(),
+			TwinEvent:                 event.NewTwinEvent(),
+			AllowPendingRelationships: allowPendingRelationships,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TwinInterface")
+			os.Exit(1)
+		}
+		if err = (&dtdwebhook.TwinInstanceValidator{
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TwinInstance")
+			os.Exit(1)
+		}
+		if err = (&dtdv1.TwinInterface{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "TwinInterface")
+			os.Exit(1)
+		}
+		if err = (&dtdv1.TwinInstance{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "TwinInstance")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
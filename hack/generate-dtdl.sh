@@ -1,4 +1,4 @@
 make generate-dtdl \
     INPUT_FOLDER=../examples/opendigitaltwins-smartcities/Ontology \
     OUTPUT_FOLDER=../examples/opendigitaltwins-smartcities-output \
-    INSTANCE_GRAPH_FILE=dtdl-instance-graph.txt
\ No newline at end of file
+    INSTANCE_GRAPH_FILE=hack/dtdl-instance-graph.json
\ No newline at end of file
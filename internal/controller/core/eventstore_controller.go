@@ -4,15 +4,22 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	corev0 "github.com/Open-Digital-Twin/ktwin-operator/api/core/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/apply"
 	eventStore "github.com/Open-Digital-Twin/ktwin-operator/pkg/event-store"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/status"
 	keventing "knative.dev/eventing/pkg/apis/eventing/v1"
 	kserving "knative.dev/serving/pkg/apis/serving/v1"
 )
@@ -22,11 +29,13 @@ type EventStoreReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	EventStore eventStore.EventStore
+	Recorder   record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=core.ktwin,resources=eventstores,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core.ktwin,resources=eventstores/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core.ktwin,resources=eventstores/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *EventStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -49,62 +58,111 @@ func (r *EventStoreReconciler) createOrUpdateEventStoreResources(ctx context.Con
 	logger := log.FromContext(ctx)
 
 	newKService := r.EventStore.GetEventStoreService(&eventStore)
+	err := r.applyChildResource(ctx, &eventStore, newKService, "Service")
 
-	err := r.Create(ctx, newKService, &client.CreateOptions{})
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying Event Store service %s", eventStore.Name))
+		return r.updateEventStoreStatus(ctx, eventStore, err)
+	}
 
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating Event Store service %s", eventStore.Name))
-		return ctrl.Result{}, err
-	} else if err != nil {
-		currentKService := &kserving.Service{}
-		err := r.Get(ctx, types.NamespacedName{Namespace: eventStore.Namespace, Name: eventStore.Name}, currentKService)
-		if err != nil {
-			logger.Error(err, fmt.Sprintf("Error while getting current Event Store service %s", eventStore.Name))
-			return ctrl.Result{}, err
+	newTrigger := r.EventStore.GetEventStoreTrigger(&eventStore)
+	err = r.applyChildResource(ctx, &eventStore, newTrigger, "Trigger")
+
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying trigger for event store %s", eventStore.Name))
+		return r.updateEventStoreStatus(ctx, eventStore, err)
+	}
+
+	return r.updateEventStoreStatus(ctx, eventStore, nil)
+}
+
+// Apply an EventStore child resource, recording a Kubernetes Event when it is created or updated
+func (r *EventStoreReconciler) applyChildResource(ctx context.Context, eventStore *corev0.EventStore, resource client.Object, kind string) error {
+	logger := log.FromContext(ctx)
+	result, err := apply.Apply(ctx, r.Client, resource)
+
+	if err != nil {
+		r.Recorder.Eventf(eventStore, corev1.EventTypeWarning, status.EVENT_REASON_APPLY_FAILED, "Failed to apply %s %s: %s", kind, resource.GetName(), err)
+		return err
+	}
+
+	switch result {
+	case controllerutil.OperationResultCreated:
+		logger.Info(fmt.Sprintf("Event Store %s %s created", kind, resource.GetName()))
+		r.Recorder.Eventf(eventStore, corev1.EventTypeNormal, status.EVENT_REASON_CREATED, "Created %s %s", kind, resource.GetName())
+	case controllerutil.OperationResultUpdated:
+		logger.Info(fmt.Sprintf("Event Store %s %s updated", kind, resource.GetName()))
+		r.Recorder.Eventf(eventStore, corev1.EventTypeNormal, status.EVENT_REASON_UPDATED, "Updated %s %s", kind, resource.GetName())
+	}
+
+	return nil
+}
+
+// Update the EventStore conditions from the current state of its Service and Trigger.
+// The reconcile error, if any, is returned after the status is written so the request is requeued.
+func (r *EventStoreReconciler) updateEventStoreStatus(ctx context.Context, eventStore corev0.EventStore, reconcileErr error) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	generation := eventStore.Generation
+	var conditions []metav1.Condition
+
+	kService := &kserving.Service{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: eventStore.Namespace, Name: eventStore.Name}, kService)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Error while getting Event Store service %s", eventStore.Name))
+		}
+		conditions = append(conditions, status.NewServiceReadyCondition(nil, generation))
+		eventStore.Status.ServiceURL = ""
+	} else {
+		conditions = append(conditions, status.NewServiceReadyCondition(kService, generation))
+		if kService.Status.URL != nil {
+			eventStore.Status.ServiceURL = kService.Status.URL.String()
 		}
+	}
 
-		currentKService = r.EventStore.MergeEventStoreService(currentKService, newKService)
-		err = r.Update(ctx, currentKService, &client.UpdateOptions{})
-		if err != nil {
-			logger.Error(err, fmt.Sprintf("Error while updating Event Store service %s", eventStore.Name))
-			return ctrl.Result{}, err
+	trigger := &keventing.Trigger{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: eventStore.Namespace, Name: eventStore.Name + "-trigger"}, trigger)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Error while getting Event Store trigger %s", eventStore.Name+"-trigger"))
 		}
+		conditions = append(conditions, status.NewTriggerReadyCondition(nil, generation))
 	} else {
-		logger.Info(fmt.Sprintf("Event Store %s created", eventStore.Name))
+		conditions = append(conditions, status.NewTriggerReadyCondition(trigger, generation))
 	}
 
-	newTrigger := r.EventStore.GetEventStoreTrigger(&eventStore)
-	err = r.Create(ctx, newTrigger, &client.CreateOptions{})
+	if reconcileErr != nil {
+		r.Recorder.Eventf(&eventStore, corev1.EventTypeWarning, status.EVENT_REASON_RECONCILE_FAILED, "Failed to reconcile Event Store %s: %s", eventStore.Name, reconcileErr)
+	}
 
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating trigger for event store %s", eventStore.Name))
-		return ctrl.Result{}, err
-	} else if err != nil {
-		currentTrigger := &keventing.Trigger{}
-		err := r.Get(ctx, types.NamespacedName{Namespace: eventStore.Namespace, Name: eventStore.Name + "-trigger"}, currentTrigger)
-		if err != nil {
-			logger.Error(err, fmt.Sprintf("Error while getting current Event Store trigger %s", eventStore.Name+"-trigger"))
-			return ctrl.Result{}, err
-		}
+	status.SetConditions(&eventStore.Status.Conditions, generation, conditions...)
+	eventStore.Status.ObservedGeneration = generation
 
-		currentTrigger = r.EventStore.MergeEventStoreTrigger(currentTrigger, newTrigger)
-		err = r.Update(ctx, currentTrigger, &client.UpdateOptions{})
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		currentEventStore := &corev0.EventStore{}
+		err := r.Get(ctx, client.ObjectKeyFromObject(&eventStore), currentEventStore)
 		if err != nil {
-			logger.Error(err, fmt.Sprintf("Error while updating trigger for event store %s", eventStore.Name))
-			return ctrl.Result{}, err
-		} else {
-			logger.Info(fmt.Sprintf("Event Store %s updated", eventStore.Name))
+			return err
 		}
-	} else {
-		logger.Info(fmt.Sprintf("Event Store trigger %s created", eventStore.Name))
+
+		eventStoreBase := currentEventStore.DeepCopy()
+		currentEventStore.Status = eventStore.Status
+		return r.Status().Patch(ctx, currentEventStore, client.MergeFromWithOptions(eventStoreBase, client.MergeFromWithOptimisticLock{}))
+	})
+
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while updating Event Store %s status", eventStore.Name))
+		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{}, reconcileErr
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *EventStoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev0.EventStore{}).
+		Owns(&kserving.Service{}).
+		Owns(&keventing.Trigger{}).
 		Complete(r)
 }
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev0 "github.com/Open-Digital-Twin/ktwin-operator/api/core/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/status"
+
+	keventing "knative.dev/eventing/pkg/apis/eventing/v1"
+	kserving "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const (
+	timeout  = time.Second * 10
+	interval = time.Millisecond * 250
+)
+
+// Create the namespace of the core resources, once for all the specs
+func createKtwinNamespace(ctx context.Context) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ktwin"}}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, namespace))).To(Succeed())
+}
+
+// Expect the resource to be controlled by the owner, so it is garbage collected and its changes reconcile the owner
+func expectControlledBy(g Gomega, resource client.Object, owner client.Object, ownerKind string) {
+	controllerRef := metav1.GetControllerOf(resource)
+	g.Expect(controllerRef).NotTo(BeNil(), resource.GetName())
+	g.Expect(controllerRef.Kind).To(Equal(ownerKind))
+	g.Expect(controllerRef.UID).To(Equal(owner.GetUID()))
+}
+
+// Expect an Event recorded for the resource, as listed by kubectl describe
+func expectEvent(g Gomega, ctx context.Context, resource client.Object, eventType string, reason string, message string) {
+	eventList := corev1.EventList{}
+	g.Expect(k8sClient.List(ctx, &eventList, client.InNamespace(resource.GetNamespace()))).To(Succeed())
+
+	var events []string
+	for _, recordedEvent := range eventList.Items {
+		if recordedEvent.InvolvedObject.UID == resource.GetUID() {
+			events = append(events, recordedEvent.Type+" "+recordedEvent.Reason+" "+recordedEvent.Message)
+		}
+	}
+
+	g.Expect(events).To(ContainElement(HavePrefix(eventType + " " + reason + " " + message)))
+}
+
+var _ = Describe("EventStore controller", func() {
+	ctx := context.Background()
+
+	It("Should report the conditions of the EventStore resources in its status", func() {
+		createKtwinNamespace(ctx)
+
+		timeoutSeconds := 30
+		eventStore := &corev0.EventStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "event-store", Namespace: "ktwin"},
+			Spec:       corev0.EventStoreSpec{Timeout: &timeoutSeconds},
+		}
+		Expect(k8sClient.Create(ctx, eventStore)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			currentEventStore := &corev0.EventStore{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(eventStore), currentEventStore)).To(Succeed())
+			g.Expect(currentEventStore.Status.ObservedGeneration).To(Equal(currentEventStore.Generation))
+
+			// The Service and Trigger are not ready without the Knative controllers
+			for _, conditionType := range []string{status.CONDITION_SERVICE_READY, status.CONDITION_TRIGGER_READY} {
+				condition := meta.FindStatusCondition(currentEventStore.Status.Conditions, conditionType)
+				g.Expect(condition).NotTo(BeNil(), conditionType)
+				g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(condition.Reason).To(Equal(status.REASON_NOT_READY))
+			}
+		}, timeout, interval).Should(Succeed())
+
+		By("Recording an Event for each created resource")
+		Eventually(func(g Gomega) {
+			expectEvent(g, ctx, eventStore, corev1.EventTypeNormal, status.EVENT_REASON_CREATED, "Created Service event-store")
+			expectEvent(g, ctx, eventStore, corev1.EventTypeNormal, status.EVENT_REASON_CREATED, "Created Trigger event-store-trigger")
+		}, timeout, interval).Should(Succeed())
+	})
+
+	It("Should recreate the owned resources deleted from the cluster", func() {
+		createKtwinNamespace(ctx)
+
+		timeoutSeconds := 30
+		eventStore := &corev0.EventStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "city-event-store", Namespace: "ktwin"},
+			Spec:       corev0.EventStoreSpec{Timeout: &timeoutSeconds},
+		}
+		Expect(k8sClient.Create(ctx, eventStore)).To(Succeed())
+
+		resources := []client.Object{
+			&kserving.Service{ObjectMeta: metav1.ObjectMeta{Name: "city-event-store", Namespace: "ktwin"}},
+			&keventing.Trigger{ObjectMeta: metav1.ObjectMeta{Name: "city-event-store-trigger", Namespace: "ktwin"}},
+		}
+
+		for _, resource := range resources {
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+				expectControlledBy(g, resource, eventStore, "EventStore")
+			}, timeout, interval).Should(Succeed())
+		}
+
+		for _, resource := range resources {
+			By(fmt.Sprintf("Recreating the deleted %T %s", resource, resource.GetName()))
+			deletedUID := resource.GetUID()
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+				g.Expect(resource.GetUID()).NotTo(Equal(deletedUID))
+				expectControlledBy(g, resource, eventStore, "EventStore")
+			}, timeout, interval).Should(Succeed())
+		}
+	})
+})
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	corev0 "github.com/Open-Digital-Twin/ktwin-operator/api/core/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/apply"
 	"github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
 	"github.com/Open-Digital-Twin/ktwin-operator/pkg/naming"
 	"github.com/Open-Digital-Twin/ktwin-operator/pkg/third-party/rabbitmq"
@@ -93,21 +94,21 @@ func (r *MQTTTriggerReconciler) createOrUpdateMQTTTrigger(ctx context.Context, r
 	mqttDispacherDeployment := r.getMQQTDispatcherDeployment(mqttTrigger, rabbitMQSecret, defaultBrokerExchange)
 	mqttDispacherService := r.getMQQTDispatcherService(mqttTrigger)
 
-	err = r.Create(ctx, mqttDispatcherQueue, &client.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating mqtt dispatcher queue %s", mqttTrigger.Name))
+	err = apply.Patch(ctx, r.Client, mqttDispatcherQueue)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying mqtt dispatcher queue %s", mqttTrigger.Name))
 		return ctrl.Result{}, err
 	}
 
-	err = r.Create(ctx, &mqttDispacherDeployment, &client.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating mqtt dispatcher deployment %s", mqttTrigger.Name))
+	err = apply.Patch(ctx, r.Client, &mqttDispacherDeployment)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying mqtt dispatcher deployment %s", mqttTrigger.Name))
 		return ctrl.Result{}, err
 	}
 
-	err = r.Create(ctx, &mqttDispacherService, &client.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating mqtt dispatcher service %s", mqttTrigger.Name))
+	err = apply.Patch(ctx, r.Client, &mqttDispacherService)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying mqtt dispatcher service %s", mqttTrigger.Name))
 		return ctrl.Result{}, err
 	}
 
@@ -116,27 +117,31 @@ func (r *MQTTTriggerReconciler) createOrUpdateMQTTTrigger(ctx context.Context, r
 	ceDispacherDeployment := r.getCloudEventDispatcherDeployment(mqttTrigger, rabbitMQSecret)
 	ceDispacherService := r.geCloudEventDispatcherService(mqttTrigger)
 
-	err = r.Create(ctx, ceDispatcherQueue, &client.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating cloud event dispatcher queue %s", mqttTrigger.Name))
+	err = apply.Patch(ctx, r.Client, ceDispatcherQueue)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying cloud event dispatcher queue %s", mqttTrigger.Name))
 		return ctrl.Result{}, err
 	}
 
-	err = r.Create(ctx, &ceDispacherDeployment, &client.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating cloud event dispatcher deployment %s", mqttTrigger.Name))
+	err = apply.Patch(ctx, r.Client, &ceDispacherDeployment)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying cloud event dispatcher deployment %s", mqttTrigger.Name))
 		return ctrl.Result{}, err
 	}
 
-	err = r.Create(ctx, &ceDispacherService, &client.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		logger.Error(err, fmt.Sprintf("Error while creating cloud event dispatcher service %s", mqttTrigger.Name))
+	err = apply.Patch(ctx, r.Client, &ceDispacherService)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while applying cloud event dispatcher service %s", mqttTrigger.Name))
 		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
+func (r *MQTTTriggerReconciler) getMQTTTriggerOwnerReference(mqttTrigger corev0.MQTTTrigger) metav1.OwnerReference {
+	return *metav1.NewControllerRef(&mqttTrigger, corev0.GroupVersion.WithKind("MQTTTrigger"))
+}
+
 func (r *MQTTTriggerReconciler) getMQQTDispatcherQueue(mqttTrigger corev0.MQTTTrigger) *rabbitmqv1beta1.Queue {
 	args := &rabbitmq.QueueArgs{
 		Name:          event.MQTT_DISPATCHER_QUEUE,
@@ -147,12 +152,7 @@ func (r *MQTTTriggerReconciler) getMQQTDispatcherQueue(mqttTrigger corev0.MQTTTr
 			Name:      "rabbitmq",
 			Namespace: mqttTrigger.Namespace,
 		},
-		Owner: metav1.OwnerReference{
-			APIVersion: mqttTrigger.APIVersion,
-			Kind:       mqttTrigger.Kind,
-			Name:       mqttTrigger.ObjectMeta.Name,
-			UID:        mqttTrigger.ObjectMeta.UID,
-		},
+		Owner:  r.getMQTTTriggerOwnerReference(mqttTrigger),
 		Labels: map[string]string{},
 	}
 	return rabbitmq.NewQueue(args)
@@ -160,20 +160,17 @@ func (r *MQTTTriggerReconciler) getMQQTDispatcherQueue(mqttTrigger corev0.MQTTTr
 
 func (r *MQTTTriggerReconciler) getMQQTDispatcherDeployment(mqttTrigger corev0.MQTTTrigger, rabbitMQSecret v1.Secret, defaultBrokerExchange rabbitmqv1beta1.Exchange) appsv1.Deployment {
 	return appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "mqtt-dispatcher",
 			Namespace: mqttTrigger.Namespace,
 			Labels: map[string]string{
 				"ktwin/trigger": "mqtt-dispatcher",
 			},
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: mqttTrigger.APIVersion,
-					Kind:       mqttTrigger.Kind,
-					Name:       mqttTrigger.ObjectMeta.Name,
-					UID:        mqttTrigger.ObjectMeta.UID,
-				},
-			},
+			OwnerReferences: []metav1.OwnerReference{r.getMQTTTriggerOwnerReference(mqttTrigger)},
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: int32Ptr(1),
@@ -269,12 +266,7 @@ func (r *MQTTTriggerReconciler) getCloudEventDispatcherQueue(mqttTrigger corev0.
 			Name:      "rabbitmq",
 			Namespace: mqttTrigger.Namespace,
 		},
-		Owner: metav1.OwnerReference{
-			APIVersion: mqttTrigger.APIVersion,
-			Kind:       mqttTrigger.Kind,
-			Name:       mqttTrigger.ObjectMeta.Name,
-			UID:        mqttTrigger.ObjectMeta.UID,
-		},
+		Owner:  r.getMQTTTriggerOwnerReference(mqttTrigger),
 		Labels: map[string]string{},
 	}
 	return rabbitmq.NewQueue(args)
@@ -282,20 +274,17 @@ func (r *MQTTTriggerReconciler) getCloudEventDispatcherQueue(mqttTrigger corev0.
 
 func (r *MQTTTriggerReconciler) getMQQTDispatcherService(mqttTrigger corev0.MQTTTrigger) v1.Service {
 	return v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      event.MQTT_DISPATCHER,
 			Namespace: mqttTrigger.Namespace,
 			Labels: map[string]string{
 				"ktwin/trigger": event.MQTT_DISPATCHER,
 			},
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: mqttTrigger.APIVersion,
-					Kind:       mqttTrigger.Kind,
-					Name:       mqttTrigger.ObjectMeta.Name,
-					UID:        mqttTrigger.ObjectMeta.UID,
-				},
-			},
+			OwnerReferences: []metav1.OwnerReference{r.getMQTTTriggerOwnerReference(mqttTrigger)},
 		},
 		Spec: v1.ServiceSpec{
 			Selector: map[string]string{
@@ -322,20 +311,17 @@ func (r *MQTTTriggerReconciler) getMQQTDispatcherService(mqttTrigger corev0.MQTT
 
 func (r *MQTTTriggerReconciler) getCloudEventDispatcherDeployment(mqttTrigger corev0.MQTTTrigger, rabbitMQSecret v1.Secret) appsv1.Deployment {
 	return appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      event.CLOUD_EVENT_DISPATCHER,
 			Namespace: mqttTrigger.Namespace,
 			Labels: map[string]string{
 				"ktwin/trigger": event.CLOUD_EVENT_DISPATCHER,
 			},
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: mqttTrigger.APIVersion,
-					Kind:       mqttTrigger.Kind,
-					Name:       mqttTrigger.ObjectMeta.Name,
-					UID:        mqttTrigger.ObjectMeta.UID,
-				},
-			},
+			OwnerReferences: []metav1.OwnerReference{r.getMQTTTriggerOwnerReference(mqttTrigger)},
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: int32Ptr(1),
@@ -423,20 +409,17 @@ func (r *MQTTTriggerReconciler) getCloudEventDispatcherDeployment(mqttTrigger co
 
 func (r *MQTTTriggerReconciler) geCloudEventDispatcherService(mqttTrigger corev0.MQTTTrigger) v1.Service {
 	return v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      event.CLOUD_EVENT_DISPATCHER,
 			Namespace: mqttTrigger.Namespace,
 			Labels: map[string]string{
 				"ktwin/trigger": event.CLOUD_EVENT_DISPATCHER,
 			},
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: mqttTrigger.APIVersion,
-					Kind:       mqttTrigger.Kind,
-					Name:       mqttTrigger.ObjectMeta.Name,
-					UID:        mqttTrigger.ObjectMeta.UID,
-				},
-			},
+			OwnerReferences: []metav1.OwnerReference{r.getMQTTTriggerOwnerReference(mqttTrigger)},
 		},
 		Spec: v1.ServiceSpec{
 			Selector: map[string]string{
@@ -460,5 +443,8 @@ func int32Ptr(i int32) *int32 { return &i }
 func (r *MQTTTriggerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev0.MQTTTrigger{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&v1.Service{}).
+		Owns(&rabbitmqv1beta1.Queue{}).
 		Complete(r)
 }
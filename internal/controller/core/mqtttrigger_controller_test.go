@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev0 "github.com/Open-Digital-Twin/ktwin-operator/api/core/v0"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+)
+
+// Create the RabbitMQ Secret and broker Exchange used by the dispatchers, once for all the specs
+func createMQTTTriggerDependencies(ctx context.Context) {
+	createKtwinNamespace(ctx)
+
+	rabbitMQSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq-default-user", Namespace: "ktwin"},
+		Data:       map[string][]byte{"host": []byte("rabbitmq.ktwin"), "port": []byte("5672")},
+	}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, rabbitMQSecret))).To(Succeed())
+
+	exchange := &rabbitmqv1beta1.Exchange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ktwin-broker-exchange",
+			Namespace: "ktwin",
+			Labels:    map[string]string{"eventing.knative.dev/broker": "ktwin"},
+		},
+		Spec: rabbitmqv1beta1.ExchangeSpec{
+			Name:                     "ktwin-broker-exchange",
+			RabbitmqClusterReference: rabbitmqv1beta1.RabbitmqClusterReference{Name: "rabbitmq"},
+		},
+	}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, exchange))).To(Succeed())
+}
+
+var _ = Describe("MQTTTrigger controller", func() {
+	ctx := context.Background()
+
+	It("Should recreate the dispatcher Deployment deleted from the cluster", func() {
+		createMQTTTriggerDependencies(ctx)
+
+		mqttTrigger := &corev0.MQTTTrigger{ObjectMeta: metav1.ObjectMeta{Name: "mqtt-trigger", Namespace: "ktwin"}}
+		Expect(k8sClient.Create(ctx, mqttTrigger)).To(Succeed())
+
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "mqtt-dispatcher", Namespace: "ktwin"}}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+			expectControlledBy(g, deployment, mqttTrigger, "MQTTTrigger")
+		}, timeout, interval).Should(Succeed())
+
+		By("Recreating the deleted Deployment")
+		deletedUID := deployment.UID
+		Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+			g.Expect(deployment.UID).NotTo(Equal(deletedUID))
+			expectControlledBy(g, deployment, mqttTrigger, "MQTTTrigger")
+		}, timeout, interval).Should(Succeed())
+	})
+})
@@ -23,15 +23,28 @@ import (
 	twinevent "github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
 	eventStore "github.com/Open-Digital-Twin/ktwin-operator/pkg/event-store"
 	twinservice "github.com/Open-Digital-Twin/ktwin-operator/pkg/service"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/status"
 
+	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 
 	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/apply"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/revision"
 )
 
 // TwinInstanceReconciler reconciles a TwinInstance object
@@ -62,12 +75,331 @@ func (r *TwinInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, err
+	if twinInstance.Status.Status == "" {
+		twinInstance.Status.Status = dtdv0.TwinInstancePhasePending
+		err = r.Status().Update(ctx, twinInstance, &client.SubResourceUpdateOptions{})
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while updating TwinInstance %s status", twinInstance.Name))
+			return ctrl.Result{}, err
+		}
+	}
+
+	return r.createUpdateTwinInstance(ctx, req, twinInstance)
+}
+
+func (r *TwinInstanceReconciler) createUpdateTwinInstance(ctx context.Context, req ctrl.Request, twinInstance *dtdv0.TwinInstance) (ctrl.Result, error) {
+	twinInstanceName := twinInstance.ObjectMeta.Name
+	originalTwinInstance := twinInstance.DeepCopy()
+
+	var resultErrors []error
+	var bindingNames []string
+	logger := log.FromContext(ctx)
+
+	// Resolve the TwinInterface of the TwinInstance
+	twinInterface := &dtdv0.TwinInterface{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: twinInstance.Namespace, Name: twinInstance.Spec.Interface}, twinInterface)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info(fmt.Sprintf("TwinInterface %s of TwinInstance %s not found. Requeueing request...", twinInstance.Spec.Interface, twinInstanceName))
+			return r.updateTwinInstanceStatus(ctx, twinInstance, originalTwinInstance, dtdv0.TwinInstancePhasePending, err)
+		}
+		logger.Error(err, fmt.Sprintf("Error while getting TwinInterface %s", twinInstance.Spec.Interface))
+		return r.updateTwinInstanceStatus(ctx, twinInstance, originalTwinInstance, dtdv0.TwinInstancePhaseFailed, err)
+	}
+
+	// The TwinInterface cleanup deletes the TwinInstance routing resources, which must not be applied again
+	if !twinInterface.DeletionTimestamp.IsZero() {
+		return r.skipTerminatingTwinInterface(ctx, twinInstance, originalTwinInstance, twinInterface)
+	}
+
+	// Resolve the version of the TwinInterface whose service processes the TwinInstance events
+	twinInterface, err = r.resolveTwinInterfaceVersion(ctx, twinInstance, twinInterface)
+
+	if err != nil {
+		logger.Info(fmt.Sprintf("TwinInterface %s of TwinInstance %s not resolved: %s. Requeueing request...", twinInstance.Spec.Interface, twinInstanceName, err))
+		return r.updateTwinInstanceStatus(ctx, twinInstance, originalTwinInstance, dtdv0.TwinInstancePhasePending, err)
+	}
+
+	twinInstance.Status.ResolvedInterface = twinInterface.Name
+
+	if !twinInterface.DeletionTimestamp.IsZero() {
+		return r.skipTerminatingTwinInterface(ctx, twinInstance, originalTwinInstance, twinInterface)
+	}
+
+	// Get Broker
+	broker := eventingv1.Broker{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: "ktwin", Name: twinevent.EVENT_BROKER_NAME}, &broker)
+
+	if err != nil {
+		logger.Error(err, "Error while getting Broker")
+		resultErrors = append(resultErrors, err)
+	}
+
+	// RabbitMQ Broker Secret
+	rabbitMQSecret := corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: "ktwin", Name: "rabbitmq-default-user"}, &rabbitMQSecret)
+
+	if err != nil {
+		logger.Error(err, "Error while getting rabbitmq default user secret")
+		resultErrors = append(resultErrors, err)
+	}
+
+	// Create Instance Trigger, if the TwinInterface has a service
+	twinInstanceTrigger := r.TwinEvent.GetTwinInstanceTrigger(twinInstance, twinInterface)
+	if twinInstanceTrigger != nil {
+		logger.Info(fmt.Sprintf("Applying Twin Instance Trigger %s", twinInstanceTrigger.Name))
+		err = apply.Patch(ctx, r.Client, twinInstanceTrigger)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while applying Twin Instance Trigger %s", twinInstanceName))
+			resultErrors = append(resultErrors, err)
+		}
+	}
+
+	// Create Instance MQTT Binding Rules
+	bindings := r.TwinEvent.GetTwinInstanceMQQTDispatcherBindings(twinInstance, twinInterface)
+	for _, binding := range bindings {
+		logger.Info(fmt.Sprintf("Applying Twin Instance MQTT Dispatcher Binding %s", binding.Name))
+		bindingNames = append(bindingNames, binding.Name)
+		err = apply.Patch(ctx, r.Client, &binding)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while applying Twin Instance MQTT Dispatcher Binding %s", binding.Name))
+			resultErrors = append(resultErrors, err)
+		}
+	}
+
+	// Create Instance Virtual Cloud Event Bindings
+	brokerExchange, err := r.getBrokerExchange(ctx, twinInstance)
+
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("No Broker Exchange found for TwinInstance %s", twinInstanceName))
+		resultErrors = append(resultErrors, err)
+	} else {
+		bindings := r.TwinEvent.GetTwinInstanceVirtualCloudEventBrokerBinding(twinInstance, twinInterface, brokerExchange)
+		for _, binding := range bindings {
+			logger.Info(fmt.Sprintf("Applying Twin Instance Virtual Cloud Event Binding %s", binding.Name))
+			bindingNames = append(bindingNames, binding.Name)
+			err = apply.Patch(ctx, r.Client, &binding)
+			if err != nil {
+				logger.Error(err, fmt.Sprintf("Error while applying Twin Instance Virtual Cloud Event Binding %s", binding.Name))
+				resultErrors = append(resultErrors, err)
+			}
+		}
+	}
+
+	// Set the endpoints the real twin must use to communicate, they are stored in the status of the v1 storage
+	// version and so they are written with the TwinInstance status
+	if len(resultErrors) == 0 {
+		twinInstance.Spec.EndpointSettings = r.TwinEvent.GetTwinInstanceEndpointSettings(twinInstance, twinInterface, broker, rabbitMQSecret)
+	}
+
+	// Set conditions from the current state of the created resources
+	r.setTwinInstanceConditions(ctx, twinInstance, twinInterface, twinInstanceTrigger, bindingNames)
+
+	if len(resultErrors) > 0 {
+		return r.updateTwinInstanceStatus(ctx, twinInstance, originalTwinInstance, dtdv0.TwinInstancePhaseFailed, resultErrors[0])
+	}
+
+	return r.updateTwinInstanceStatus(ctx, twinInstance, originalTwinInstance, dtdv0.TwinInstancePhaseRunning, nil)
+}
+
+func (r *TwinInstanceReconciler) setTwinInstanceConditions(
+	ctx context.Context,
+	twinInstance *dtdv0.TwinInstance,
+	twinInterface *dtdv0.TwinInterface,
+	twinInstanceTrigger *eventingv1.Trigger,
+	bindingNames []string,
+) {
+	logger := log.FromContext(ctx)
+	generation := twinInstance.Generation
+	var conditions []metav1.Condition
+
+	// Trigger
+	if twinInstanceTrigger != nil {
+		trigger := &eventingv1.Trigger{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: twinInstanceTrigger.Namespace, Name: twinInstanceTrigger.Name}, trigger)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				logger.Error(err, fmt.Sprintf("Error while getting Twin Instance Trigger %s", twinInstanceTrigger.Name))
+			}
+			conditions = append(conditions, status.NewTriggerReadyCondition(nil, generation))
+		} else {
+			conditions = append(conditions, status.NewTriggerReadyCondition(trigger, generation))
+		}
+	} else {
+		conditions = append(conditions, status.NewNotRequiredCondition(status.CONDITION_TRIGGER_READY, "TwinInterface has no service", generation))
+	}
+
+	// Bindings
+	bindingList := rabbitmqv1beta1.BindingList{}
+	err := r.List(ctx, &bindingList,
+		client.InNamespace(twinInstance.Namespace),
+		client.MatchingLabels{
+			"ktwin/twin-instance": twinInstance.Name,
+		},
+	)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing Bindings of TwinInstance %s", twinInstance.Name))
+		conditions = append(conditions, status.NewErrorCondition(status.CONDITION_BINDINGS_READY, err, generation))
+	} else {
+		conditions = append(conditions, status.NewBindingsReadyCondition(status.CONDITION_BINDINGS_READY, bindingNames, bindingList.Items, generation))
+	}
+
+	// Event Store, the instance events are persisted through the TwinInterface bindings
+	eventStoreCondition := meta.FindStatusCondition(twinInterface.Status.Conditions, status.CONDITION_EVENT_STORE_BOUND)
+	if eventStoreCondition != nil {
+		conditions = append(conditions, metav1.Condition{
+			Type:               status.CONDITION_EVENT_STORE_BOUND,
+			Status:             eventStoreCondition.Status,
+			Reason:             eventStoreCondition.Reason,
+			Message:            fmt.Sprintf("TwinInterface %s: %s", twinInterface.Name, eventStoreCondition.Message),
+			ObservedGeneration: generation,
+		})
+	}
+
+	status.SetConditions(&twinInstance.Status.Conditions, generation, conditions...)
+	twinInstance.Status.Bindings = bindingNames
+	twinInstance.Status.ObservedGeneration = generation
+}
+
+// Stop reconciling the TwinInstance while its TwinInterface is being deleted, the TwinInstance is reconciled
+// again when the TwinInterface is removed or recreated
+func (r *TwinInstanceReconciler) skipTerminatingTwinInterface(ctx context.Context, twinInstance *dtdv0.TwinInstance, originalTwinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info(fmt.Sprintf("TwinInterface %s of TwinInstance %s is being deleted. Skipping reconciliation...", twinInterface.Name, twinInstance.Name))
+
+	generation := twinInstance.Generation
+
+	status.SetConditions(&twinInstance.Status.Conditions, generation,
+		status.NewTwinInterfaceTerminatingCondition(status.CONDITION_TRIGGER_READY, twinInterface.Name, generation),
+		status.NewTwinInterfaceTerminatingCondition(status.CONDITION_BINDINGS_READY, twinInterface.Name, generation),
+	)
+	twinInstance.Status.Bindings = nil
+	twinInstance.Status.ObservedGeneration = generation
+
+	return r.updateTwinInstanceStatus(ctx, twinInstance, originalTwinInstance, dtdv0.TwinInstancePhasePending, nil)
+}
+
+// Return the TwinInterface of the version informed in the TwinInstance interfaceVersion, among the versions of
+// the model of the TwinInterface referenced by the TwinInstance
+func (r *TwinInstanceReconciler) resolveTwinInterfaceVersion(ctx context.Context, twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) (*dtdv0.TwinInterface, error) {
+	if twinInstance.Spec.InterfaceVersion == "" {
+		return twinInterface, nil
+	}
+
+	twinInterfaceList := dtdv0.TwinInterfaceList{}
+	err := r.List(ctx, &twinInterfaceList, client.InNamespace(twinInstance.Namespace))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return revision.ResolveTwinInterface(*twinInterface, twinInstance.Spec.InterfaceVersion, twinInterfaceList.Items)
+}
+
+func (r *TwinInstanceReconciler) getBrokerExchange(ctx context.Context, twinInstance *dtdv0.TwinInstance) (rabbitmqv1beta1.Exchange, error) {
+	exchangeList := rabbitmqv1beta1.ExchangeList{}
+	exchangeListOptions := []client.ListOption{
+		client.InNamespace(twinInstance.Namespace),
+		client.MatchingLabels(client.MatchingFields{
+			"eventing.knative.dev/broker": twinevent.EVENT_BROKER_NAME,
+		}),
+	}
+
+	err := r.List(ctx, &exchangeList, exchangeListOptions...)
+
+	if err != nil {
+		return rabbitmqv1beta1.Exchange{}, err
+	}
+
+	if len(exchangeList.Items) == 0 {
+		return rabbitmqv1beta1.Exchange{}, errors.NewNotFound(rabbitmqv1beta1.Resource("rabbitmqv1beta1.Exchange"), twinevent.EVENT_BROKER_NAME)
+	}
+
+	return exchangeList.Items[0], nil
+}
+
+// Update the TwinInstance phase, writing the status and the endpoint settings, and return the reconcile error,
+// if any, so the request is requeued
+func (r *TwinInstanceReconciler) updateTwinInstanceStatus(ctx context.Context, twinInstance *dtdv0.TwinInstance, originalTwinInstance *dtdv0.TwinInstance, phase dtdv0.TwinInstancePhase, reconcileErr error) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	twinInstance.Status.Status = phase
+	if !equality.Semantic.DeepEqual(originalTwinInstance.Status, twinInstance.Status) ||
+		!equality.Semantic.DeepEqual(originalTwinInstance.Spec.EndpointSettings, twinInstance.Spec.EndpointSettings) {
+		err := r.patchTwinInstanceStatus(ctx, twinInstance, originalTwinInstance)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while updating TwinInstance %s status", twinInstance.Name))
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, reconcileErr
+}
+
+// Patch the status of the TwinInstance in the v1 storage version, whose status has the endpoint settings of the v0
+// spec. The v0 status subresource keeps the stored spec, so the endpoint settings would not be written through it.
+func (r *TwinInstanceReconciler) patchTwinInstanceStatus(ctx context.Context, twinInstance *dtdv0.TwinInstance, originalTwinInstance *dtdv0.TwinInstance) error {
+	patchedTwinInstance := &dtdv1.TwinInstance{}
+	if err := twinInstance.ConvertTo(patchedTwinInstance); err != nil {
+		return err
+	}
+
+	storedTwinInstance := &dtdv1.TwinInstance{}
+	if err := originalTwinInstance.ConvertTo(storedTwinInstance); err != nil {
+		return err
+	}
+
+	return r.Status().Patch(ctx, patchedTwinInstance, client.MergeFrom(storedTwinInstance))
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TwinInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dtdv0.TwinInstance{}).
+		Owns(&eventingv1.Trigger{}).
+		Owns(&rabbitmqv1beta1.Binding{}).
+		Watches(&dtdv0.TwinInterface{}, handler.EnqueueRequestsFromMapFunc(r.mapTwinInterfaceToTwinInstances)).
 		Complete(r)
 }
+
+// TwinInstances waiting for its TwinInterface, or depending on its Service, are reconciled when the TwinInterface changes.
+// TwinInstances that choose a version of the TwinInterface model are reconciled when any version of the model changes,
+// so they follow the versions being added and removed.
+func (r *TwinInstanceReconciler) mapTwinInterfaceToTwinInstances(ctx context.Context, twinInterface client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+	twinInstanceList := dtdv0.TwinInstanceList{}
+
+	err := r.List(ctx, &twinInstanceList, client.InNamespace(twinInterface.GetNamespace()))
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing TwinInstances of TwinInterface %s", twinInterface.GetName()))
+		return nil
+	}
+
+	modelVersions := map[string]bool{}
+	if changedTwinInterface, ok := twinInterface.(*dtdv0.TwinInterface); ok {
+		twinInterfaceList := dtdv0.TwinInterfaceList{}
+		err = r.List(ctx, &twinInterfaceList, client.InNamespace(twinInterface.GetNamespace()))
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while listing the versions of TwinInterface %s", twinInterface.GetName()))
+			return nil
+		}
+
+		for _, version := range revision.GetModelVersions(*changedTwinInterface, twinInterfaceList.Items) {
+			modelVersions[version.Name] = true
+		}
+	}
+
+	var requests []reconcile.Request
+	for _, twinInstance := range twinInstanceList.Items {
+		if twinInstance.Spec.Interface == twinInterface.GetName() ||
+			twinInstance.Status.ResolvedInterface == twinInterface.GetName() ||
+			(twinInstance.Spec.InterfaceVersion != "" && modelVersions[twinInstance.Spec.Interface]) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: twinInstance.Namespace, Name: twinInstance.Name},
+			})
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,241 @@
+package dtd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+	keventing "knative.dev/eventing/pkg/apis/eventing/v1"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	dtdv1 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v1"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
+)
+
+const (
+	timeout  = time.Second * 10
+	interval = time.Millisecond * 250
+)
+
+// Create the namespace, Broker, Exchange and RabbitMQ Secret shared by the TwinInstances, once for all the specs
+func createTwinInstanceDependencies(ctx context.Context) (keventing.Broker, corev1.Secret) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ktwin"}}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, namespace))).To(Succeed())
+
+	broker := keventing.Broker{ObjectMeta: metav1.ObjectMeta{Name: event.EVENT_BROKER_NAME, Namespace: "ktwin"}}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, &broker))).To(Succeed())
+
+	exchange := &rabbitmqv1beta1.Exchange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ktwin-broker-exchange",
+			Namespace: "ktwin",
+			Labels:    map[string]string{"eventing.knative.dev/broker": event.EVENT_BROKER_NAME},
+		},
+		Spec: rabbitmqv1beta1.ExchangeSpec{
+			Name:                     "ktwin-broker-exchange",
+			RabbitmqClusterReference: rabbitmqv1beta1.RabbitmqClusterReference{Name: "rabbitmq"},
+		},
+	}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, exchange))).To(Succeed())
+
+	rabbitMQSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq-default-user", Namespace: "ktwin"},
+		Data:       map[string][]byte{"host": []byte("rabbitmq.ktwin"), "port": []byte("5672")},
+	}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, &rabbitMQSecret))).To(Succeed())
+
+	return broker, rabbitMQSecret
+}
+
+// Return a TwinInterface with a service, whose events are routed to the service by Triggers
+func newServiceTwinInterface(name string) *dtdv0.TwinInterface {
+	return &dtdv0.TwinInterface{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ktwin"},
+		Spec: dtdv0.TwinInterfaceSpec{
+			Id: name,
+			Service: &dtdv0.TwinInterfaceService{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: "ktwin/" + name + ":0.1"}}},
+				},
+			},
+		},
+	}
+}
+
+// Expect the resource to be controlled by the owner, so it is garbage collected and its changes reconcile the owner
+func expectControlledBy(g Gomega, resource client.Object, owner client.Object, ownerKind string) {
+	controllerRef := metav1.GetControllerOf(resource)
+	g.Expect(controllerRef).NotTo(BeNil(), resource.GetName())
+	g.Expect(controllerRef.Kind).To(Equal(ownerKind))
+	g.Expect(controllerRef.UID).To(Equal(owner.GetUID()))
+}
+
+var _ = Describe("TwinInstance controller", func() {
+	ctx := context.Background()
+
+	It("Should store the endpoint settings in the status of the storage version", func() {
+		broker, rabbitMQSecret := createTwinInstanceDependencies(ctx)
+
+		twinInterface := &dtdv0.TwinInterface{
+			ObjectMeta: metav1.ObjectMeta{Name: "city-pole", Namespace: "ktwin"},
+			Spec:       dtdv0.TwinInterfaceSpec{Id: "dtmi:city:Pole;1"},
+		}
+		Expect(k8sClient.Create(ctx, twinInterface)).To(Succeed())
+
+		twinInstance := &dtdv0.TwinInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "city-pole-001", Namespace: "ktwin"},
+			Spec:       dtdv0.TwinInstanceSpec{Interface: "city-pole"},
+		}
+		Expect(k8sClient.Create(ctx, twinInstance)).To(Succeed())
+
+		expectedEndpointSettings := event.NewTwinEvent().GetTwinInstanceEndpointSettings(twinInstance, twinInterface, broker, rabbitMQSecret)
+
+		By("Reading the endpoint settings back from the v0 spec")
+		Eventually(func(g Gomega) {
+			currentTwinInstance := &dtdv0.TwinInstance{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+			g.Expect(currentTwinInstance.Spec.EndpointSettings).To(Equal(expectedEndpointSettings))
+			g.Expect(currentTwinInstance.Status.ResolvedInterface).To(Equal("city-pole"))
+		}, timeout, interval).Should(Succeed())
+
+		By("Reading the endpoint settings back from the v1 status")
+		currentTwinInstance := &dtdv1.TwinInstance{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+		Expect(currentTwinInstance.Status.EndpointSettings).NotTo(BeNil())
+		Expect(currentTwinInstance.Status.EndpointSettings.MqttEndpoint.PublisherTopic).To(Equal(expectedEndpointSettings.MqttEndpoint.PublisherTopic))
+		Expect(currentTwinInstance.Status.ResolvedInterface).To(Equal("city-pole"))
+
+		By("Keeping the TwinInstance generation, as the spec is not updated")
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+			g.Expect(currentTwinInstance.Generation).To(Equal(int64(1)))
+		}, time.Second*2, interval).Should(Succeed())
+	})
+
+	It("Should keep the telemetry values written in the v0 spec", func() {
+		createTwinInstanceDependencies(ctx)
+
+		twinInstance := &dtdv0.TwinInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "city-bench-001", Namespace: "ktwin"},
+			Spec: dtdv0.TwinInstanceSpec{
+				Interface: "city-bench",
+				Data:      &dtdv0.TwinInstanceDataSpec{Telemetries: []dtdv0.TwinInstanceTelemetryData{{Name: "occupancy", Value: "2"}}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, twinInstance)).To(Succeed())
+
+		currentTwinInstance := &dtdv0.TwinInstance{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+		Expect(currentTwinInstance.Spec.Data).To(Equal(twinInstance.Spec.Data))
+
+		By("Updating the telemetry values")
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+			currentTwinInstance.Spec.Data.Telemetries[0].Value = "3"
+			g.Expect(k8sClient.Update(ctx, currentTwinInstance)).To(Succeed())
+		}, timeout, interval).Should(Succeed())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+		Expect(currentTwinInstance.Spec.Data.Telemetries).To(Equal([]dtdv0.TwinInstanceTelemetryData{{Name: "occupancy", Value: "3"}}))
+	})
+
+	It("Should own the routing resources of the TwinInstance", func() {
+		createTwinInstanceDependencies(ctx)
+
+		twinInterface := newServiceTwinInterface("city-sensor")
+		Expect(k8sClient.Create(ctx, twinInterface)).To(Succeed())
+
+		twinInstance := &dtdv0.TwinInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "city-sensor-001", Namespace: "ktwin"},
+			Spec:       dtdv0.TwinInstanceSpec{Interface: "city-sensor"},
+		}
+		Expect(k8sClient.Create(ctx, twinInstance)).To(Succeed())
+
+		By("Creating the Trigger and Bindings controlled by the TwinInstance")
+		resources := []client.Object{
+			&keventing.Trigger{ObjectMeta: metav1.ObjectMeta{Name: "city-sensor-001", Namespace: "ktwin"}},
+			&rabbitmqv1beta1.Binding{ObjectMeta: metav1.ObjectMeta{Name: "city-sensor-001-real-mqtt-dispatcher", Namespace: "ktwin"}},
+			&rabbitmqv1beta1.Binding{ObjectMeta: metav1.ObjectMeta{Name: "city-sensor-001-virtual-cloud-event-dispatcher", Namespace: "ktwin"}},
+		}
+		for _, resource := range resources {
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+				expectControlledBy(g, resource, twinInstance, "TwinInstance")
+			}, timeout, interval).Should(Succeed())
+		}
+
+		Eventually(func(g Gomega) {
+			currentTwinInstance := &dtdv0.TwinInstance{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+			g.Expect(currentTwinInstance.Status.Status).To(Equal(dtdv0.TwinInstancePhaseRunning))
+		}, timeout, interval).Should(Succeed())
+
+		By("Recreating a deleted Binding")
+		binding := resources[1]
+		deletedUID := binding.GetUID()
+		Expect(k8sClient.Delete(ctx, binding)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)).To(Succeed())
+			g.Expect(binding.GetUID()).NotTo(Equal(deletedUID))
+			expectControlledBy(g, binding, twinInstance, "TwinInstance")
+		}, timeout, interval).Should(Succeed())
+	})
+	It("Should route the TwinInstance events to the resolved TwinInterface version", func() {
+		createTwinInstanceDependencies(ctx)
+
+		var twinInterfaces []*dtdv0.TwinInterface
+		for version := 1; version <= 2; version++ {
+			twinInterface := newServiceTwinInterface(fmt.Sprintf("city-meter-v%d", version))
+			twinInterface.Spec.ModelId = "dtmi:city:Meter"
+			twinInterface.Spec.Version = version
+			Expect(k8sClient.Create(ctx, twinInterface)).To(Succeed())
+			twinInterfaces = append(twinInterfaces, twinInterface)
+		}
+
+		twinInstance := &dtdv0.TwinInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "city-meter-001", Namespace: "ktwin"},
+			Spec:       dtdv0.TwinInstanceSpec{Interface: "city-meter-v1", InterfaceVersion: "latest"},
+		}
+		Expect(k8sClient.Create(ctx, twinInstance)).To(Succeed())
+
+		realBinding := &rabbitmqv1beta1.Binding{ObjectMeta: metav1.ObjectMeta{Name: "city-meter-001-real-mqtt-dispatcher", Namespace: "ktwin"}}
+		virtualBinding := &rabbitmqv1beta1.Binding{ObjectMeta: metav1.ObjectMeta{Name: "city-meter-001-virtual-cloud-event-dispatcher", Namespace: "ktwin"}}
+
+		expectRoutedTo := func(twinInterfaceName string) {
+			Eventually(func(g Gomega) {
+				currentTwinInstance := &dtdv0.TwinInstance{}
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInstance), currentTwinInstance)).To(Succeed())
+				g.Expect(currentTwinInstance.Status.ResolvedInterface).To(Equal(twinInterfaceName))
+				g.Expect(currentTwinInstance.Spec.EndpointSettings).NotTo(BeNil())
+				g.Expect(currentTwinInstance.Spec.EndpointSettings.AmqpEndpoint.PublisherTopic).To(Equal("ktwin.real." + twinInterfaceName + ".city-meter-001"))
+
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(realBinding), realBinding)).To(Succeed())
+				g.Expect(realBinding.Labels).To(HaveKeyWithValue("ktwin/twin-interface", twinInterfaceName))
+				g.Expect(realBinding.Spec.RoutingKey).To(Equal("ktwin.real." + twinInterfaceName + ".city-meter-001"))
+
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(virtualBinding), virtualBinding)).To(Succeed())
+				g.Expect(virtualBinding.Labels).To(HaveKeyWithValue("ktwin/twin-interface", twinInterfaceName))
+			}, timeout, interval).Should(Succeed())
+		}
+
+		By("Labelling the Bindings with the latest version")
+		expectRoutedTo("city-meter-v2")
+
+		By("Routing to the previous version once the latest version is deleted")
+		Expect(k8sClient.Delete(ctx, twinInterfaces[1])).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInterfaces[1]), &dtdv0.TwinInterface{}))).To(BeTrue())
+		}, timeout, interval).Should(Succeed())
+		expectRoutedTo("city-meter-v1")
+	})
+})
@@ -19,25 +19,44 @@ package dtd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/apply"
 	twinevent "github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
 	eventStore "github.com/Open-Digital-Twin/ktwin-operator/pkg/event-store"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
 	twinservice "github.com/Open-Digital-Twin/ktwin-operator/pkg/service"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/status"
 	kserving "knative.dev/serving/pkg/apis/serving/v1"
 )
 
+// Finalizer that holds the TwinInterface until all its routing resources are removed
+const twinInterfaceFinalizer = "ktwin.dev/cleanup"
+
+// Interval to wait for resources that are still being deleted
+const twinInterfaceCleanupRequeueInterval = 5 * time.Second
+
 // TwinInterfaceReconciler reconciles a TwinInterface object
 type TwinInterfaceReconciler struct {
 	client.Client
@@ -45,11 +64,15 @@ type TwinInterfaceReconciler struct {
 	TwinService twinservice.TwinService
 	TwinEvent   twinevent.TwinEvent
 	EventStore  eventStore.EventStore
+	Recorder    record.EventRecorder
+	// Only report the stale bindings that would be pruned, without deleting them
+	PruneDryRun bool
 }
 
 //+kubebuilder:rbac:groups=dtd.ktwin,resources=twininterfaces,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=dtd.ktwin,resources=twininterfaces/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=dtd.ktwin,resources=twininterfaces/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *TwinInterfaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -66,9 +89,178 @@ func (r *TwinInterfaceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	if !twinInterface.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.deleteTwinInterface(ctx, req, twinInterface)
+	}
+
+	if !controllerutil.ContainsFinalizer(twinInterface, twinInterfaceFinalizer) {
+		twinInterfaceBase := twinInterface.DeepCopy()
+		controllerutil.AddFinalizer(twinInterface, twinInterfaceFinalizer)
+		err = r.Patch(ctx, twinInterface, client.MergeFrom(twinInterfaceBase))
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while adding finalizer to TwinInterface %s", req.Name))
+			return ctrl.Result{}, err
+		}
+	}
+
 	return r.createUpdateTwinInterface(ctx, req, twinInterface)
 }
 
+// Delete all the resources created for the TwinInterface and release it once nothing is left behind.
+// Bindings are created in the ktwin RabbitMQ cluster and are not always garbage collected with the TwinInterface.
+func (r *TwinInterfaceReconciler) deleteTwinInterface(ctx context.Context, req ctrl.Request, twinInterface *dtdv0.TwinInterface) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	twinInterfaceName := twinInterface.ObjectMeta.Name
+
+	if !controllerutil.ContainsFinalizer(twinInterface, twinInterfaceFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info(fmt.Sprintf("Cleaning up resources of TwinInterface %s", twinInterfaceName))
+
+	var resultErrors []error
+	listOptions := []client.ListOption{
+		client.InNamespace(twinInterface.Namespace),
+		client.MatchingLabels{
+			"ktwin/twin-interface": twinInterfaceName,
+		},
+	}
+
+	// Delete Bindings
+	bindingList := rabbitmqv1beta1.BindingList{}
+	err := r.List(ctx, &bindingList, listOptions...)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing Bindings of TwinInterface %s", twinInterfaceName))
+		return ctrl.Result{}, err
+	}
+
+	for _, binding := range bindingList.Items {
+		err = r.Delete(ctx, &binding, &client.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Error while deleting Binding %s", binding.Name))
+			r.Recorder.Eventf(twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_DELETE_FAILED, "Failed to delete Binding %s: %s", binding.Name, err)
+			resultErrors = append(resultErrors, err)
+		}
+	}
+
+	// Delete Triggers (TwinInterface and TwinInstances triggers)
+	triggerList := eventingv1.TriggerList{}
+	err = r.List(ctx, &triggerList, listOptions...)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing Triggers of TwinInterface %s", twinInterfaceName))
+		return ctrl.Result{}, err
+	}
+
+	for _, trigger := range triggerList.Items {
+		err = r.Delete(ctx, &trigger, &client.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Error while deleting Trigger %s", trigger.Name))
+			r.Recorder.Eventf(twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_DELETE_FAILED, "Failed to delete Trigger %s: %s", trigger.Name, err)
+			resultErrors = append(resultErrors, err)
+		}
+	}
+
+	// Delete Service
+	serviceList := kserving.ServiceList{}
+	err = r.List(ctx, &serviceList,
+		client.InNamespace(twinInterface.Namespace),
+		client.MatchingLabels(r.TwinService.GetServiceDeletionCriteria(req.NamespacedName)),
+	)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing Services of TwinInterface %s", twinInterfaceName))
+		return ctrl.Result{}, err
+	}
+
+	for _, service := range serviceList.Items {
+		err = r.Delete(ctx, &service, &client.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Error while deleting Service %s", service.Name))
+			r.Recorder.Eventf(twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_DELETE_FAILED, "Failed to delete Service %s: %s", service.Name, err)
+			resultErrors = append(resultErrors, err)
+		}
+	}
+
+	// Purge the queues created by the broker for the triggers of the TwinInterface. Triggers already deleted are not
+	// listed anymore, so their queues are found by the trigger names of the TwinInterface and its TwinInstances.
+	triggerNames := map[string]bool{}
+	var twinInstanceNames []string
+	for _, binding := range bindingList.Items {
+		if twinInstanceName, ok := binding.Labels["ktwin/twin-instance"]; ok {
+			twinInstanceNames = append(twinInstanceNames, twinInstanceName)
+		}
+	}
+	for _, trigger := range triggerList.Items {
+		triggerNames[trigger.Name] = true
+	}
+	for _, triggerName := range r.TwinEvent.GetTwinInterfaceTriggerNames(twinInterface, twinInstanceNames) {
+		triggerNames[triggerName] = true
+	}
+
+	queueList := rabbitmqv1beta1.QueueList{}
+	err = r.List(ctx, &queueList,
+		client.InNamespace(twinInterface.Namespace),
+		client.MatchingLabels{"eventing.knative.dev/broker": twinevent.EVENT_BROKER_NAME},
+	)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing Queues of TwinInterface %s", twinInterfaceName))
+		return ctrl.Result{}, err
+	}
+
+	remainingQueues := 0
+	for _, queue := range queueList.Items {
+		if !triggerNames[queue.Labels["eventing.knative.dev/trigger"]] {
+			continue
+		}
+
+		err = r.Delete(ctx, &queue, &client.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Error while deleting Queue %s", queue.Name))
+			r.Recorder.Eventf(twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_DELETE_FAILED, "Failed to delete Queue %s: %s", queue.Name, err)
+			resultErrors = append(resultErrors, err)
+		}
+		remainingQueues++
+	}
+
+	// Report progress, resources still listed are waiting for their own finalizers
+	cleanupStatus := &dtdv0.TwinInterfaceCleanupStatus{
+		RemainingBindings: len(bindingList.Items),
+		RemainingTriggers: len(triggerList.Items),
+		RemainingServices: len(serviceList.Items),
+		RemainingQueues:   remainingQueues,
+	}
+	cleanupStatus.Completed = cleanupStatus.RemainingBindings == 0 &&
+		cleanupStatus.RemainingTriggers == 0 &&
+		cleanupStatus.RemainingServices == 0 &&
+		cleanupStatus.RemainingQueues == 0
+
+	twinInterface.Status.Status = dtdv0.TwinInterfacePhaseTerminating
+	twinInterface.Status.Cleanup = cleanupStatus
+	err = r.patchTwinInterfaceStatus(ctx, twinInterface)
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, fmt.Sprintf("Error while updating TwinInterface %s cleanup status", twinInterfaceName))
+		return ctrl.Result{}, err
+	}
+
+	if len(resultErrors) > 0 {
+		return ctrl.Result{}, resultErrors[0]
+	}
+
+	if !cleanupStatus.Completed {
+		logger.Info(fmt.Sprintf("Waiting for resources of TwinInterface %s to be deleted", twinInterfaceName))
+		return ctrl.Result{RequeueAfter: twinInterfaceCleanupRequeueInterval}, nil
+	}
+
+	twinInterfaceBase := twinInterface.DeepCopy()
+	controllerutil.RemoveFinalizer(twinInterface, twinInterfaceFinalizer)
+	err = r.Patch(ctx, twinInterface, client.MergeFrom(twinInterfaceBase))
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, fmt.Sprintf("Error while removing finalizer from TwinInterface %s", twinInterfaceName))
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
 func (r *TwinInterfaceReconciler) createUpdateTwinInterface(ctx context.Context, req ctrl.Request, twinInterface *dtdv0.TwinInterface) (ctrl.Result, error) {
 	twinInterfaceName := twinInterface.ObjectMeta.Name
 
@@ -76,11 +268,30 @@ func (r *TwinInterfaceReconciler) createUpdateTwinInterface(ctx context.Context,
 	var twinInterfaceTrigger *eventingv1.Trigger
 	logger := log.FromContext(ctx)
 
+	// Resolve the definitions inherited from the parent TwinInterfaces, used to generate all the resources below
+	twinInterfaces, err := r.getTwinInterfaceChain(ctx, twinInterface)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while resolving the parent TwinInterfaces of TwinInterface %s", twinInterfaceName))
+		resultErrors = append(resultErrors, err)
+	}
+
+	effectiveSpec := inheritance.GetEffectiveSpec(twinInterfaces)
+
+	// Add the contents of the components, referred by their component-qualified names in instances and events
+	componentSpecs, componentErrors := r.getComponentSpecs(ctx, twinInterface.Namespace, effectiveSpec.Components)
+	for _, err := range componentErrors {
+		logger.Error(err, fmt.Sprintf("Error while resolving the components of TwinInterface %s", twinInterfaceName))
+		resultErrors = append(resultErrors, err)
+	}
+
+	effectiveSpec = inheritance.AddComponentContents(effectiveSpec, componentSpecs)
+	twinInterface.Status.EffectiveSpec = &effectiveSpec
+
 	// Create Service Instance and Trigger, if pod is specified
-	if twinInterface.Spec.Service != nil {
+	if twinInterface.GetEffectiveSpec().Service != nil {
 		// Get Broker
 		broker := eventingv1.Broker{}
-		err := r.Get(ctx, types.NamespacedName{Namespace: "ktwin", Name: "ktwin"}, &broker)
+		err = r.Get(ctx, types.NamespacedName{Namespace: "ktwin", Name: "ktwin"}, &broker)
 
 		if err != nil {
 			logger.Error(err, "Error while getting Broker")
@@ -102,49 +313,35 @@ func (r *TwinInterfaceReconciler) createUpdateTwinInterface(ctx context.Context,
 			EventStoreService: eventStoreService,
 		})
 
-		err = r.Create(ctx, newKService, &client.CreateOptions{})
-
-		if err != nil && !errors.IsAlreadyExists(err) {
-			logger.Error(err, fmt.Sprintf("Error while creating Twin Interface Service %s", twinInterfaceName))
+		logger.Info(fmt.Sprintf("Applying Twin Interface Service %s", newKService.Name))
+		err = r.applyChildResource(ctx, twinInterface, newKService, "Service")
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while applying Twin Interface Service %s", twinInterfaceName))
 			resultErrors = append(resultErrors, err)
-		} else if err != nil {
-			currentKService := &kserving.Service{}
-			err := r.Get(ctx, types.NamespacedName{Namespace: twinInterface.Namespace, Name: twinInterface.Name}, currentKService)
-			if err != nil {
-				logger.Error(err, fmt.Sprintf("Error while getting current Twin Interface service %s", twinInterface.Name))
-				return ctrl.Result{}, err
-			}
-
-			twinServiceEqual := r.TwinService.CompareTwinService(currentKService, newKService)
-			if !twinServiceEqual {
-				currentKService = r.TwinService.MergeTwinService(currentKService, newKService)
-				err = r.Update(ctx, currentKService, &client.UpdateOptions{})
-				if err != nil {
-					logger.Error(err, fmt.Sprintf("Error while updating Twin Interface Service %s", twinInterfaceName))
-					resultErrors = append(resultErrors, err)
-				}
-			} else {
-				logger.Info(fmt.Sprintf("No changes to Twin Interface Service: %s", twinInterfaceName))
-			}
 		}
 
 		// Create Trigger
 		twinInterfaceTrigger = r.TwinEvent.GetTwinInterfaceTrigger(twinInterface)
-		logger.Info(fmt.Sprintf("Creating Twin Interface Trigger %s", twinInterfaceTrigger.Name))
-		err = r.Create(ctx, twinInterfaceTrigger, &client.CreateOptions{})
-		if err != nil && !errors.IsAlreadyExists(err) {
-			logger.Error(err, fmt.Sprintf("Error while creating Twin Interface Trigger %s", twinInterfaceName))
+		logger.Info(fmt.Sprintf("Applying Twin Interface Trigger %s", twinInterfaceTrigger.Name))
+		err = r.applyChildResource(ctx, twinInterface, twinInterfaceTrigger, "Trigger")
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while applying Twin Interface Trigger %s", twinInterfaceName))
 			resultErrors = append(resultErrors, err)
 		}
 	}
 
+	var bindingNames []string
+	var eventStoreBindingNames []string
+	var eventStoreBoundErr error
+
 	// Create MQTT Binding Rules
 	bindings := r.TwinEvent.GetMQQTDispatcherBindings(twinInterface)
 	for _, binding := range bindings {
-		logger.Info(fmt.Sprintf("Creating Twin Interface MQTT Dispatcher Trigger Binding %s", binding.Name))
-		err := r.Create(ctx, &binding, &client.CreateOptions{})
-		if err != nil && !errors.IsAlreadyExists(err) {
-			logger.Error(err, fmt.Sprintf("Error while creating Twin Interface MQTT Dispatcher Trigger Binding %s", binding.Name))
+		logger.Info(fmt.Sprintf("Applying Twin Interface MQTT Dispatcher Trigger Binding %s", binding.Name))
+		bindingNames = append(bindingNames, binding.Name)
+		err := r.applyChildResource(ctx, twinInterface, &binding, "Binding")
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while applying Twin Interface MQTT Dispatcher Trigger Binding %s", binding.Name))
 			resultErrors = append(resultErrors, err)
 		}
 	}
@@ -157,35 +354,44 @@ func (r *TwinInterfaceReconciler) createUpdateTwinInterface(ctx context.Context,
 	eventStoreQueue, err := r.getEventStoreQueue(ctx, twinInterface)
 	if err != nil {
 		logger.Error(err, fmt.Sprintf("No Queue found for event store %s", twinInterfaceName))
-		return ctrl.Result{}, err
+		eventStoreBoundErr = err
+		resultErrors = append(resultErrors, err)
 	}
 
 	brokerExchange, err := r.getBrokerExchange(ctx, req, twinInterface)
 
 	if err != nil {
 		logger.Error(err, fmt.Sprintf("No Broker Exchange found for TwinInterface %s", twinInterfaceName))
+		if eventStoreBoundErr == nil {
+			eventStoreBoundErr = err
+		}
 		resultErrors = append(resultErrors, err)
 	} else {
 
-		if twinInterface.Spec.Service != nil {
+		if twinInterface.GetEffectiveSpec().Service != nil {
 			bindings := r.TwinEvent.GetVirtualCloudEventBrokerBinding(twinInterface, brokerExchange)
 			for _, binding := range bindings {
-				logger.Info(fmt.Sprintf("Creating Twin Command Virtual Cloud Event Binding %s", binding.Name))
-				err = r.Create(ctx, &binding, &client.CreateOptions{})
-				if err != nil && !errors.IsAlreadyExists(err) {
-					logger.Error(err, fmt.Sprintf("Error while creating Virtual CLoud Event Broker Bindings %s", binding.Name))
+				logger.Info(fmt.Sprintf("Applying Twin Command Virtual Cloud Event Binding %s", binding.Name))
+				bindingNames = append(bindingNames, binding.Name)
+				err = r.applyChildResource(ctx, twinInterface, &binding, "Binding")
+				if err != nil {
+					logger.Error(err, fmt.Sprintf("Error while applying Virtual CLoud Event Broker Bindings %s", binding.Name))
 					resultErrors = append(resultErrors, err)
 				}
 			}
 		}
 
-		bindings := r.EventStore.GetEventStoreBrokerBindings(twinInterface, brokerExchange, eventStoreQueue)
-		for _, binding := range bindings {
-			logger.Info(fmt.Sprintf("Creating Twin Command Event Store Binding %s", binding.Name))
-			err = r.Create(ctx, &binding, &client.CreateOptions{})
-			if err != nil && !errors.IsAlreadyExists(err) {
-				logger.Error(err, fmt.Sprintf("Error while creating EventStore TwinInterface Bindings %s", binding.Name))
-				resultErrors = append(resultErrors, err)
+		if eventStoreBoundErr == nil {
+			bindings := r.EventStore.GetEventStoreBrokerBindings(twinInterface, brokerExchange, eventStoreQueue)
+			for _, binding := range bindings {
+				logger.Info(fmt.Sprintf("Applying Twin Command Event Store Binding %s", binding.Name))
+				bindingNames = append(bindingNames, binding.Name)
+				eventStoreBindingNames = append(eventStoreBindingNames, binding.Name)
+				err = r.applyChildResource(ctx, twinInterface, &binding, "Binding")
+				if err != nil {
+					logger.Error(err, fmt.Sprintf("Error while applying EventStore TwinInterface Bindings %s", binding.Name))
+					resultErrors = append(resultErrors, err)
+				}
 			}
 		}
 
@@ -195,22 +401,19 @@ func (r *TwinInterfaceReconciler) createUpdateTwinInterface(ctx context.Context,
 			if err != nil {
 				if errors.IsNotFound(err) {
 					logger.Info(fmt.Sprintf("No Queue found for TwinInterface %s. Requeueing request...", twinInterfaceName))
-					return ctrl.Result{
-						Requeue: true,
-					}, err
-				}
-				if !errors.IsNotFound(err) {
+				} else {
 					logger.Error(err, fmt.Sprintf("Error while getting TwinInterface %s Queue", twinInterfaceName))
-					resultErrors = append(resultErrors, err)
 				}
+				resultErrors = append(resultErrors, err)
 			} else {
 				// Create Relationship Twin Interface
 				bindings := r.TwinEvent.GetRelationshipBrokerBindings(twinInterface, brokerExchange, twinInterfaceQueue)
 				for _, binding := range bindings {
-					logger.Info(fmt.Sprintf("Creating Twin Command Relationship Binding %s", binding.Name))
-					err = r.Create(ctx, &binding, &client.CreateOptions{})
-					if err != nil && !errors.IsAlreadyExists(err) {
-						logger.Error(err, fmt.Sprintf("Error while creating TwinInterface Binding %s", binding.Name))
+					logger.Info(fmt.Sprintf("Applying Twin Command Relationship Binding %s", binding.Name))
+					bindingNames = append(bindingNames, binding.Name)
+					err = r.applyChildResource(ctx, twinInterface, &binding, "Binding")
+					if err != nil {
+						logger.Error(err, fmt.Sprintf("Error while applying TwinInterface Binding %s", binding.Name))
 						resultErrors = append(resultErrors, err)
 					}
 				}
@@ -218,10 +421,11 @@ func (r *TwinInterfaceReconciler) createUpdateTwinInterface(ctx context.Context,
 				// Create Command Bindings
 				twinInterfaceCommandBindings := r.TwinEvent.GetTwinInterfaceCommandBindings(twinInterface, brokerExchange, twinInterfaceQueue)
 				for _, commandBindings := range twinInterfaceCommandBindings {
-					logger.Info(fmt.Sprintf("Creating Twin Command Bindings %s", commandBindings.Name))
-					err = r.Create(ctx, &commandBindings, &client.CreateOptions{})
-					if err != nil && !errors.IsAlreadyExists(err) {
-						logger.Error(err, fmt.Sprintf("Error while creating Twin Command Binding %s", twinInterfaceName))
+					logger.Info(fmt.Sprintf("Applying Twin Command Bindings %s", commandBindings.Name))
+					bindingNames = append(bindingNames, commandBindings.Name)
+					err = r.applyChildResource(ctx, twinInterface, &commandBindings, "Binding")
+					if err != nil {
+						logger.Error(err, fmt.Sprintf("Error while applying Twin Command Binding %s", twinInterfaceName))
 						resultErrors = append(resultErrors, err)
 					}
 				}
@@ -229,29 +433,239 @@ func (r *TwinInterfaceReconciler) createUpdateTwinInterface(ctx context.Context,
 		}
 	}
 
+	// Bindings are only pruned when the whole desired set could be computed
+	if len(resultErrors) == 0 {
+		err = r.pruneStaleBindings(ctx, twinInterface, bindingNames)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Error while pruning stale bindings of TwinInterface %s", twinInterfaceName))
+			resultErrors = append(resultErrors, err)
+		}
+	}
+
+	// Set conditions from the current state of the created resources
+	r.setTwinInterfaceConditions(ctx, twinInterface, twinInterfaceTrigger, bindingNames, eventStoreBindingNames, eventStoreBoundErr)
+
 	if len(resultErrors) > 0 {
 		twinInterface.Status.Status = dtdv0.TwinInterfacePhaseFailed
-		return ctrl.Result{}, resultErrors[0]
+		r.Recorder.Eventf(twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_RECONCILE_FAILED, "Failed to reconcile TwinInterface %s: %s", twinInterfaceName, resultErrors[0])
 	} else {
 		twinInterface.Status.Status = dtdv0.TwinInterfacePhaseRunning
 	}
 
-	twinInterface.Labels = map[string]string{
-		"ktwin/twin-interface": twinInterfaceName,
+	// Update labels and then Status for Running or Failed
+	err = r.patchTwinInterfaceLabels(ctx, twinInterface)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while updating TwinInterface %s labels", twinInterfaceName))
+		return ctrl.Result{}, err
 	}
 
-	// Update Status for Running or Failed
-	_, err = r.updateTwinInterface(ctx, req, twinInterface)
-
+	err = r.patchTwinInterfaceStatus(ctx, twinInterface)
 	if err != nil {
-		return ctrl.Result{}, nil
+		logger.Error(err, fmt.Sprintf("Error while updating TwinInterface %s status", twinInterfaceName))
+		return ctrl.Result{}, err
+	}
+
+	if len(resultErrors) > 0 {
+		return ctrl.Result{}, resultErrors[0]
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *TwinInterfaceReconciler) getEventStoreQueue(ctx context.Context, twinInterface *dtdv0.TwinInterface) (rabbitmqv1beta1.Queue, error) {
+// Return the TwinInterface followed by all the TwinInterfaces it extends, directly or not, in topological order.
+// When a parent can not be resolved or the inheritance has a cycle, the chain resolved so far is returned with the error.
+func (r *TwinInterfaceReconciler) getTwinInterfaceChain(ctx context.Context, twinInterface *dtdv0.TwinInterface) ([]dtdv0.TwinInterface, error) {
+	return inheritance.GetExtendsChain(twinInterface.Name, func(name string) (*dtdv0.TwinInterface, error) {
+		if name == twinInterface.Name {
+			return twinInterface, nil
+		}
+
+		parentTwinInterface := &dtdv0.TwinInterface{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: twinInterface.Namespace, Name: name}, parentTwinInterface)
+		if err != nil {
+			return nil, err
+		}
+
+		return parentTwinInterface, nil
+	})
+}
+
+// Return the effective spec of the TwinInterface of each component, keyed by the component name.
+// Components whose TwinInterface can not be resolved are not returned.
+func (r *TwinInterfaceReconciler) getComponentSpecs(ctx context.Context, namespace string, components []dtdv0.TwinComponent) (map[string]dtdv0.TwinInterfaceSpec, []error) {
+	componentSpecs := map[string]dtdv0.TwinInterfaceSpec{}
+	var componentErrors []error
+
+	for _, component := range components {
+		componentTwinInterface := dtdv0.TwinInterface{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: component.Interface}, &componentTwinInterface)
+		if err != nil {
+			componentErrors = append(componentErrors, err)
+			continue
+		}
+
+		twinInterfaces, err := r.getTwinInterfaceChain(ctx, &componentTwinInterface)
+		if err != nil {
+			componentErrors = append(componentErrors, err)
+			continue
+		}
+
+		componentSpecs[component.Name] = inheritance.GetEffectiveSpec(twinInterfaces)
+	}
+
+	return componentSpecs, componentErrors
+}
+
+// Delete the TwinInterface bindings that are no longer generated, such as the bindings of removed
+// relationships and commands or of disabled event store persistence flags.
+// TwinInstance bindings share the TwinInterface label, but are managed by the TwinInstance controller.
+func (r *TwinInterfaceReconciler) pruneStaleBindings(ctx context.Context, twinInterface *dtdv0.TwinInterface, desiredBindingNames []string) error {
+	logger := log.FromContext(ctx)
+
+	bindingList := rabbitmqv1beta1.BindingList{}
+	err := r.List(ctx, &bindingList,
+		client.InNamespace(twinInterface.Namespace),
+		client.MatchingLabels{
+			"ktwin/twin-interface": twinInterface.Name,
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	desiredBindings := make(map[string]bool)
+	for _, bindingName := range desiredBindingNames {
+		desiredBindings[bindingName] = true
+	}
+
+	for _, binding := range bindingList.Items {
+		if _, ok := binding.Labels["ktwin/twin-instance"]; ok {
+			continue
+		}
+
+		if desiredBindings[binding.Name] || !binding.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if r.PruneDryRun {
+			logger.Info(fmt.Sprintf("Dry run: stale Binding %s of TwinInterface %s would be pruned", binding.Name, twinInterface.Name))
+			r.Recorder.Eventf(twinInterface, corev1.EventTypeNormal, status.EVENT_REASON_PRUNE_DRY_RUN, "Stale Binding %s would be pruned", binding.Name)
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Pruning stale Binding %s of TwinInterface %s", binding.Name, twinInterface.Name))
+		err = r.Delete(ctx, &binding, &client.DeleteOptions{})
+
+		if err != nil && !errors.IsNotFound(err) {
+			r.Recorder.Eventf(twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_DELETE_FAILED, "Failed to delete Binding %s: %s", binding.Name, err)
+			return err
+		}
+
+		r.Recorder.Eventf(twinInterface, corev1.EventTypeNormal, status.EVENT_REASON_PRUNED, "Pruned stale Binding %s", binding.Name)
+	}
+
+	return nil
+}
+
+// Apply a TwinInterface child resource, recording a Kubernetes Event when it is created or updated
+func (r *TwinInterfaceReconciler) applyChildResource(ctx context.Context, twinInterface *dtdv0.TwinInterface, resource client.Object, kind string) error {
+	result, err := apply.Apply(ctx, r.Client, resource)
+
+	if err != nil {
+		r.Recorder.Eventf(twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_APPLY_FAILED, "Failed to apply %s %s: %s", kind, resource.GetName(), err)
+		return err
+	}
+
+	switch result {
+	case controllerutil.OperationResultCreated:
+		r.Recorder.Eventf(twinInterface, corev1.EventTypeNormal, status.EVENT_REASON_CREATED, "Created %s %s", kind, resource.GetName())
+	case controllerutil.OperationResultUpdated:
+		r.Recorder.Eventf(twinInterface, corev1.EventTypeNormal, status.EVENT_REASON_UPDATED, "Updated %s %s", kind, resource.GetName())
+	}
+
+	return nil
+}
+
+func (r *TwinInterfaceReconciler) setTwinInterfaceConditions(
+	ctx context.Context,
+	twinInterface *dtdv0.TwinInterface,
+	twinInterfaceTrigger *eventingv1.Trigger,
+	bindingNames []string,
+	eventStoreBindingNames []string,
+	eventStoreBoundErr error,
+) {
 	logger := log.FromContext(ctx)
+	generation := twinInterface.Generation
+	var conditions []metav1.Condition
+
+	// Service
+	if twinInterface.GetEffectiveSpec().Service != nil {
+		kService := &kserving.Service{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: twinInterface.Namespace, Name: twinInterface.Name}, kService)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				logger.Error(err, fmt.Sprintf("Error while getting Twin Interface Service %s", twinInterface.Name))
+			}
+			conditions = append(conditions, status.NewServiceReadyCondition(nil, generation))
+			twinInterface.Status.ServiceURL = ""
+		} else {
+			conditions = append(conditions, status.NewServiceReadyCondition(kService, generation))
+			if kService.Status.URL != nil {
+				twinInterface.Status.ServiceURL = kService.Status.URL.String()
+			}
+		}
+	} else {
+		conditions = append(conditions, status.NewNotRequiredCondition(status.CONDITION_SERVICE_READY, "TwinInterface has no service", generation))
+		twinInterface.Status.ServiceURL = ""
+	}
+
+	// Trigger
+	if twinInterfaceTrigger != nil {
+		trigger := &eventingv1.Trigger{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: twinInterfaceTrigger.Namespace, Name: twinInterfaceTrigger.Name}, trigger)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				logger.Error(err, fmt.Sprintf("Error while getting Twin Interface Trigger %s", twinInterfaceTrigger.Name))
+			}
+			conditions = append(conditions, status.NewTriggerReadyCondition(nil, generation))
+		} else {
+			conditions = append(conditions, status.NewTriggerReadyCondition(trigger, generation))
+		}
+	} else {
+		conditions = append(conditions, status.NewNotRequiredCondition(status.CONDITION_TRIGGER_READY, "TwinInterface has no service", generation))
+	}
+
+	// Bindings
+	bindingList := rabbitmqv1beta1.BindingList{}
+	err := r.List(ctx, &bindingList,
+		client.InNamespace(twinInterface.Namespace),
+		client.MatchingLabels{
+			"ktwin/twin-interface": twinInterface.Name,
+		},
+	)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing Bindings of TwinInterface %s", twinInterface.Name))
+		conditions = append(conditions, status.NewErrorCondition(status.CONDITION_BINDINGS_READY, err, generation))
+	} else {
+		conditions = append(conditions, status.NewBindingsReadyCondition(status.CONDITION_BINDINGS_READY, bindingNames, bindingList.Items, generation))
+	}
+
+	// Event Store
+	if eventStoreBoundErr != nil {
+		conditions = append(conditions, status.NewErrorCondition(status.CONDITION_EVENT_STORE_BOUND, eventStoreBoundErr, generation))
+	} else if err == nil {
+		conditions = append(conditions, status.NewBindingsReadyCondition(status.CONDITION_EVENT_STORE_BOUND, eventStoreBindingNames, bindingList.Items, generation))
+	} else {
+		conditions = append(conditions, status.NewErrorCondition(status.CONDITION_EVENT_STORE_BOUND, err, generation))
+	}
+
+	status.SetConditions(&twinInterface.Status.Conditions, generation, conditions...)
+	twinInterface.Status.Bindings = bindingNames
+	twinInterface.Status.ObservedGeneration = generation
+}
+
+func (r *TwinInterfaceReconciler) getEventStoreQueue(ctx context.Context, twinInterface *dtdv0.TwinInterface) (rabbitmqv1beta1.Queue, error) {
 	eventStoreQueuesList := rabbitmqv1beta1.QueueList{}
 	queueListOptions := []client.ListOption{
 		client.InNamespace(twinInterface.Namespace),
@@ -262,10 +676,13 @@ func (r *TwinInterfaceReconciler) getEventStoreQueue(ctx context.Context, twinIn
 
 	err := r.List(ctx, &eventStoreQueuesList, queueListOptions...)
 
-	if len(eventStoreQueuesList.Items) == 0 {
-		logger.Error(err, fmt.Sprintf("No Queue found for event store %s", twinInterface.Name))
+	if err != nil {
 		return rabbitmqv1beta1.Queue{}, err
 	}
+
+	if len(eventStoreQueuesList.Items) == 0 {
+		return rabbitmqv1beta1.Queue{}, errors.NewNotFound(rabbitmqv1beta1.Resource("rabbitmqv1beta1.Queue"), "event-store-trigger")
+	}
 	return eventStoreQueuesList.Items[0], nil
 }
 
@@ -286,6 +703,10 @@ func (r *TwinInterfaceReconciler) getBrokerExchange(ctx context.Context, req ctr
 		return rabbitmqv1beta1.Exchange{}, err
 	}
 
+	if len(exchangeList.Items) == 0 {
+		return rabbitmqv1beta1.Exchange{}, errors.NewNotFound(rabbitmqv1beta1.Resource("rabbitmqv1beta1.Exchange"), "ktwin")
+	}
+
 	return exchangeList.Items[0], nil
 }
 
@@ -312,21 +733,155 @@ func (r *TwinInterfaceReconciler) getTwinInterfaceQueue(ctx context.Context, req
 	return queueList.Items[0], nil
 }
 
-func (r *TwinInterfaceReconciler) updateTwinInterface(ctx context.Context, req ctrl.Request, twinInterface *dtdv0.TwinInterface) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
-	err := r.Update(ctx, twinInterface, &client.UpdateOptions{})
+// Patch the TwinInterface labels without touching its spec or status
+func (r *TwinInterfaceReconciler) patchTwinInterfaceLabels(ctx context.Context, twinInterface *dtdv0.TwinInterface) error {
+	if twinInterface.Labels["ktwin/twin-interface"] == twinInterface.Name {
+		return nil
+	}
 
-	if err != nil {
-		logger.Error(err, fmt.Sprintf("Error while updating TwinInterface %s", twinInterface.ObjectMeta.Name))
-		return ctrl.Result{}, err
+	twinInterfaceStatus := twinInterface.Status.DeepCopy()
+	twinInterfaceBase := twinInterface.DeepCopy()
+	if twinInterface.Labels == nil {
+		twinInterface.Labels = map[string]string{}
 	}
+	twinInterface.Labels["ktwin/twin-interface"] = twinInterface.Name
 
-	return ctrl.Result{}, nil
+	err := r.Patch(ctx, twinInterface, client.MergeFrom(twinInterfaceBase))
+
+	// The patched object returned by the API server carries the stored status
+	twinInterface.Status = *twinInterfaceStatus
+	return err
+}
+
+// Patch the TwinInterface status subresource, retrying on conflicts with the latest version of the object
+func (r *TwinInterfaceReconciler) patchTwinInterfaceStatus(ctx context.Context, twinInterface *dtdv0.TwinInterface) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		currentTwinInterface := &dtdv0.TwinInterface{}
+		err := r.Get(ctx, client.ObjectKeyFromObject(twinInterface), currentTwinInterface)
+		if err != nil {
+			return err
+		}
+
+		twinInterfaceBase := currentTwinInterface.DeepCopy()
+		currentTwinInterface.Status = twinInterface.Status
+		return r.Status().Patch(ctx, currentTwinInterface, client.MergeFromWithOptions(twinInterfaceBase, client.MergeFromWithOptimisticLock{}))
+	})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TwinInterfaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dtdv0.TwinInterface{}).
+		Owns(&kserving.Service{}).
+		Owns(&eventingv1.Trigger{}).
+		Owns(&rabbitmqv1beta1.Binding{}).
+		Watches(&rabbitmqv1beta1.Queue{}, handler.EnqueueRequestsFromMapFunc(r.mapQueueToTwinInterfaces)).
+		Watches(&rabbitmqv1beta1.Exchange{}, handler.EnqueueRequestsFromMapFunc(r.mapExchangeToTwinInterfaces)).
+		Watches(&dtdv0.TwinInterface{}, handler.EnqueueRequestsFromMapFunc(r.mapTwinInterfaceToDependentTwinInterfaces), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
+
+// Changes in the spec of a TwinInterface are inherited by all the TwinInterfaces extending it or using it as
+// a component, directly or not
+func (r *TwinInterfaceReconciler) mapTwinInterfaceToDependentTwinInterfaces(ctx context.Context, parentTwinInterface client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+	twinInterfaceList := dtdv0.TwinInterfaceList{}
+
+	err := r.List(ctx, &twinInterfaceList, client.InNamespace(parentTwinInterface.GetNamespace()))
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing TwinInterfaces of namespace %s", parentTwinInterface.GetNamespace()))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	parentNames := []string{parentTwinInterface.GetName()}
+	visited := map[string]bool{parentTwinInterface.GetName(): true}
+
+	for len(parentNames) > 0 {
+		parentName := parentNames[0]
+		parentNames = parentNames[1:]
+
+		for _, twinInterface := range twinInterfaceList.Items {
+			if !dependsOnTwinInterface(twinInterface, parentName) || visited[twinInterface.Name] {
+				continue
+			}
+
+			visited[twinInterface.Name] = true
+			parentNames = append(parentNames, twinInterface.Name)
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: twinInterface.Namespace, Name: twinInterface.Name},
+			})
+		}
+	}
+
+	return requests
+}
+
+func dependsOnTwinInterface(twinInterface dtdv0.TwinInterface, twinInterfaceName string) bool {
+	for _, parentName := range twinInterface.Spec.GetExtends() {
+		if parentName == twinInterfaceName {
+			return true
+		}
+	}
+
+	for _, component := range twinInterface.Spec.Components {
+		if component.Interface == twinInterfaceName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Queues are created by the broker for each Trigger and are not owned by the TwinInterface.
+// The TwinInterface queue is labelled with the TwinInterface trigger name, while the event store
+// queue is shared by all TwinInterfaces of the namespace.
+func (r *TwinInterfaceReconciler) mapQueueToTwinInterfaces(ctx context.Context, queue client.Object) []reconcile.Request {
+	labels := queue.GetLabels()
+	triggerName := labels["eventing.knative.dev/trigger"]
+
+	if triggerName == "" {
+		return nil
+	}
+
+	if triggerName == "event-store-trigger" {
+		return r.getNamespaceTwinInterfaceRequests(ctx, queue.GetNamespace())
+	}
+
+	if labels["eventing.knative.dev/broker"] != twinevent.EVENT_BROKER_NAME {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: queue.GetNamespace(), Name: triggerName}},
+	}
+}
+
+// The broker exchange is the source of most TwinInterface bindings
+func (r *TwinInterfaceReconciler) mapExchangeToTwinInterfaces(ctx context.Context, exchange client.Object) []reconcile.Request {
+	if exchange.GetLabels()["eventing.knative.dev/broker"] != twinevent.EVENT_BROKER_NAME {
+		return nil
+	}
+
+	return r.getNamespaceTwinInterfaceRequests(ctx, exchange.GetNamespace())
+}
+
+func (r *TwinInterfaceReconciler) getNamespaceTwinInterfaceRequests(ctx context.Context, namespace string) []reconcile.Request {
+	logger := log.FromContext(ctx)
+	twinInterfaceList := dtdv0.TwinInterfaceList{}
+
+	err := r.List(ctx, &twinInterfaceList, client.InNamespace(namespace))
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Error while listing TwinInterfaces of namespace %s", namespace))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, twinInterface := range twinInterfaceList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: twinInterface.Namespace, Name: twinInterface.Name},
+		})
+	}
+
+	return requests
+}
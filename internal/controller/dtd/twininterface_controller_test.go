@@ -0,0 +1,376 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/service"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/status"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	keventing "knative.dev/eventing/pkg/apis/eventing/v1"
+	kserving "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	dtdv0.AddToScheme(scheme)
+	rabbitmqv1beta1.AddToScheme(scheme)
+	keventing.AddToScheme(scheme)
+	kserving.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&dtdv0.TwinInterface{}).Build()
+}
+
+func newBinding(name string, labels map[string]string) *rabbitmqv1beta1.Binding {
+	return &rabbitmqv1beta1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ktwin", Labels: labels},
+	}
+}
+
+func getBindingNames(t *testing.T, c client.Client) []string {
+	bindingList := rabbitmqv1beta1.BindingList{}
+	assert.Nil(t, c.List(context.Background(), &bindingList))
+
+	var bindingNames []string
+	for _, binding := range bindingList.Items {
+		bindingNames = append(bindingNames, binding.Name)
+	}
+
+	sort.Strings(bindingNames)
+	return bindingNames
+}
+
+func TestTwinInterfaceReconciler_pruneStaleBindings(t *testing.T) {
+	twinInterface := &dtdv0.TwinInterface{
+		ObjectMeta: metav1.ObjectMeta{Name: "city", Namespace: "ktwin"},
+	}
+
+	existingBindings := []client.Object{
+		newBinding("city-command", map[string]string{"ktwin/twin-interface": "city"}),
+		newBinding("city-stale-relationship", map[string]string{"ktwin/twin-interface": "city"}),
+		newBinding("city-001-mqtt", map[string]string{"ktwin/twin-interface": "city", "ktwin/twin-instance": "city-001"}),
+		newBinding("pole-command", map[string]string{"ktwin/twin-interface": "pole"}),
+	}
+
+	tests := []struct {
+		name             string
+		pruneDryRun      bool
+		expectedBindings []string
+		expectedEvents   []string
+	}{
+		{
+			name:             "Stale TwinInterface bindings are deleted",
+			expectedBindings: []string{"city-001-mqtt", "city-command", "pole-command"},
+			expectedEvents:   []string{"Normal Pruned Pruned stale Binding city-stale-relationship"},
+		},
+		{
+			name:             "Stale TwinInterface bindings are only reported in dry run",
+			pruneDryRun:      true,
+			expectedBindings: []string{"city-001-mqtt", "city-command", "city-stale-relationship", "pole-command"},
+			expectedEvents:   []string{"Normal PruneDryRun Stale Binding city-stale-relationship would be pruned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := newFakeClient(existingBindings...)
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &TwinInterfaceReconciler{
+				Client:      fakeClient,
+				Recorder:    recorder,
+				PruneDryRun: tt.pruneDryRun,
+			}
+
+			err := reconciler.pruneStaleBindings(context.Background(), twinInterface, []string{"city-command"})
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedBindings, getBindingNames(t, fakeClient))
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+		})
+	}
+}
+
+func newQueue(name string, triggerName string) *rabbitmqv1beta1.Queue {
+	return &rabbitmqv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ktwin",
+			Labels: map[string]string{
+				"eventing.knative.dev/broker":  event.EVENT_BROKER_NAME,
+				"eventing.knative.dev/trigger": triggerName,
+			},
+		},
+	}
+}
+
+func TestTwinInterfaceReconciler_deleteTwinInterfaceQueues(t *testing.T) {
+	twinInterface := &dtdv0.TwinInterface{
+		ObjectMeta: metav1.ObjectMeta{Name: "city", Namespace: "ktwin", Finalizers: []string{twinInterfaceFinalizer}},
+	}
+
+	// The Triggers are already deleted, their Queues are still left behind
+	fakeClient := newFakeClient(
+		twinInterface,
+		newBinding("city-001-mqtt", map[string]string{"ktwin/twin-interface": "city", "ktwin/twin-instance": "city-001"}),
+		newQueue("city-queue", "city"),
+		newQueue("city-001-queue", "city-001"),
+		newQueue("pole-queue", "pole"),
+	)
+	reconciler := &TwinInterfaceReconciler{
+		Client:      fakeClient,
+		Recorder:    record.NewFakeRecorder(10),
+		TwinService: service.NewTwinService(),
+		TwinEvent:   event.NewTwinEvent(),
+	}
+
+	result, err := reconciler.deleteTwinInterface(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(twinInterface)}, twinInterface)
+	assert.Nil(t, err)
+	assert.NotZero(t, result.RequeueAfter)
+
+	queueList := rabbitmqv1beta1.QueueList{}
+	assert.Nil(t, fakeClient.List(context.Background(), &queueList))
+
+	var queueNames []string
+	for _, queue := range queueList.Items {
+		queueNames = append(queueNames, queue.Name)
+	}
+	assert.Equal(t, []string{"pole-queue"}, queueNames)
+
+	currentTwinInterface := &dtdv0.TwinInterface{}
+	assert.Nil(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(twinInterface), currentTwinInterface))
+	assert.Equal(t, 2, currentTwinInterface.Status.Cleanup.RemainingQueues)
+}
+
+// Create the event store Service and Queue used by all the TwinInterfaces, besides the TwinInstance dependencies
+func createTwinInterfaceDependencies(ctx context.Context) {
+	createTwinInstanceDependencies(ctx)
+
+	eventStoreService := &kserving.Service{ObjectMeta: metav1.ObjectMeta{Name: "event-store", Namespace: "ktwin"}}
+	eventStoreService.Spec.Template.Spec.Containers = []corev1.Container{{Name: "event-store", Image: "ktwin/event-store:0.1"}}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, eventStoreService))).To(Succeed())
+
+	createBrokerQueue(ctx, "event-store-trigger")
+}
+
+// Create the Queue the broker creates for a Trigger, which is not owned by the TwinInterface of the Trigger
+func createBrokerQueue(ctx context.Context, triggerName string) *rabbitmqv1beta1.Queue {
+	queue := &rabbitmqv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      triggerName + "-queue",
+			Namespace: "ktwin",
+			Labels: map[string]string{
+				"eventing.knative.dev/broker":  event.EVENT_BROKER_NAME,
+				"eventing.knative.dev/trigger": triggerName,
+			},
+		},
+		Spec: rabbitmqv1beta1.QueueSpec{
+			Name:                     triggerName + "-queue",
+			RabbitmqClusterReference: rabbitmqv1beta1.RabbitmqClusterReference{Name: "rabbitmq"},
+		},
+	}
+	Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, queue))).To(Succeed())
+
+	return queue
+}
+
+// Expect an Event recorded for the resource, as listed by kubectl describe
+func expectEvent(g Gomega, ctx context.Context, resource client.Object, eventType string, reason string, message string) {
+	eventList := corev1.EventList{}
+	g.Expect(k8sClient.List(ctx, &eventList, client.InNamespace(resource.GetNamespace()))).To(Succeed())
+
+	var events []string
+	for _, recordedEvent := range eventList.Items {
+		if recordedEvent.InvolvedObject.UID == resource.GetUID() {
+			events = append(events, recordedEvent.Type+" "+recordedEvent.Reason+" "+recordedEvent.Message)
+		}
+	}
+
+	g.Expect(events).To(ContainElement(HavePrefix(eventType + " " + reason + " " + message)))
+}
+
+var _ = Describe("TwinInterface controller", func() {
+	ctx := context.Background()
+
+	It("Should report the conditions of the TwinInterface resources in its status", func() {
+		createTwinInterfaceDependencies(ctx)
+		createBrokerQueue(ctx, "city-bus")
+
+		twinInterface := newServiceTwinInterface("city-bus")
+		Expect(k8sClient.Create(ctx, twinInterface)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			currentTwinInterface := &dtdv0.TwinInterface{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInterface), currentTwinInterface)).To(Succeed())
+			g.Expect(currentTwinInterface.Status.Status).To(Equal(dtdv0.TwinInterfacePhaseRunning))
+			g.Expect(currentTwinInterface.Status.ObservedGeneration).To(Equal(currentTwinInterface.Generation))
+			g.Expect(currentTwinInterface.Status.Bindings).To(ContainElement("city-bus-real-mqtt-dispatcher"))
+
+			// The Service and Trigger are not ready without the Knative controllers
+			serviceReady := meta.FindStatusCondition(currentTwinInterface.Status.Conditions, status.CONDITION_SERVICE_READY)
+			g.Expect(serviceReady).NotTo(BeNil())
+			g.Expect(serviceReady.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(serviceReady.ObservedGeneration).To(Equal(currentTwinInterface.Generation))
+
+			for _, conditionType := range []string{status.CONDITION_TRIGGER_READY, status.CONDITION_BINDINGS_READY, status.CONDITION_EVENT_STORE_BOUND} {
+				g.Expect(meta.FindStatusCondition(currentTwinInterface.Status.Conditions, conditionType)).NotTo(BeNil(), conditionType)
+			}
+		}, timeout, interval).Should(Succeed())
+
+		By("Recording an Event for each created resource")
+		Eventually(func(g Gomega) {
+			expectEvent(g, ctx, twinInterface, corev1.EventTypeNormal, status.EVENT_REASON_CREATED, "Created Service city-bus")
+			expectEvent(g, ctx, twinInterface, corev1.EventTypeNormal, status.EVENT_REASON_CREATED, "Created Trigger city-bus")
+			expectEvent(g, ctx, twinInterface, corev1.EventTypeNormal, status.EVENT_REASON_CREATED, "Created Binding city-bus-real-mqtt-dispatcher")
+		}, timeout, interval).Should(Succeed())
+	})
+
+	It("Should report the reconcile failures in the status and Events", func() {
+		// The namespace has no broker Exchange nor event store Queue to bind the TwinInterface to
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "city"}}
+		Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, namespace))).To(Succeed())
+
+		twinInterface := &dtdv0.TwinInterface{
+			ObjectMeta: metav1.ObjectMeta{Name: "city-lamp", Namespace: "city"},
+			Spec:       dtdv0.TwinInterfaceSpec{Id: "city-lamp"},
+		}
+		Expect(k8sClient.Create(ctx, twinInterface)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			currentTwinInterface := &dtdv0.TwinInterface{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInterface), currentTwinInterface)).To(Succeed())
+			g.Expect(currentTwinInterface.Status.Status).To(Equal(dtdv0.TwinInterfacePhaseFailed))
+			expectEvent(g, ctx, twinInterface, corev1.EventTypeWarning, status.EVENT_REASON_RECONCILE_FAILED, "Failed to reconcile TwinInterface city-lamp")
+		}, timeout, interval).Should(Succeed())
+	})
+
+	It("Should recreate the owned resources deleted from the cluster", func() {
+		createTwinInterfaceDependencies(ctx)
+		createBrokerQueue(ctx, "city-light")
+
+		twinInterface := newServiceTwinInterface("city-light")
+		Expect(k8sClient.Create(ctx, twinInterface)).To(Succeed())
+
+		resources := []client.Object{
+			&kserving.Service{ObjectMeta: metav1.ObjectMeta{Name: "city-light", Namespace: "ktwin"}},
+			&keventing.Trigger{ObjectMeta: metav1.ObjectMeta{Name: "city-light", Namespace: "ktwin"}},
+			&rabbitmqv1beta1.Binding{ObjectMeta: metav1.ObjectMeta{Name: "city-light-real-mqtt-dispatcher", Namespace: "ktwin"}},
+		}
+
+		for _, resource := range resources {
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+				expectControlledBy(g, resource, twinInterface, "TwinInterface")
+			}, timeout, interval).Should(Succeed())
+		}
+
+		for _, resource := range resources {
+			By(fmt.Sprintf("Recreating the deleted %T %s", resource, resource.GetName()))
+			deletedUID := resource.GetUID()
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+				g.Expect(resource.GetUID()).NotTo(Equal(deletedUID))
+				expectControlledBy(g, resource, twinInterface, "TwinInterface")
+			}, timeout, interval).Should(Succeed())
+		}
+	})
+
+	It("Should delete the routing resources before releasing a deleted TwinInterface", func() {
+		createTwinInterfaceDependencies(ctx)
+		queue := createBrokerQueue(ctx, "city-tram")
+		// The Trigger of the TwinInstance is already deleted, its Queue is found by the TwinInstance of its Bindings
+		instanceQueue := createBrokerQueue(ctx, "city-tram-001")
+
+		twinInterface := newServiceTwinInterface("city-tram")
+		Expect(k8sClient.Create(ctx, twinInterface)).To(Succeed())
+
+		// Bindings of the TwinInstances are labelled with their TwinInterface but are not owned by it
+		instanceBinding := &rabbitmqv1beta1.Binding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "city-tram-001-real-mqtt-dispatcher",
+				Namespace: "ktwin",
+				Labels:    map[string]string{"ktwin/twin-interface": "city-tram", "ktwin/twin-instance": "city-tram-001"},
+			},
+			Spec: rabbitmqv1beta1.BindingSpec{RabbitmqClusterReference: rabbitmqv1beta1.RabbitmqClusterReference{Name: "rabbitmq"}},
+		}
+		Expect(k8sClient.Create(ctx, instanceBinding)).To(Succeed())
+
+		resources := []client.Object{
+			&kserving.Service{ObjectMeta: metav1.ObjectMeta{Name: "city-tram", Namespace: "ktwin"}},
+			&keventing.Trigger{ObjectMeta: metav1.ObjectMeta{Name: "city-tram", Namespace: "ktwin"}},
+			&rabbitmqv1beta1.Binding{ObjectMeta: metav1.ObjectMeta{Name: "city-tram-real-mqtt-dispatcher", Namespace: "ktwin"}},
+		}
+
+		Eventually(func(g Gomega) {
+			currentTwinInterface := &dtdv0.TwinInterface{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInterface), currentTwinInterface)).To(Succeed())
+			g.Expect(currentTwinInterface.Finalizers).To(ContainElement(twinInterfaceFinalizer))
+
+			for _, resource := range resources {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+			}
+		}, timeout, interval).Should(Succeed())
+
+		By("Deleting the TwinInterface")
+		Expect(k8sClient.Delete(ctx, twinInterface)).To(Succeed())
+
+		// The test environment has no garbage collector, the resources are only deleted by the finalizer
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(twinInterface), &dtdv0.TwinInterface{})
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), "TwinInterface not deleted: %v", err)
+		}, timeout, interval).Should(Succeed())
+
+		for _, resource := range append(resources, instanceBinding, queue, instanceQueue) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "%s not deleted: %v", resource.GetName(), err)
+		}
+
+		bindingList := rabbitmqv1beta1.BindingList{}
+		Expect(k8sClient.List(ctx, &bindingList, client.InNamespace("ktwin"), client.MatchingLabels{"ktwin/twin-interface": "city-tram"})).To(Succeed())
+		Expect(bindingList.Items).To(BeEmpty())
+
+		eventStoreQueue := &rabbitmqv1beta1.Queue{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "event-store-trigger-queue", Namespace: "ktwin"}, eventStoreQueue)).To(Succeed())
+	})
+})
@@ -0,0 +1,81 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtd
+
+import (
+	"context"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/graph"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// List the TwinInterfaces in the namespace
+func listTwinInterfaces(ctx context.Context, c client.Client, namespace string) ([]dtdv0.TwinInterface, error) {
+	twinInterfaceList := &dtdv0.TwinInterfaceList{}
+	if err := c.List(ctx, twinInterfaceList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	return twinInterfaceList.Items, nil
+}
+
+// Build the graph of the TwinInterfaces, replacing the stored version of the validated TwinInterface if informed.
+// Relationships, extended TwinInterfaces and components reference TwinInterfaces by name, so the vertexes are keyed by name.
+func getTwinInterfaceGraph(twinInterfaces []dtdv0.TwinInterface, twinInterface *dtdv0.TwinInterface) graph.TwinInterfaceGraph {
+	twinInterfaceGraph := graph.NewTwinInterfaceGraph()
+	if twinInterface != nil {
+		addTwinInterfaceToGraph(twinInterfaceGraph, *twinInterface)
+	}
+
+	for _, existingTwinInterface := range twinInterfaces {
+		if twinInterface == nil || existingTwinInterface.Name != twinInterface.Name {
+			addTwinInterfaceToGraph(twinInterfaceGraph, existingTwinInterface)
+		}
+	}
+
+	return twinInterfaceGraph
+}
+
+func addTwinInterfaceToGraph(twinInterfaceGraph graph.TwinInterfaceGraph, twinInterface dtdv0.TwinInterface) {
+	vertex := *twinInterface.DeepCopy()
+	vertex.Spec.Id = twinInterface.Name
+	twinInterfaceGraph.AddVertex(vertex)
+
+	for _, relationship := range twinInterface.Spec.Relationships {
+		twinInterfaceGraph.AddEdge(vertex, dtdv0.TwinInterface{
+			Spec: dtdv0.TwinInterfaceSpec{
+				Id: relationship.Interface,
+			},
+		})
+	}
+}
+
+// Resolve the spec of a TwinInterface with the definitions inherited from its parents and the contents of its components
+func getEffectiveSpec(twinInterfaceGraph graph.TwinInterfaceGraph, twinInterfaceName string) dtdv0.TwinInterfaceSpec {
+	effectiveSpec := inheritance.GetEffectiveSpec(twinInterfaceGraph.GetExtendsChain(twinInterfaceName))
+
+	componentSpecs := map[string]dtdv0.TwinInterfaceSpec{}
+	for _, component := range effectiveSpec.Components {
+		if !twinInterfaceGraph.IsTemporaryVertex(component.Interface) {
+			componentSpecs[component.Name] = inheritance.GetEffectiveSpec(twinInterfaceGraph.GetExtendsChain(component.Interface))
+		}
+	}
+
+	return inheritance.AddComponentContents(effectiveSpec, componentSpecs)
+}
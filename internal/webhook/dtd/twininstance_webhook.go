@@ -0,0 +1,465 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/graph"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/revision"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-dtd-ktwin-v0-twininstance,mutating=false,failurePolicy=fail,sideEffects=None,groups=dtd.ktwin,resources=twininstances,verbs=create;update,versions=v0,name=vtwininstance.ktwin,admissionReviewVersions=v1
+
+// TwinInstanceValidator rejects TwinInstances whose relationships and data do not match their TwinInterface,
+// including the relationships and properties inherited from the parent TwinInterfaces
+type TwinInstanceValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &TwinInstanceValidator{}
+
+func (v *TwinInstanceValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&dtdv0.TwinInstance{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *TwinInstanceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	twinInstance, ok := obj.(*dtdv0.TwinInstance)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a TwinInstance but got a %T", obj))
+	}
+	return nil, v.validateTwinInstance(ctx, nil, twinInstance)
+}
+
+func (v *TwinInstanceValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldTwinInstance, ok := oldObj.(*dtdv0.TwinInstance)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a TwinInstance but got a %T", oldObj))
+	}
+	twinInstance, ok := newObj.(*dtdv0.TwinInstance)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a TwinInstance but got a %T", newObj))
+	}
+
+	// The metadata updates and the updates of TwinInstances being deleted must not be rejected because of the
+	// TwinInterfaces and TwinInstances they depend on, which may be already deleted
+	if !twinInstance.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldTwinInstance.Spec, twinInstance.Spec) {
+		return nil, nil
+	}
+
+	return nil, v.validateTwinInstance(ctx, oldTwinInstance, twinInstance)
+}
+
+func (v *TwinInstanceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *TwinInstanceValidator) validateTwinInstance(ctx context.Context, oldTwinInstance *dtdv0.TwinInstance, twinInstance *dtdv0.TwinInstance) error {
+	twinInterfaces, err := listTwinInterfaces(ctx, v.Client, twinInstance.Namespace)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	twinInterfaceGraph := getTwinInterfaceGraph(twinInterfaces, nil)
+
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if twinInstance.Spec.Interface == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("interface"), "TwinInterface must be informed"))
+	} else if twinInterfaceGraph.IsTemporaryVertex(twinInstance.Spec.Interface) {
+		allErrs = append(allErrs, field.NotFound(specPath.Child("interface"), twinInstance.Spec.Interface))
+	} else if twinInterface, err := revision.ResolveTwinInterface(*twinInterfaceGraph.GetVertex(twinInstance.Spec.Interface), twinInstance.Spec.InterfaceVersion, twinInterfaces); err != nil {
+		allErrs = append(allErrs, field.NotFound(specPath.Child("interfaceVersion"), twinInstance.Spec.InterfaceVersion))
+	} else {
+		// The TwinInstance data must match the TwinInterface version that processes its events
+		effectiveSpec := getEffectiveSpec(twinInterfaceGraph, twinInterface.Name)
+
+		relationshipErrs, err := v.validateRelationships(ctx, twinInstance, effectiveSpec, twinInterfaceGraph, specPath.Child("twinInstanceRelationships"))
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+
+		allErrs = append(allErrs, relationshipErrs...)
+		allErrs = append(allErrs, validatePropertiesData(oldTwinInstance, twinInstance, effectiveSpec, specPath.Child("data", "properties"))...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(dtdv0.GroupVersion.WithKind("TwinInstance").GroupKind(), twinInstance.Name, allErrs)
+}
+
+func (v *TwinInstanceValidator) validateRelationships(
+	ctx context.Context,
+	twinInstance *dtdv0.TwinInstance,
+	effectiveSpec dtdv0.TwinInterfaceSpec,
+	twinInterfaceGraph graph.TwinInterfaceGraph,
+	fldPath *field.Path,
+) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	declaredRelationships := map[string]dtdv0.TwinRelationship{}
+	for _, relationship := range effectiveSpec.Relationships {
+		declaredRelationships[relationship.Name] = relationship
+	}
+
+	var relationshipNames []string
+	relationshipCount := map[string]int{}
+
+	for i, instanceRelationship := range twinInstance.Spec.TwinInstanceRelationships {
+		relationshipPath := fldPath.Index(i)
+
+		relationship, ok := declaredRelationships[instanceRelationship.Name]
+		if !ok {
+			allErrs = append(allErrs, field.NotFound(relationshipPath.Child("name"), instanceRelationship.Name))
+			continue
+		}
+
+		if relationshipCount[relationship.Name] == 0 {
+			relationshipNames = append(relationshipNames, relationship.Name)
+		}
+		relationshipCount[relationship.Name]++
+
+		if !isTwinInterfaceOrChild(instanceRelationship.Interface, relationship.Interface, twinInterfaceGraph) {
+			allErrs = append(allErrs, field.Invalid(relationshipPath.Child("interface"), instanceRelationship.Interface, fmt.Sprintf("relationship %s targets TwinInterface %s", relationship.Name, relationship.Interface)))
+		}
+
+		targetTwinInstance := &dtdv0.TwinInstance{}
+		err := v.Client.Get(ctx, types.NamespacedName{Namespace: twinInstance.Namespace, Name: instanceRelationship.Instance}, targetTwinInstance)
+
+		// The target TwinInstance may be created after the source TwinInstance
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTwinInterfaceOrChild(targetTwinInstance.Spec.Interface, relationship.Interface, twinInterfaceGraph) {
+			allErrs = append(allErrs, field.Invalid(relationshipPath.Child("instance"), instanceRelationship.Instance, fmt.Sprintf("TwinInstance has TwinInterface %s, expected %s", targetTwinInstance.Spec.Interface, relationship.Interface)))
+		}
+	}
+
+	for _, name := range relationshipNames {
+		relationship := declaredRelationships[name]
+		if relationship.MaxMultiplicity > 0 && relationshipCount[name] > relationship.MaxMultiplicity {
+			allErrs = append(allErrs, field.Invalid(fldPath, relationshipCount[name], fmt.Sprintf("relationship %s must have at most %d instances", name, relationship.MaxMultiplicity)))
+		}
+	}
+
+	return allErrs, nil
+}
+
+// Check if the TwinInterface is the expected one or extends it
+func isTwinInterfaceOrChild(twinInterfaceName string, expectedTwinInterfaceName string, twinInterfaceGraph graph.TwinInterfaceGraph) bool {
+	if twinInterfaceName == expectedTwinInterfaceName {
+		return true
+	}
+
+	for _, twinInterface := range twinInterfaceGraph.GetExtendsChain(twinInterfaceName) {
+		if twinInterface.Name == expectedTwinInterfaceName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validatePropertiesData(oldTwinInstance *dtdv0.TwinInstance, twinInstance *dtdv0.TwinInstance, effectiveSpec dtdv0.TwinInterfaceSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	declaredProperties := map[string]dtdv0.TwinProperty{}
+	for _, property := range effectiveSpec.Properties {
+		declaredProperties[property.Name] = property
+	}
+
+	var oldProperties []dtdv0.TwinInstancePropertyData
+	if oldTwinInstance != nil && oldTwinInstance.Spec.Data != nil {
+		oldProperties = oldTwinInstance.Spec.Data.Properties
+	}
+
+	var properties []dtdv0.TwinInstancePropertyData
+	if twinInstance.Spec.Data != nil {
+		properties = twinInstance.Spec.Data.Properties
+	}
+
+	oldValues := map[string]string{}
+	for _, propertyData := range oldProperties {
+		oldValues[propertyData.Name] = propertyData.Value
+	}
+
+	values := map[string]bool{}
+	for _, propertyData := range properties {
+		values[propertyData.Name] = true
+	}
+
+	// Non-writable properties with a value can not be removed, as they could be added back with another value
+	for _, propertyData := range oldProperties {
+		if property, ok := declaredProperties[propertyData.Name]; ok && !property.Writeable && propertyData.Value != "" && !values[propertyData.Name] {
+			allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("property %s is not writable and can not be removed", property.Name)))
+		}
+	}
+
+	for i, propertyData := range properties {
+		propertyPath := fldPath.Index(i)
+
+		property, ok := declaredProperties[propertyData.Name]
+		if !ok {
+			allErrs = append(allErrs, field.NotFound(propertyPath.Child("name"), propertyData.Name))
+			continue
+		}
+
+		// Non-writable properties are set once, by the TwinInstance creation or a later update, and then kept
+		if oldValue := oldValues[propertyData.Name]; !property.Writeable && oldValue != "" && oldValue != propertyData.Value {
+			allErrs = append(allErrs, field.Forbidden(propertyPath.Child("value"), fmt.Sprintf("property %s is not writable", property.Name)))
+			continue
+		}
+
+		if err := validatePropertyValue(property.Schema, propertyData.Value); err != nil {
+			allErrs = append(allErrs, field.Invalid(propertyPath.Child("value"), propertyData.Value, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// Check if the value can be parsed as the property schema. Values not informed are accepted.
+// Values of object, array and map schemas are expected in JSON format.
+func validatePropertyValue(schema *dtdv0.TwinSchema, value string) error {
+	if schema == nil || value == "" {
+		return nil
+	}
+
+	if schema.ComplexType != nil || schema.ArrayType != nil || schema.MapType != nil {
+		var jsonValue interface{}
+		if err := json.Unmarshal([]byte(value), &jsonValue); err != nil {
+			return fmt.Errorf("must be a valid JSON value")
+		}
+		return validateJSONValue(schema, jsonValue)
+	}
+
+	if schema.EnumType != nil {
+		for _, enumValue := range schema.EnumType.EnumValues {
+			if enumValue.EnumValue == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of the enum values")
+	}
+
+	return validatePrimitiveValue(schema.PrimitiveType, value)
+}
+
+// Check if a value decoded from JSON matches the schema, including the nested schemas. Values not informed are accepted.
+func validateJSONValue(schema *dtdv0.TwinSchema, value interface{}) error {
+	if schema == nil || value == nil {
+		return nil
+	}
+
+	switch {
+	case schema.ComplexType != nil:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("must be an object")
+		}
+
+		declaredFields := map[string]bool{}
+		for _, complexField := range schema.ComplexType.Fields {
+			declaredFields[complexField.Name] = true
+			if err := validateJSONValue(complexField.Schema, object[complexField.Name]); err != nil {
+				return fmt.Errorf("field %s %s", complexField.Name, err.Error())
+			}
+		}
+
+		for _, key := range getSortedKeys(object) {
+			if !declaredFields[key] {
+				return fmt.Errorf("field %s is not declared", key)
+			}
+		}
+	case schema.ArrayType != nil:
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("must be an array")
+		}
+
+		for i, element := range array {
+			if err := validateJSONValue(schema.ArrayType.ElementSchema, element); err != nil {
+				return fmt.Errorf("element %d %s", i, err.Error())
+			}
+		}
+	case schema.MapType != nil:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("must be a map")
+		}
+
+		for _, key := range getSortedKeys(object) {
+			if err := validateJSONValue(schema.MapType.MapValue.Schema, object[key]); err != nil {
+				return fmt.Errorf("value of %s %s", key, err.Error())
+			}
+		}
+	default:
+		stringValue, isString := value.(string)
+
+		if isString != isStringSchema(schema) {
+			if isString {
+				return fmt.Errorf("must not be a JSON string")
+			}
+			return fmt.Errorf("must be a JSON string")
+		}
+
+		if isString {
+			return validatePropertyValue(schema, stringValue)
+		}
+
+		// Numbers, booleans and geospatial values are validated in their JSON representation
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return validatePropertyValue(schema, string(encodedValue))
+	}
+
+	return nil
+}
+
+// Check if the values of the schema are JSON strings inside object, array and map values
+func isStringSchema(schema *dtdv0.TwinSchema) bool {
+	if schema.EnumType != nil {
+		return schema.EnumType.ValueSchema == dtdv0.String
+	}
+
+	switch schema.PrimitiveType {
+	case dtdv0.String, dtdv0.Date, dtdv0.DateTime, dtdv0.Time, dtdv0.Duration:
+		return true
+	}
+
+	return false
+}
+
+func getSortedKeys(object map[string]interface{}) []string {
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var (
+	durationRegexp = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+W)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+
+	// GeoJSON geometry type of each geospatial schema
+	geospatialTypes = map[dtdv0.PrimitiveType]string{
+		dtdv0.Point:           "Point",
+		dtdv0.MultiPoint:      "MultiPoint",
+		dtdv0.LineString:      "LineString",
+		dtdv0.MultiLineString: "MultiLineString",
+		dtdv0.Polygon:         "Polygon",
+		dtdv0.MultiPolygon:    "MultiPolygon",
+	}
+)
+
+func validatePrimitiveValue(primitiveType dtdv0.PrimitiveType, value string) error {
+	var err error
+
+	switch primitiveType {
+	case dtdv0.Integer:
+		_, err = strconv.ParseInt(value, 10, 32)
+	case dtdv0.Long:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case dtdv0.Float:
+		_, err = strconv.ParseFloat(value, 32)
+	case dtdv0.Double:
+		_, err = strconv.ParseFloat(value, 64)
+	case dtdv0.Boolean:
+		_, err = strconv.ParseBool(value)
+	case dtdv0.Date:
+		_, err = time.Parse("2006-01-02", value)
+	case dtdv0.DateTime:
+		_, err = time.Parse(time.RFC3339, value)
+	case dtdv0.Time:
+		if _, err = time.Parse("15:04:05", value); err != nil {
+			_, err = time.Parse("15:04:05Z07:00", value)
+		}
+	case dtdv0.Duration:
+		err = validateDurationValue(value)
+	case dtdv0.Point, dtdv0.MultiPoint, dtdv0.LineString, dtdv0.MultiLineString, dtdv0.Polygon, dtdv0.MultiPolygon:
+		err = validateGeospatialValue(primitiveType, value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("must be a valid %s", primitiveType)
+	}
+
+	return nil
+}
+
+// Durations are informed in ISO 8601 format, such as P1DT2H30M
+func validateDurationValue(value string) error {
+	if value == "P" || strings.HasSuffix(value, "T") || !durationRegexp.MatchString(value) {
+		return fmt.Errorf("invalid duration %s", value)
+	}
+	return nil
+}
+
+// Geospatial values are informed as GeoJSON geometries, such as {"type": "Point", "coordinates": [-51.23, -30.03]}
+func validateGeospatialValue(primitiveType dtdv0.PrimitiveType, value string) error {
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+
+	if err := json.Unmarshal([]byte(value), &geometry); err != nil {
+		return err
+	}
+
+	if geometry.Type != geospatialTypes[primitiveType] {
+		return fmt.Errorf("invalid geometry type %s", geometry.Type)
+	}
+
+	var coordinates []interface{}
+	if err := json.Unmarshal(geometry.Coordinates, &coordinates); err != nil || len(coordinates) == 0 {
+		return fmt.Errorf("invalid geometry coordinates")
+	}
+
+	return nil
+}
@@ -0,0 +1,458 @@
+package dtd
+
+import (
+	"context"
+	"testing"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTwinInstance(name string, spec dtdv0.TwinInstanceSpec) *dtdv0.TwinInstance {
+	return &dtdv0.TwinInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+}
+
+func newPropertiesData(properties ...dtdv0.TwinInstancePropertyData) *dtdv0.TwinInstanceDataSpec {
+	return &dtdv0.TwinInstanceDataSpec{
+		Properties: properties,
+	}
+}
+
+var twinInstanceWebhookObjects = []client.Object{
+	newTwinInterface("place", dtdv0.TwinInterfaceSpec{
+		Properties: []dtdv0.TwinProperty{
+			{Name: "name", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.String}},
+		},
+		Relationships: []dtdv0.TwinRelationship{
+			{Name: "refCity", Interface: "city", MaxMultiplicity: 1},
+		},
+	}),
+	newTwinInterface("city", dtdv0.TwinInterfaceSpec{}),
+	newTwinInterface("airqualitysensor", dtdv0.TwinInterfaceSpec{
+		Properties: []dtdv0.TwinProperty{
+			{Name: "co2", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}},
+		},
+	}),
+	newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+		ExtendsInterface: "place",
+		Components: []dtdv0.TwinComponent{
+			{Name: "airQualitySensor", Interface: "airqualitysensor"},
+		},
+		Properties: []dtdv0.TwinProperty{
+			{Name: "population", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Integer}, Writeable: true},
+			{Name: "area", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}},
+			{
+				Name: "status",
+				Schema: &dtdv0.TwinSchema{
+					EnumType: &dtdv0.TwinEnumSchema{
+						ValueSchema: dtdv0.String,
+						EnumValues: []dtdv0.TwinEnumSchemaValues{
+							{Name: "active", EnumValue: "active"},
+							{Name: "inactive", EnumValue: "inactive"},
+						},
+					},
+				},
+				Writeable: true,
+			},
+			{
+				Name: "location",
+				Schema: &dtdv0.TwinSchema{
+					ComplexType: &dtdv0.TwinComplexType{
+						Type: dtdv0.Object,
+						Fields: []dtdv0.TwinComplexTypeFields{
+							{Name: "latitude", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}},
+							{Name: "longitude", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}},
+						},
+					},
+				},
+			},
+			{
+				Name: "sensors",
+				Schema: &dtdv0.TwinSchema{
+					MapType: &dtdv0.TwinMapSchema{
+						MapKey: dtdv0.TwinMapKey{Name: "sensorId"},
+						MapValue: dtdv0.TwinMapValue{
+							Name:   "readings",
+							Schema: &dtdv0.TwinSchema{ArrayType: &dtdv0.TwinArraySchema{ElementSchema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Integer}}},
+						},
+					},
+				},
+			},
+		},
+	}),
+	newTwinInterface("dtmi-city-pole-1", dtdv0.TwinInterfaceSpec{ModelId: "dtmi:city:Pole", Version: 1}),
+	newTwinInterface("dtmi-city-pole-2", dtdv0.TwinInterfaceSpec{
+		ModelId:    "dtmi:city:Pole",
+		Version:    2,
+		Properties: []dtdv0.TwinProperty{{Name: "height", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}}},
+	}),
+	newTwinInstance("city-001", dtdv0.TwinInstanceSpec{Interface: "city"}),
+	newTwinInstance("neighborhood-001", dtdv0.TwinInstanceSpec{Interface: "neighborhood"}),
+}
+
+func TestTwinInstanceValidator_ValidateCreate(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		twinInstance   *dtdv0.TwinInstance
+		expectedFields []string
+	}{
+		{
+			name: "Valid TwinInstance with inherited relationship and properties",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				TwinInstanceRelationships: []dtdv0.TwinInstanceRelationship{
+					{Name: "refCity", Interface: "city", Instance: "city-001"},
+				},
+				Data: newPropertiesData(
+					dtdv0.TwinInstancePropertyData{Name: "name", Value: "Downtown"},
+					dtdv0.TwinInstancePropertyData{Name: "population", Value: "1200"},
+					dtdv0.TwinInstancePropertyData{Name: "area", Value: "12.5"},
+					dtdv0.TwinInstancePropertyData{Name: "status", Value: "active"},
+					dtdv0.TwinInstancePropertyData{Name: "status"},
+					dtdv0.TwinInstancePropertyData{Name: "location", Value: `{"latitude": -30.03, "longitude": -51.23}`},
+					dtdv0.TwinInstancePropertyData{Name: "sensors", Value: `{"s1": [1, 2], "s2": []}`},
+					dtdv0.TwinInstancePropertyData{Name: "airQualitySensor.co2", Value: "400.5"},
+				),
+			}),
+		},
+		{
+			name:           "TwinInterface not found",
+			twinInstance:   newTwinInstance("pole-001", dtdv0.TwinInstanceSpec{Interface: "pole"}),
+			expectedFields: []string{"spec.interface"},
+		},
+		{
+			name: "Properties of the latest TwinInterface version",
+			twinInstance: newTwinInstance("pole-001", dtdv0.TwinInstanceSpec{
+				Interface:        "dtmi-city-pole-1",
+				InterfaceVersion: "latest",
+				Data:             newPropertiesData(dtdv0.TwinInstancePropertyData{Name: "height", Value: "10.5"}),
+			}),
+		},
+		{
+			name: "Properties of another TwinInterface version",
+			twinInstance: newTwinInstance("pole-001", dtdv0.TwinInstanceSpec{
+				Interface:        "dtmi-city-pole-2",
+				InterfaceVersion: "1",
+				Data:             newPropertiesData(dtdv0.TwinInstancePropertyData{Name: "height", Value: "10.5"}),
+			}),
+			expectedFields: []string{"spec.data.properties[0].name"},
+		},
+		{
+			name:           "TwinInterface version not found",
+			twinInstance:   newTwinInstance("pole-001", dtdv0.TwinInstanceSpec{Interface: "dtmi-city-pole-1", InterfaceVersion: "3"}),
+			expectedFields: []string{"spec.interfaceVersion"},
+		},
+		{
+			name: "Relationship not declared",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				TwinInstanceRelationships: []dtdv0.TwinInstanceRelationship{
+					{Name: "refPole", Interface: "pole", Instance: "pole-001"},
+				},
+			}),
+			expectedFields: []string{"spec.twinInstanceRelationships[0].name"},
+		},
+		{
+			name: "Relationship to an instance of another TwinInterface",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				TwinInstanceRelationships: []dtdv0.TwinInstanceRelationship{
+					{Name: "refCity", Interface: "city", Instance: "neighborhood-001"},
+				},
+			}),
+			expectedFields: []string{"spec.twinInstanceRelationships[0].instance"},
+		},
+		{
+			name: "Relationship exceeding MaxMultiplicity",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				TwinInstanceRelationships: []dtdv0.TwinInstanceRelationship{
+					{Name: "refCity", Interface: "city", Instance: "city-001"},
+					{Name: "refCity", Interface: "city", Instance: "city-002"},
+				},
+			}),
+			expectedFields: []string{"spec.twinInstanceRelationships"},
+		},
+		{
+			name: "Property values not matching the schema",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				Data: newPropertiesData(
+					dtdv0.TwinInstancePropertyData{Name: "population", Value: "many"},
+					dtdv0.TwinInstancePropertyData{Name: "area", Value: "1,5"},
+					dtdv0.TwinInstancePropertyData{Name: "status", Value: "unknown"},
+					dtdv0.TwinInstancePropertyData{Name: "color", Value: "blue"},
+					dtdv0.TwinInstancePropertyData{Name: "location", Value: `{"latitude": "north"}`},
+					dtdv0.TwinInstancePropertyData{Name: "location", Value: `{"altitude": 10}`},
+					dtdv0.TwinInstancePropertyData{Name: "sensors", Value: `{"s1": [1.5]}`},
+					dtdv0.TwinInstancePropertyData{Name: "sensors", Value: `[1]`},
+					dtdv0.TwinInstancePropertyData{Name: "airQualitySensor.co2", Value: "high"},
+					dtdv0.TwinInstancePropertyData{Name: "co2", Value: "400.5"},
+				),
+			}),
+			expectedFields: []string{
+				"spec.data.properties[0].value",
+				"spec.data.properties[1].value",
+				"spec.data.properties[2].value",
+				"spec.data.properties[3].name",
+				"spec.data.properties[4].value",
+				"spec.data.properties[5].value",
+				"spec.data.properties[6].value",
+				"spec.data.properties[7].value",
+				"spec.data.properties[8].value",
+				"spec.data.properties[9].name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &TwinInstanceValidator{
+				Client: newFakeClient(twinInstanceWebhookObjects...),
+			}
+
+			_, err := validator.ValidateCreate(context.TODO(), tt.twinInstance)
+
+			if len(tt.expectedFields) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Equal(t, tt.expectedFields, getCauseFields(err))
+		})
+	}
+}
+
+func TestTwinInstanceValidator_ValidateUpdate(t *testing.T) {
+
+	oldTwinInstance := newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+		Interface: "neighborhood",
+		Data: newPropertiesData(
+			dtdv0.TwinInstancePropertyData{Name: "population", Value: "1200"},
+			dtdv0.TwinInstancePropertyData{Name: "area", Value: "12.5"},
+		),
+	})
+
+	// The TwinInterface of the TwinInstance was deleted
+	metadataTwinInstance := oldTwinInstance.DeepCopy()
+	metadataTwinInstance.Spec.Interface = "neighborhood-deleted"
+	metadataTwinInstance.Labels = map[string]string{"ktwin/twin-interface": "neighborhood-deleted"}
+
+	deletedTwinInstance := oldTwinInstance.DeepCopy()
+	deletionTimestamp := metav1.Now()
+	deletedTwinInstance.DeletionTimestamp = &deletionTimestamp
+	deletedTwinInstance.Spec.Interface = "neighborhood-deleted"
+	deletedTwinInstance.Spec.Data = nil
+
+	tests := []struct {
+		name           string
+		twinInstance   *dtdv0.TwinInstance
+		oldInterface   string
+		expectedFields []string
+	}{
+		{
+			name: "Writable property updated",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				Data: newPropertiesData(
+					dtdv0.TwinInstancePropertyData{Name: "population", Value: "1300"},
+					dtdv0.TwinInstancePropertyData{Name: "area", Value: "12.5"},
+				),
+			}),
+		},
+		{
+			name: "Non-writable property updated",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				Data: newPropertiesData(
+					dtdv0.TwinInstancePropertyData{Name: "population", Value: "1200"},
+					dtdv0.TwinInstancePropertyData{Name: "area", Value: "13"},
+				),
+			}),
+			expectedFields: []string{"spec.data.properties[1].value"},
+		},
+		{
+			name: "Non-writable property cleared",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				Data: newPropertiesData(
+					dtdv0.TwinInstancePropertyData{Name: "population", Value: "1200"},
+					dtdv0.TwinInstancePropertyData{Name: "area", Value: ""},
+				),
+			}),
+			expectedFields: []string{"spec.data.properties[1].value"},
+		},
+		{
+			name: "Non-writable property set after creation",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				Data: newPropertiesData(
+					dtdv0.TwinInstancePropertyData{Name: "population", Value: "1200"},
+					dtdv0.TwinInstancePropertyData{Name: "area", Value: "12.5"},
+					dtdv0.TwinInstancePropertyData{Name: "location", Value: `{"latitude": -30.03, "longitude": -51.23}`},
+				),
+			}),
+		},
+		{
+			name: "Non-writable property removed",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				Data:      newPropertiesData(dtdv0.TwinInstancePropertyData{Name: "population", Value: "1200"}),
+			}),
+			expectedFields: []string{"spec.data.properties"},
+		},
+		{
+			name:           "Data removed",
+			twinInstance:   newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{Interface: "neighborhood"}),
+			expectedFields: []string{"spec.data.properties"},
+		},
+		{
+			name: "Writable property removed",
+			twinInstance: newTwinInstance("neighborhood-002", dtdv0.TwinInstanceSpec{
+				Interface: "neighborhood",
+				Data:      newPropertiesData(dtdv0.TwinInstancePropertyData{Name: "area", Value: "12.5"}),
+			}),
+		},
+		{
+			name:         "Metadata update with an unchanged spec",
+			twinInstance: metadataTwinInstance,
+			oldInterface: "neighborhood-deleted",
+		},
+		{
+			name:         "Update of a TwinInstance being deleted",
+			twinInstance: deletedTwinInstance,
+			oldInterface: "neighborhood-deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &TwinInstanceValidator{
+				Client: newFakeClient(twinInstanceWebhookObjects...),
+			}
+
+			currentTwinInstance := oldTwinInstance
+			if tt.oldInterface != "" {
+				currentTwinInstance = oldTwinInstance.DeepCopy()
+				currentTwinInstance.Spec.Interface = tt.oldInterface
+			}
+
+			_, err := validator.ValidateUpdate(context.TODO(), currentTwinInstance, tt.twinInstance)
+
+			if len(tt.expectedFields) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Equal(t, tt.expectedFields, getCauseFields(err))
+		})
+	}
+}
+
+func TestTwinInstanceWebhook_ValidatePrimitiveValue(t *testing.T) {
+
+	tests := []struct {
+		primitiveType dtdv0.PrimitiveType
+		value         string
+		valid         bool
+	}{
+		{primitiveType: dtdv0.Integer, value: "2147483647", valid: true},
+		{primitiveType: dtdv0.Integer, value: "2147483648", valid: false},
+		{primitiveType: dtdv0.Long, value: "2147483648", valid: true},
+		{primitiveType: dtdv0.Long, value: "1.5", valid: false},
+		{primitiveType: dtdv0.Float, value: "1.5", valid: true},
+		{primitiveType: dtdv0.Float, value: "1e39", valid: false},
+		{primitiveType: dtdv0.Double, value: "1e39", valid: true},
+		{primitiveType: dtdv0.Boolean, value: "yes", valid: false},
+		{primitiveType: dtdv0.String, value: "any value", valid: true},
+		{primitiveType: dtdv0.Date, value: "2023-10-18", valid: true},
+		{primitiveType: dtdv0.Date, value: "18/10/2023", valid: false},
+		{primitiveType: dtdv0.DateTime, value: "2023-10-18T10:30:00Z", valid: true},
+		{primitiveType: dtdv0.DateTime, value: "2023-10-18T10:30:00.125-03:00", valid: true},
+		{primitiveType: dtdv0.DateTime, value: "2023-10-18 10:30", valid: false},
+		{primitiveType: dtdv0.Time, value: "10:30:00", valid: true},
+		{primitiveType: dtdv0.Time, value: "10:30:00.5Z", valid: true},
+		{primitiveType: dtdv0.Time, value: "25:00:00", valid: false},
+		{primitiveType: dtdv0.Duration, value: "P1DT2H30M", valid: true},
+		{primitiveType: dtdv0.Duration, value: "PT0.5S", valid: true},
+		{primitiveType: dtdv0.Duration, value: "P2W", valid: true},
+		{primitiveType: dtdv0.Duration, value: "P", valid: false},
+		{primitiveType: dtdv0.Duration, value: "P1DT", valid: false},
+		{primitiveType: dtdv0.Duration, value: "1 day", valid: false},
+		{primitiveType: dtdv0.Point, value: `{"type": "Point", "coordinates": [-51.23, -30.03]}`, valid: true},
+		{primitiveType: dtdv0.Point, value: `{"type": "Polygon", "coordinates": [[[0, 0], [1, 1], [0, 1], [0, 0]]]}`, valid: false},
+		{primitiveType: dtdv0.Polygon, value: `{"type": "Polygon", "coordinates": [[[0, 0], [1, 1], [0, 1], [0, 0]]]}`, valid: true},
+		{primitiveType: dtdv0.LineString, value: `{"type": "LineString"}`, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.primitiveType)+" "+tt.value, func(t *testing.T) {
+			err := validatePrimitiveValue(tt.primitiveType, tt.value)
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestTwinInstanceWebhook_ValidateJSONValue(t *testing.T) {
+
+	stringEnumSchema := &dtdv0.TwinSchema{
+		EnumType: &dtdv0.TwinEnumSchema{
+			ValueSchema: dtdv0.String,
+			EnumValues:  []dtdv0.TwinEnumSchemaValues{{Name: "one", EnumValue: "1"}},
+		},
+	}
+
+	integerEnumSchema := &dtdv0.TwinSchema{
+		EnumType: &dtdv0.TwinEnumSchema{
+			ValueSchema: dtdv0.Integer,
+			EnumValues:  []dtdv0.TwinEnumSchemaValues{{Name: "one", EnumValue: "1"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		schema *dtdv0.TwinSchema
+		value  string
+		valid  bool
+	}{
+		{name: "String", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.String}, value: `"north"`, valid: true},
+		{name: "Number as string", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.String}, value: `10`, valid: false},
+		{name: "Boolean as string", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.String}, value: `true`, valid: false},
+		{name: "Date", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Date}, value: `"2023-10-18"`, valid: true},
+		{name: "Number as date", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Date}, value: `20231018`, valid: false},
+		{name: "Integer", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Integer}, value: `10`, valid: true},
+		{name: "String as integer", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Integer}, value: `"10"`, valid: false},
+		{name: "Point", schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Point}, value: `{"type": "Point", "coordinates": [-51.23, -30.03]}`, valid: true},
+		{name: "String enum", schema: stringEnumSchema, value: `"1"`, valid: true},
+		{name: "Number as string enum", schema: stringEnumSchema, value: `1`, valid: false},
+		{name: "Integer enum", schema: integerEnumSchema, value: `1`, valid: true},
+		{name: "String as integer enum", schema: integerEnumSchema, value: `"1"`, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &dtdv0.TwinSchema{
+				ComplexType: &dtdv0.TwinComplexType{
+					Type:   dtdv0.Object,
+					Fields: []dtdv0.TwinComplexTypeFields{{Name: "field", Schema: tt.schema}},
+				},
+			}
+
+			err := validatePropertyValue(schema, `{"field": `+tt.value+`}`)
+			assert.Equal(t, tt.valid, err == nil, err)
+		})
+	}
+}
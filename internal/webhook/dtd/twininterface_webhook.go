@@ -0,0 +1,442 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/graph"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/revision"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-dtd-ktwin-v0-twininterface,mutating=false,failurePolicy=fail,sideEffects=None,groups=dtd.ktwin,resources=twininterfaces,verbs=create;update,versions=v0,name=vtwininterface.ktwin,admissionReviewVersions=v1
+
+// TwinInterfaceValidator rejects TwinInterfaces with invalid relationships, inheritance, schemas or names
+type TwinInterfaceValidator struct {
+	Client    client.Client
+	TwinEvent event.TwinEvent
+	// Accept relationships to TwinInterfaces not created yet with a warning, so TwinInterfaces referencing each other
+	// can be applied one after the other. They are rejected by default.
+	AllowPendingRelationships bool
+}
+
+var _ webhook.CustomValidator = &TwinInterfaceValidator{}
+
+func (v *TwinInterfaceValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&dtdv0.TwinInterface{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *TwinInterfaceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	twinInterface, ok := obj.(*dtdv0.TwinInterface)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a TwinInterface but got a %T", obj))
+	}
+	return v.validateTwinInterface(ctx, twinInterface)
+}
+
+func (v *TwinInterfaceValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldTwinInterface, ok := oldObj.(*dtdv0.TwinInterface)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a TwinInterface but got a %T", oldObj))
+	}
+	twinInterface, ok := newObj.(*dtdv0.TwinInterface)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a TwinInterface but got a %T", newObj))
+	}
+
+	// The finalizer and labels patched by the controller, and the updates of TwinInterfaces being deleted, must not
+	// be rejected because of the TwinInterfaces they depend on, which may be already deleted
+	if !twinInterface.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldTwinInterface.Spec, twinInterface.Spec) {
+		return nil, nil
+	}
+
+	return v.validateTwinInterface(ctx, twinInterface)
+}
+
+func (v *TwinInterfaceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *TwinInterfaceValidator) validateTwinInterface(ctx context.Context, twinInterface *dtdv0.TwinInterface) (admission.Warnings, error) {
+	twinInterfaces, err := listTwinInterfaces(ctx, v.Client, twinInterface.Namespace)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	twinInterfaceGraph := getTwinInterfaceGraph(twinInterfaces, twinInterface)
+
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateModelVersion(twinInterface, twinInterfaces, specPath)...)
+	allErrs = append(allErrs, validateExtends(twinInterface, twinInterfaceGraph, specPath)...)
+	allErrs = append(allErrs, validateRelationships(twinInterface.Spec.Relationships, twinInterfaceGraph, v.AllowPendingRelationships, specPath.Child("relationships"))...)
+	allErrs = append(allErrs, validateProperties(twinInterface.Spec.Properties, specPath.Child("properties"))...)
+
+	allErrs = append(allErrs, validateComponents(twinInterface, twinInterfaceGraph, specPath.Child("components"))...)
+
+	for i, telemetry := range twinInterface.Spec.Telemetries {
+		telemetryPath := specPath.Child("telemetries").Index(i)
+		allErrs = append(allErrs, validateSchema(telemetry.Schema, telemetryPath.Child("schema"))...)
+		allErrs = append(allErrs, validateUnit(telemetry.Unit, telemetry.SemanticTypes, telemetryPath.Child("unit"))...)
+	}
+
+	for i, command := range twinInterface.Spec.Commands {
+		commandPath := specPath.Child("commands").Index(i)
+		allErrs = append(allErrs, validateNameSegment(command.Name, commandPath.Child("name"))...)
+		allErrs = append(allErrs, validateSchema(command.Request.Schema, commandPath.Child("request", "schema"))...)
+		allErrs = append(allErrs, validateSchema(command.Response.Schema, commandPath.Child("response", "schema"))...)
+	}
+
+	// Only check the generated names once the names they are derived from are valid, to avoid reporting the same error twice
+	if len(allErrs) == 0 {
+		allErrs = append(allErrs, v.validateBindingNames(twinInterface, twinInterfaceGraph)...)
+	}
+
+	var warnings admission.Warnings
+	if v.AllowPendingRelationships {
+		warnings = getPendingRelationshipWarnings(twinInterface.Spec.Relationships, twinInterfaceGraph, specPath.Child("relationships"))
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+
+	return warnings, apierrors.NewInvalid(dtdv0.GroupVersion.WithKind("TwinInterface").GroupKind(), twinInterface.Name, allErrs)
+}
+
+// The names of the RabbitMQ Bindings are derived from the TwinInterface, relationship and command names
+// and must be valid RFC 1123 subdomains to be created in the cluster.
+func (v *TwinInterfaceValidator) validateBindingNames(twinInterface *dtdv0.TwinInterface, twinInterfaceGraph graph.TwinInterfaceGraph) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// The bindings are generated from the inherited definitions, the effective spec stored in the status may be outdated
+	effectiveSpec := getEffectiveSpec(twinInterfaceGraph, twinInterface.Name)
+	twinInterface = twinInterface.DeepCopy()
+	twinInterface.Status.EffectiveSpec = &effectiveSpec
+
+	var bindings []rabbitmqv1beta1.Binding
+	bindings = append(bindings, v.TwinEvent.GetMQQTDispatcherBindings(twinInterface)...)
+	bindings = append(bindings, v.TwinEvent.GetVirtualCloudEventBrokerBinding(twinInterface, rabbitmqv1beta1.Exchange{})...)
+	bindings = append(bindings, v.TwinEvent.GetRelationshipBrokerBindings(twinInterface, rabbitmqv1beta1.Exchange{}, rabbitmqv1beta1.Queue{})...)
+	bindings = append(bindings, v.TwinEvent.GetTwinInterfaceCommandBindings(twinInterface, rabbitmqv1beta1.Exchange{}, rabbitmqv1beta1.Queue{})...)
+
+	for _, binding := range bindings {
+		for _, msg := range validation.IsDNS1123Subdomain(binding.Name) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), twinInterface.Name, fmt.Sprintf("generated binding name %s is invalid: %s", binding.Name, msg)))
+		}
+	}
+
+	return allErrs
+}
+
+// Validate the model and version of the TwinInterface, which are informed together. Each version of a model is
+// declared by a single TwinInterface.
+func validateModelVersion(twinInterface *dtdv0.TwinInterface, twinInterfaces []dtdv0.TwinInterface, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if twinInterface.Spec.ModelId != "" && twinInterface.Spec.Version == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("version"), "version must be informed with modelId"))
+	}
+
+	if twinInterface.Spec.ModelId == "" && twinInterface.Spec.Version != 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("modelId"), "modelId must be informed with version"))
+	}
+
+	if len(allErrs) > 0 || twinInterface.Spec.ModelId == "" {
+		return allErrs
+	}
+
+	for _, version := range revision.GetModelVersions(*twinInterface, twinInterfaces) {
+		if version.Name != twinInterface.Name && version.Spec.Version == twinInterface.Spec.Version {
+			allErrs = append(allErrs, field.Duplicate(specPath.Child("version"), twinInterface.Spec.Version))
+			break
+		}
+	}
+
+	return allErrs
+}
+
+// Validate the TwinInterfaces informed in extends and in the deprecated extendsInterface field
+func validateExtends(twinInterface *dtdv0.TwinInterface, twinInterfaceGraph graph.TwinInterfaceGraph, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	var parentNames []string
+	var parentPaths []*field.Path
+
+	if twinInterface.Spec.ExtendsInterface != "" {
+		parentNames = append(parentNames, twinInterface.Spec.ExtendsInterface)
+		parentPaths = append(parentPaths, specPath.Child("extendsInterface"))
+	}
+
+	for i, parentName := range twinInterface.Spec.Extends {
+		parentPath := specPath.Child("extends").Index(i)
+
+		if getIndex(parentNames, parentName) >= 0 {
+			allErrs = append(allErrs, field.Duplicate(parentPath, parentName))
+			continue
+		}
+
+		parentNames = append(parentNames, parentName)
+		parentPaths = append(parentPaths, parentPath)
+	}
+
+	if len(parentNames) > MAX_EXTENDS {
+		allErrs = append(allErrs, field.TooMany(specPath.Child("extends"), len(parentNames), MAX_EXTENDS))
+	}
+
+	for i, parentName := range parentNames {
+		if twinInterfaceGraph.IsTemporaryVertex(parentName) {
+			allErrs = append(allErrs, field.NotFound(parentPaths[i], parentName))
+		}
+	}
+
+	if len(parentNames) == 0 || len(allErrs) > 0 {
+		return allErrs
+	}
+
+	if cycle := twinInterfaceGraph.GetExtendsCycle(twinInterface.Name); cycle != nil {
+		// Report the cycle in the parent it goes through, or in the first parent when the cycle is above it
+		index := 0
+		if cycle[0] == twinInterface.Name && len(cycle) > 1 {
+			index = getIndex(parentNames, cycle[1])
+		}
+		return append(allErrs, field.Invalid(parentPaths[index], parentNames[index], "inheritance cycle: "+strings.Join(cycle, " -> ")))
+	}
+
+	for _, conflict := range inheritance.GetInheritanceConflicts(twinInterfaceGraph.GetExtendsChain(twinInterface.Name)) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("extends"), parentNames, conflict.String()))
+	}
+
+	return allErrs
+}
+
+func getIndex(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateComponents(twinInterface *dtdv0.TwinInterface, twinInterfaceGraph graph.TwinInterfaceGraph, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	componentNames := map[string]bool{}
+
+	for i, component := range twinInterface.Spec.Components {
+		componentPath := fldPath.Index(i)
+
+		allErrs = append(allErrs, validateNameSegment(component.Name, componentPath.Child("name"))...)
+
+		if componentNames[component.Name] {
+			allErrs = append(allErrs, field.Duplicate(componentPath.Child("name"), component.Name))
+		}
+		componentNames[component.Name] = true
+
+		if component.Interface == "" {
+			allErrs = append(allErrs, field.Required(componentPath.Child("interface"), "component interface must be informed"))
+		} else if component.Interface == twinInterface.Name {
+			allErrs = append(allErrs, field.Invalid(componentPath.Child("interface"), component.Interface, "TwinInterface can not be a component of itself"))
+		} else if twinInterfaceGraph.IsTemporaryVertex(component.Interface) {
+			allErrs = append(allErrs, field.NotFound(componentPath.Child("interface"), component.Interface))
+		} else if len(inheritance.GetEffectiveSpec(twinInterfaceGraph.GetExtendsChain(component.Interface)).Components) > 0 {
+			// Only one level of components is resolved, as in DTDL
+			allErrs = append(allErrs, field.Invalid(componentPath.Child("interface"), component.Interface, "TwinInterface used as component must not have components"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateRelationships(relationships []dtdv0.TwinRelationship, twinInterfaceGraph graph.TwinInterfaceGraph, allowPendingRelationships bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, relationship := range relationships {
+		relationshipPath := fldPath.Index(i)
+
+		allErrs = append(allErrs, validateNameSegment(relationship.Name, relationshipPath.Child("name"))...)
+
+		if relationship.Interface == "" {
+			allErrs = append(allErrs, field.Required(relationshipPath.Child("interface"), "relationship target interface must be informed"))
+		} else if !allowPendingRelationships && twinInterfaceGraph.IsTemporaryVertex(relationship.Interface) {
+			allErrs = append(allErrs, field.NotFound(relationshipPath.Child("interface"), relationship.Interface))
+		}
+
+		if relationship.MinMultiplicity < 0 {
+			allErrs = append(allErrs, field.Invalid(relationshipPath.Child("minMultiplicity"), relationship.MinMultiplicity, "must be greater than or equal to 0"))
+		}
+
+		if relationship.MaxMultiplicity < 0 {
+			allErrs = append(allErrs, field.Invalid(relationshipPath.Child("maxMultiplicity"), relationship.MaxMultiplicity, "must be greater than or equal to 0"))
+		}
+
+		// A MaxMultiplicity not informed means an unbounded relationship
+		if relationship.MaxMultiplicity > 0 && relationship.MinMultiplicity > relationship.MaxMultiplicity {
+			allErrs = append(allErrs, field.Invalid(relationshipPath.Child("minMultiplicity"), relationship.MinMultiplicity, fmt.Sprintf("must be less than or equal to maxMultiplicity (%d)", relationship.MaxMultiplicity)))
+		}
+
+		allErrs = append(allErrs, validateSchema(relationship.Schema, relationshipPath.Child("schema"))...)
+		allErrs = append(allErrs, validateProperties(relationship.Properties, relationshipPath.Child("properties"))...)
+	}
+
+	return allErrs
+}
+
+// When pending relationships are allowed, relationship targets may be created after the TwinInterface, unlike the
+// extended TwinInterfaces and the components. Missing targets are only warned.
+func getPendingRelationshipWarnings(relationships []dtdv0.TwinRelationship, twinInterfaceGraph graph.TwinInterfaceGraph, fldPath *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+
+	for i, relationship := range relationships {
+		if relationship.Interface != "" && twinInterfaceGraph.IsTemporaryVertex(relationship.Interface) {
+			warnings = append(warnings, fmt.Sprintf("%s: TwinInterface %s not found, the relationship is pending until it is created", fldPath.Index(i).Child("interface"), relationship.Interface))
+		}
+	}
+
+	return warnings
+}
+
+func validateProperties(properties []dtdv0.TwinProperty, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, property := range properties {
+		allErrs = append(allErrs, validateSchema(property.Schema, fldPath.Index(i).Child("schema"))...)
+		allErrs = append(allErrs, validateUnit(property.Unit, property.SemanticTypes, fldPath.Index(i).Child("unit"))...)
+	}
+
+	return allErrs
+}
+
+// As in DTDL, units are only allowed for contents with a semantic type
+func validateUnit(unit string, semanticTypes []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if unit != "" && len(semanticTypes) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, unit, "unit requires a semantic type"))
+	}
+
+	return allErrs
+}
+
+func validateSchema(schema *dtdv0.TwinSchema, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if schema == nil {
+		return allErrs
+	}
+
+	// Nested schemas are not validated by the CRD schema
+	if schema.PrimitiveType != "" && !isSupportedValue(supportedPrimitiveTypes, string(schema.PrimitiveType)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("primitiveType"), schema.PrimitiveType, supportedPrimitiveTypes))
+	}
+
+	if schema.EnumType != nil {
+		if !isSupportedValue(supportedEnumValueSchemas, string(schema.EnumType.ValueSchema)) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("enumType", "valueSchema"), schema.EnumType.ValueSchema, supportedEnumValueSchemas))
+		}
+
+		enumValues := map[string]bool{}
+		enumValuesPath := fldPath.Child("enumType", "enumValues")
+
+		for i, enumValue := range schema.EnumType.EnumValues {
+			if enumValues[enumValue.EnumValue] {
+				allErrs = append(allErrs, field.Duplicate(enumValuesPath.Index(i).Child("enumValue"), enumValue.EnumValue))
+			}
+			enumValues[enumValue.EnumValue] = true
+		}
+	}
+
+	if schema.ComplexType != nil {
+		fieldNames := map[string]bool{}
+		fieldsPath := fldPath.Child("complexType", "fields")
+
+		for i, complexField := range schema.ComplexType.Fields {
+			if fieldNames[complexField.Name] {
+				allErrs = append(allErrs, field.Duplicate(fieldsPath.Index(i).Child("name"), complexField.Name))
+			}
+			fieldNames[complexField.Name] = true
+			allErrs = append(allErrs, validateSchema(complexField.Schema, fieldsPath.Index(i).Child("schema"))...)
+		}
+	}
+
+	if schema.ArrayType != nil {
+		allErrs = append(allErrs, validateSchema(schema.ArrayType.ElementSchema, fldPath.Child("arrayType", "elementSchema"))...)
+	}
+
+	if schema.MapType != nil {
+		allErrs = append(allErrs, validateSchema(schema.MapType.MapValue.Schema, fldPath.Child("mapType", "mapValue", "schema"))...)
+	}
+
+	return allErrs
+}
+
+// Maximum number of TwinInterfaces extended by a TwinInterface, as defined by DTDL
+const MAX_EXTENDS = 2
+
+var (
+	supportedPrimitiveTypes = []string{
+		string(dtdv0.Boolean), string(dtdv0.Date), string(dtdv0.DateTime), string(dtdv0.Double), string(dtdv0.Duration),
+		string(dtdv0.Float), string(dtdv0.Integer), string(dtdv0.Long), string(dtdv0.String), string(dtdv0.Time),
+		string(dtdv0.Point), string(dtdv0.MultiPoint), string(dtdv0.LineString), string(dtdv0.MultiLineString),
+		string(dtdv0.Polygon), string(dtdv0.MultiPolygon),
+	}
+	// DTDL enums only support integer and string values
+	supportedEnumValueSchemas = []string{string(dtdv0.Integer), string(dtdv0.String)}
+)
+
+func isSupportedValue(supportedValues []string, value string) bool {
+	for _, supportedValue := range supportedValues {
+		if supportedValue == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Names used as a segment of generated resource names must be a RFC 1123 label once lowercased
+func validateNameSegment(name string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if name == "" {
+		return append(allErrs, field.Required(fldPath, "name must be informed"))
+	}
+
+	for _, msg := range validation.IsDNS1123Label(strings.ToLower(name)) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+
+	return allErrs
+}
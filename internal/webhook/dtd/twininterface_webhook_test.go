@@ -0,0 +1,418 @@
+package dtd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/event"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	dtdv0.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func newTwinInterface(name string, spec dtdv0.TwinInterfaceSpec) *dtdv0.TwinInterface {
+	return &dtdv0.TwinInterface{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+}
+
+func getCauseFields(err error) []string {
+	var fields []string
+	if statusErr, ok := err.(*apierrors.StatusError); ok && statusErr.ErrStatus.Details != nil {
+		for _, cause := range statusErr.ErrStatus.Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+	}
+	return fields
+}
+
+func TestTwinInterfaceValidator_ValidateCreate(t *testing.T) {
+
+	existingTwinInterfaces := []client.Object{
+		newTwinInterface("city", dtdv0.TwinInterfaceSpec{}),
+		newTwinInterface("parent", dtdv0.TwinInterfaceSpec{ExtendsInterface: "child"}),
+		newTwinInterface("sensor", dtdv0.TwinInterfaceSpec{}),
+		newTwinInterface("streetlight", dtdv0.TwinInterfaceSpec{
+			ExtendsInterface: "city",
+			Properties:       []dtdv0.TwinProperty{{Name: "status", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.String}}},
+		}),
+		newTwinInterface("device", dtdv0.TwinInterfaceSpec{
+			Extends:    []string{"city"},
+			Properties: []dtdv0.TwinProperty{{Name: "status", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.String}}},
+		}),
+		newTwinInterface("station", dtdv0.TwinInterfaceSpec{
+			Components: []dtdv0.TwinComponent{{Name: "sensor", Interface: "sensor"}},
+		}),
+		newTwinInterface("dtmi-city-pole-1", dtdv0.TwinInterfaceSpec{ModelId: "dtmi:city:Pole", Version: 1}),
+	}
+
+	tests := []struct {
+		name                      string
+		twinInterface             *dtdv0.TwinInterface
+		allowPendingRelationships bool
+		expectedFields            []string
+		expectedWarnings          []string
+	}{
+		{
+			name: "Valid TwinInterface",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				ExtendsInterface: "city",
+				Relationships: []dtdv0.TwinRelationship{
+					{Name: "refCity", Interface: "city", MinMultiplicity: 1, MaxMultiplicity: 1},
+					{Name: "refNeighborhood", Interface: "neighborhood"},
+				},
+			}),
+		},
+		{
+			name: "Relationship target not found",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Relationships: []dtdv0.TwinRelationship{
+					{Name: "refPole", Interface: "pole"},
+				},
+			}),
+			expectedFields: []string{"spec.relationships[0].interface"},
+		},
+		{
+			name: "Relationship target not created yet with pending relationships allowed",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Relationships: []dtdv0.TwinRelationship{
+					{Name: "refPole", Interface: "pole"},
+				},
+			}),
+			allowPendingRelationships: true,
+			expectedWarnings:          []string{"spec.relationships[0].interface: TwinInterface pole not found, the relationship is pending until it is created"},
+		},
+		{
+			name: "Relationship target referencing the TwinInterface back",
+			twinInterface: newTwinInterface("pole", dtdv0.TwinInterfaceSpec{
+				Relationships: []dtdv0.TwinRelationship{
+					{Name: "refStreetlight", Interface: "streetlight"},
+				},
+			}),
+		},
+		{
+			name: "MinMultiplicity greater than MaxMultiplicity",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Relationships: []dtdv0.TwinRelationship{
+					{Name: "refCity", Interface: "city", MinMultiplicity: 2, MaxMultiplicity: 1},
+				},
+			}),
+			expectedFields: []string{"spec.relationships[0].minMultiplicity"},
+		},
+		{
+			name: "ExtendsInterface cycle",
+			twinInterface: newTwinInterface("child", dtdv0.TwinInterfaceSpec{
+				ExtendsInterface: "parent",
+			}),
+			expectedFields: []string{"spec.extendsInterface"},
+		},
+		{
+			name: "ExtendsInterface not found",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				ExtendsInterface: "pole",
+			}),
+			expectedFields: []string{"spec.extendsInterface"},
+		},
+		{
+			name: "Multiple inheritance with a shared parent",
+			twinInterface: newTwinInterface("smartstreetlight", dtdv0.TwinInterfaceSpec{
+				Extends:    []string{"streetlight", "sensor"},
+				Properties: []dtdv0.TwinProperty{{Name: "status", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.String}}},
+			}),
+		},
+		{
+			name: "Invalid extends",
+			twinInterface: newTwinInterface("smartstreetlight", dtdv0.TwinInterfaceSpec{
+				ExtendsInterface: "streetlight",
+				Extends:          []string{"streetlight", "sensor", "pole"},
+			}),
+			expectedFields: []string{"spec.extends[0]", "spec.extends", "spec.extends[2]"},
+		},
+		{
+			name: "Contents inherited from different parents",
+			twinInterface: newTwinInterface("smartstreetlight", dtdv0.TwinInterfaceSpec{
+				Extends: []string{"streetlight", "device"},
+			}),
+			expectedFields: []string{"spec.extends"},
+		},
+		{
+			name: "Extends cycle through the second parent",
+			twinInterface: newTwinInterface("child", dtdv0.TwinInterfaceSpec{
+				Extends: []string{"city", "parent"},
+			}),
+			expectedFields: []string{"spec.extends[1]"},
+		},
+		{
+			name: "Duplicate enum values",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Properties: []dtdv0.TwinProperty{
+					{
+						Name: "status",
+						Schema: &dtdv0.TwinSchema{
+							EnumType: &dtdv0.TwinEnumSchema{
+								ValueSchema: dtdv0.String,
+								EnumValues: []dtdv0.TwinEnumSchemaValues{
+									{Name: "on", EnumValue: "on"},
+									{Name: "off", EnumValue: "off"},
+									{Name: "enabled", EnumValue: "on"},
+								},
+							},
+						},
+					},
+				},
+			}),
+			expectedFields: []string{"spec.properties[0].schema.enumType.enumValues[2].enumValue"},
+		},
+		{
+			name: "Unsupported primitive types",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Properties: []dtdv0.TwinProperty{
+					{
+						Name: "location",
+						Schema: &dtdv0.TwinSchema{
+							ComplexType: &dtdv0.TwinComplexType{
+								Type: dtdv0.Object,
+								Fields: []dtdv0.TwinComplexTypeFields{
+									{Name: "point", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Point}},
+									{Name: "altitude", Schema: &dtdv0.TwinSchema{PrimitiveType: "decimal"}},
+								},
+							},
+						},
+					},
+					{
+						Name: "level",
+						Schema: &dtdv0.TwinSchema{
+							EnumType: &dtdv0.TwinEnumSchema{
+								ValueSchema: dtdv0.Double,
+								EnumValues:  []dtdv0.TwinEnumSchemaValues{{Name: "low", EnumValue: "0.5"}},
+							},
+						},
+					},
+				},
+			}),
+			expectedFields: []string{
+				"spec.properties[0].schema.complexType.fields[1].schema.primitiveType",
+				"spec.properties[1].schema.enumType.valueSchema",
+			},
+		},
+		{
+			name: "Nested schemas with duplicate field names and enum values",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Telemetries: []dtdv0.TwinTelemetry{
+					{
+						Name: "sensors",
+						Schema: &dtdv0.TwinSchema{
+							MapType: &dtdv0.TwinMapSchema{
+								MapKey: dtdv0.TwinMapKey{Name: "sensorId"},
+								MapValue: dtdv0.TwinMapValue{
+									Name: "readings",
+									Schema: &dtdv0.TwinSchema{
+										ArrayType: &dtdv0.TwinArraySchema{
+											ElementSchema: &dtdv0.TwinSchema{
+												ComplexType: &dtdv0.TwinComplexType{
+													Type: dtdv0.Object,
+													Fields: []dtdv0.TwinComplexTypeFields{
+														{Name: "value", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}},
+														{Name: "value", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Integer}},
+														{
+															Name: "unit",
+															Schema: &dtdv0.TwinSchema{
+																EnumType: &dtdv0.TwinEnumSchema{
+																	ValueSchema: dtdv0.String,
+																	EnumValues: []dtdv0.TwinEnumSchemaValues{
+																		{Name: "celsius", EnumValue: "C"},
+																		{Name: "centigrade", EnumValue: "C"},
+																	},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}),
+			expectedFields: []string{
+				"spec.telemetries[0].schema.mapType.mapValue.schema.arrayType.elementSchema.complexType.fields[1].name",
+				"spec.telemetries[0].schema.mapType.mapValue.schema.arrayType.elementSchema.complexType.fields[2].schema.enumType.enumValues[1].enumValue",
+			},
+		},
+		{
+			name: "Valid components",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Components: []dtdv0.TwinComponent{
+					{Name: "airQualitySensor", Interface: "sensor"},
+					{Name: "noiseSensor", Interface: "sensor"},
+				},
+			}),
+		},
+		{
+			name: "Invalid components",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Components: []dtdv0.TwinComponent{
+					{Name: "sensor", Interface: "sensor"},
+					{Name: "sensor", Interface: "pole"},
+					{Name: "self", Interface: "neighborhood"},
+					{Name: "station", Interface: "station"},
+					{Name: "empty"},
+				},
+			}),
+			expectedFields: []string{
+				"spec.components[1].name",
+				"spec.components[1].interface",
+				"spec.components[2].interface",
+				"spec.components[3].interface",
+				"spec.components[4].interface",
+			},
+		},
+		{
+			name: "Units with and without semantic types",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Properties: []dtdv0.TwinProperty{
+					{Name: "area", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}, SemanticTypes: []string{"Area"}, Unit: "squareMetre"},
+					{Name: "height", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}, Unit: "metre"},
+				},
+				Telemetries: []dtdv0.TwinTelemetry{
+					{Name: "temperature", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}, SemanticTypes: []string{"Temperature"}, Unit: "degreeCelsius"},
+					{Name: "humidity", Schema: &dtdv0.TwinSchema{PrimitiveType: dtdv0.Double}, Unit: "percent"},
+				},
+			}),
+			expectedFields: []string{"spec.properties[1].unit", "spec.telemetries[1].unit"},
+		},
+		{
+			name: "Relationship name not valid in binding names",
+			twinInterface: newTwinInterface("neighborhood", dtdv0.TwinInterfaceSpec{
+				Relationships: []dtdv0.TwinRelationship{
+					{Name: "ref_city", Interface: "city", AggregateData: true},
+				},
+			}),
+			expectedFields: []string{"spec.relationships[0].name"},
+		},
+		{
+			name:          "New version of a model",
+			twinInterface: newTwinInterface("dtmi-city-pole-2", dtdv0.TwinInterfaceSpec{ModelId: "dtmi:city:Pole", Version: 2}),
+		},
+		{
+			name:           "Version of a model already declared",
+			twinInterface:  newTwinInterface("city-pole", dtdv0.TwinInterfaceSpec{ModelId: "dtmi:city:Pole", Version: 1}),
+			expectedFields: []string{"spec.version"},
+		},
+		{
+			name:           "Model without version",
+			twinInterface:  newTwinInterface("city-pole", dtdv0.TwinInterfaceSpec{ModelId: "dtmi:city:Pole"}),
+			expectedFields: []string{"spec.version"},
+		},
+		{
+			name:           "Version without model",
+			twinInterface:  newTwinInterface("city-pole", dtdv0.TwinInterfaceSpec{Version: 2}),
+			expectedFields: []string{"spec.modelId"},
+		},
+		{
+			name:           "Generated binding name too long",
+			twinInterface:  newTwinInterface(strings.Repeat("a", 240), dtdv0.TwinInterfaceSpec{}),
+			expectedFields: []string{"metadata.name", "metadata.name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &TwinInterfaceValidator{
+				Client:                    newFakeClient(existingTwinInterfaces...),
+				TwinEvent:                 event.NewTwinEvent(),
+				AllowPendingRelationships: tt.allowPendingRelationships,
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), tt.twinInterface)
+			assert.Equal(t, admission.Warnings(tt.expectedWarnings), warnings)
+
+			if len(tt.expectedFields) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Equal(t, tt.expectedFields, getCauseFields(err))
+		})
+	}
+}
+
+func TestTwinInterfaceValidator_ValidateUpdate(t *testing.T) {
+	// The relationship target and the parent of the TwinInterface were deleted
+	oldTwinInterface := newTwinInterface("streetlight", dtdv0.TwinInterfaceSpec{
+		ExtendsInterface: "pole",
+		Relationships:    []dtdv0.TwinRelationship{{Name: "refNeighborhood", Interface: "neighborhood"}},
+	})
+
+	finalizerTwinInterface := oldTwinInterface.DeepCopy()
+	finalizerTwinInterface.Finalizers = []string{"ktwin.dev/cleanup"}
+	finalizerTwinInterface.Labels = map[string]string{"ktwin/twin-interface": "streetlight"}
+
+	deletedTwinInterface := oldTwinInterface.DeepCopy()
+	deletionTimestamp := metav1.Now()
+	deletedTwinInterface.DeletionTimestamp = &deletionTimestamp
+	deletedTwinInterface.Spec.Relationships = nil
+
+	updatedTwinInterface := oldTwinInterface.DeepCopy()
+	updatedTwinInterface.Spec.Relationships = nil
+
+	tests := []struct {
+		name           string
+		twinInterface  *dtdv0.TwinInterface
+		expectedFields []string
+	}{
+		{
+			name:          "Metadata update with an unchanged spec",
+			twinInterface: finalizerTwinInterface,
+		},
+		{
+			name:          "Update of a TwinInterface being deleted",
+			twinInterface: deletedTwinInterface,
+		},
+		{
+			name:           "Spec update",
+			twinInterface:  updatedTwinInterface,
+			expectedFields: []string{"spec.extendsInterface"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &TwinInterfaceValidator{
+				Client:    newFakeClient(oldTwinInterface),
+				TwinEvent: event.NewTwinEvent(),
+			}
+
+			_, err := validator.ValidateUpdate(context.TODO(), oldTwinInterface, tt.twinInterface)
+
+			if len(tt.expectedFields) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Equal(t, tt.expectedFields, getCauseFields(err))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package apply
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	FIELD_MANAGER string = "ktwin-operator"
+)
+
+// Apply the desired state of a resource with server-side apply using the ktwin-operator field manager.
+// Only the fields set in the desired resource are owned by the operator, fields owned by other managers are kept.
+// The resource must have its TypeMeta set and it is not modified. The current resource is read before the patch,
+// an extra request to the API server only needed to compute the OperationResult, so callers that ignore the
+// result use Patch instead.
+func Apply(ctx context.Context, c client.Client, resource client.Object) (controllerutil.OperationResult, error) {
+	currentResource := resource.DeepCopyObject().(client.Object)
+	err := c.Get(ctx, client.ObjectKeyFromObject(resource), currentResource)
+
+	if err != nil && !errors.IsNotFound(err) {
+		return controllerutil.OperationResultNone, err
+	}
+
+	exists := err == nil
+
+	appliedResource, err := patch(ctx, c, resource)
+
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	if !exists {
+		return controllerutil.OperationResultCreated, nil
+	}
+
+	if currentResource.GetResourceVersion() != appliedResource.GetResourceVersion() {
+		return controllerutil.OperationResultUpdated, nil
+	}
+
+	return controllerutil.OperationResultNone, nil
+}
+
+// Patch the desired state of a resource with server-side apply, as Apply, without reading the current resource
+func Patch(ctx context.Context, c client.Client, resource client.Object) error {
+	_, err := patch(ctx, c, resource)
+	return err
+}
+
+// Patch a copy of the resource, which is returned with the state returned by the API server
+func patch(ctx context.Context, c client.Client, resource client.Object) (client.Object, error) {
+	appliedResource := resource.DeepCopyObject().(client.Object)
+	appliedResource.SetManagedFields(nil)
+	appliedResource.SetResourceVersion("")
+
+	err := c.Patch(ctx, appliedResource, client.Apply, client.FieldOwner(FIELD_MANAGER), client.ForceOwnership)
+	return appliedResource, err
+}
@@ -0,0 +1,122 @@
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// The fake client does not create objects with apply patches and always increments the resource version of
+// patched objects. As the API server, missing objects are created and patches that change nothing are ignored.
+// All the requests are counted.
+func newFakeApplyClient(requests *int, objects ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*requests++
+			return c.Get(ctx, key, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			*requests++
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+
+			currentConfigMap := &corev1.ConfigMap{}
+			err := c.Get(ctx, client.ObjectKeyFromObject(obj), currentConfigMap)
+			if apierrors.IsNotFound(err) {
+				return c.Create(ctx, obj)
+			}
+			if err != nil {
+				return err
+			}
+
+			if equality.Semantic.DeepEqual(currentConfigMap.Data, obj.(*corev1.ConfigMap).Data) {
+				currentConfigMap.DeepCopyInto(obj.(*corev1.ConfigMap))
+				return nil
+			}
+
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+}
+
+func newConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "city", Namespace: "ktwin"},
+		Data:       data,
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       []client.Object
+		desired        *corev1.ConfigMap
+		expectedResult controllerutil.OperationResult
+	}{
+		{
+			name:           "Missing resource is created",
+			desired:        newConfigMap(map[string]string{"color": "blue"}),
+			expectedResult: controllerutil.OperationResultCreated,
+		},
+		{
+			name:           "Changed resource is updated",
+			existing:       []client.Object{newConfigMap(map[string]string{"color": "red"})},
+			desired:        newConfigMap(map[string]string{"color": "blue"}),
+			expectedResult: controllerutil.OperationResultUpdated,
+		},
+		{
+			name:           "Unchanged resource is not updated",
+			existing:       []client.Object{newConfigMap(map[string]string{"color": "blue"})},
+			desired:        newConfigMap(map[string]string{"color": "blue"}),
+			expectedResult: controllerutil.OperationResultNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			fakeClient := newFakeApplyClient(&requests, tt.existing...)
+			desired := tt.desired.DeepCopy()
+
+			result, err := Apply(context.Background(), fakeClient, desired)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, tt.desired, desired)
+			assert.Equal(t, 2, requests)
+
+			configMap := &corev1.ConfigMap{}
+			assert.Nil(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(desired), configMap))
+			assert.Equal(t, tt.desired.Data, configMap.Data)
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	var requests int
+	fakeClient := newFakeApplyClient(&requests, newConfigMap(map[string]string{"color": "red"}))
+	desired := newConfigMap(map[string]string{"color": "blue"})
+
+	err := Patch(context.Background(), fakeClient, desired)
+
+	assert.Nil(t, err)
+	assert.Equal(t, newConfigMap(map[string]string{"color": "blue"}), desired)
+	assert.Equal(t, 1, requests)
+
+	configMap := &corev1.ConfigMap{}
+	assert.Nil(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(desired), configMap))
+	assert.Equal(t, desired.Data, configMap.Data)
+}
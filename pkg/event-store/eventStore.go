@@ -32,14 +32,24 @@ func NewEventStore() EventStore {
 // TODO: Implement creation of TwinInstance and TwinInterface in event store tables
 type EventStore interface {
 	GetEventStoreService(eventStore *corev0.EventStore) *kserving.Service
-	MergeEventStoreService(currentService *kserving.Service, newService *kserving.Service) *kserving.Service
 	GetEventStoreTrigger(eventStore *corev0.EventStore) *kEventing.Trigger
-	MergeEventStoreTrigger(currentTrigger *kEventing.Trigger, newTrigger *kEventing.Trigger) *kEventing.Trigger
 	GetEventStoreBrokerBindings(twinInterface *dtdv0.TwinInterface, brokerExchange rabbitmqv1beta1.Exchange, eventStoreQueue rabbitmqv1beta1.Queue) []rabbitmqv1beta1.Binding
 }
 
 type eventStore struct{}
 
+func (t *eventStore) getEventStoreOwnerReference(eventStore *corev0.EventStore) []v1.OwnerReference {
+	return []v1.OwnerReference{
+		*v1.NewControllerRef(eventStore, corev0.GroupVersion.WithKind("EventStore")),
+	}
+}
+
+func (t *eventStore) getTwinInterfaceOwnerReference(twinInterface *dtdv0.TwinInterface) []v1.OwnerReference {
+	return []v1.OwnerReference{
+		*v1.NewControllerRef(twinInterface, dtdv0.GroupVersion.WithKind("TwinInterface")),
+	}
+}
+
 func (t *eventStore) GetEventStoreService(eventStore *corev0.EventStore) *kserving.Service {
 	eventStoreName := eventStore.ObjectMeta.Name
 	timeoutValue := fmt.Sprintf("%d", *eventStore.Spec.Timeout)
@@ -80,14 +90,7 @@ func (t *eventStore) GetEventStoreService(eventStore *corev0.EventStore) *kservi
 			Labels: map[string]string{
 				"ktwin/event-store": eventStoreName,
 			},
-			OwnerReferences: []v1.OwnerReference{
-				{
-					APIVersion: eventStore.APIVersion,
-					Kind:       eventStore.Kind,
-					Name:       eventStore.ObjectMeta.Name,
-					UID:        eventStore.UID,
-				},
-			},
+			OwnerReferences: t.getEventStoreOwnerReference(eventStore),
 		},
 		Spec: kserving.ServiceSpec{
 			ConfigurationSpec: kserving.ConfigurationSpec{
@@ -133,14 +136,6 @@ func (t *eventStore) GetEventStoreService(eventStore *corev0.EventStore) *kservi
 	return service
 }
 
-// TODO: Merge Annotations
-// Merge Resources
-// Merge Containers
-func (t *eventStore) MergeEventStoreService(currentService *kserving.Service, newService *kserving.Service) *kserving.Service {
-	currentService.Spec.ConfigurationSpec = newService.Spec.ConfigurationSpec
-	return currentService
-}
-
 func (t *eventStore) GetEventStoreTrigger(eventStore *corev0.EventStore) *kEventing.Trigger {
 	var cpuRequest string
 	var memoryRequest string
@@ -164,18 +159,11 @@ func (t *eventStore) GetEventStoreTrigger(eventStore *corev0.EventStore) *kEvent
 	}
 
 	return knative.NewTrigger(knative.TriggerParameters{
-		TriggerName:    eventStore.Name + "-trigger",
-		Namespace:      eventStore.Namespace,
-		BrokerName:     "ktwin",
-		SubscriberName: "event-store",
-		OwnerReferences: []v1.OwnerReference{
-			{
-				APIVersion: eventStore.APIVersion,
-				Kind:       eventStore.Kind,
-				Name:       eventStore.Name,
-				UID:        eventStore.UID,
-			},
-		},
+		TriggerName:     eventStore.Name + "-trigger",
+		Namespace:       eventStore.Namespace,
+		BrokerName:      "ktwin",
+		SubscriberName:  "event-store",
+		OwnerReferences: t.getEventStoreOwnerReference(eventStore),
 		Attributes: map[string]string{
 			"type": "ktwin.event-store",
 		},
@@ -193,26 +181,14 @@ func (t *eventStore) GetEventStoreTrigger(eventStore *corev0.EventStore) *kEvent
 	})
 }
 
-func (t *eventStore) MergeEventStoreTrigger(currentTrigger *kEventing.Trigger, newTrigger *kEventing.Trigger) *kEventing.Trigger {
-	currentTrigger.ObjectMeta.Annotations = newTrigger.ObjectMeta.Annotations
-	return currentTrigger
-}
-
 func (t *eventStore) GetEventStoreBrokerBindings(twinInterface *dtdv0.TwinInterface, brokerExchange rabbitmqv1beta1.Exchange, eventStoreQueue rabbitmqv1beta1.Queue) []rabbitmqv1beta1.Binding {
 	var eventStoreBindings []rabbitmqv1beta1.Binding
 
-	if twinInterface.Spec.EventStore.PersistRealEvent {
+	if twinInterface.GetEffectiveSpec().EventStore.PersistRealEvent {
 		realEventBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
 			Name:      strings.ToLower(twinInterface.Name) + "-real-event-store",
 			Namespace: twinInterface.Namespace,
-			Owner: []v1.OwnerReference{
-				{
-					APIVersion: twinInterface.APIVersion,
-					Kind:       twinInterface.Kind,
-					Name:       twinInterface.Name,
-					UID:        twinInterface.UID,
-				},
-			},
+			Owner:     t.getTwinInterfaceOwnerReference(twinInterface),
 			RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 				Name:      "rabbitmq",
 				Namespace: "ktwin",
@@ -225,23 +201,18 @@ func (t *eventStore) GetEventStoreBrokerBindings(twinInterface *dtdv0.TwinInterf
 				"x-knative-trigger": "event-store-trigger",
 				"x-match":           "all",
 			},
-			Labels: map[string]string{},
+			Labels: map[string]string{
+				"ktwin/twin-interface": twinInterface.Name,
+			},
 		})
 		eventStoreBindings = append(eventStoreBindings, realEventBinding)
 	}
 
-	if twinInterface.Spec.EventStore.PersistVirtualEvent {
+	if twinInterface.GetEffectiveSpec().EventStore.PersistVirtualEvent {
 		virtualEventBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
 			Name:      strings.ToLower(twinInterface.Name) + "-virtual-event-store",
 			Namespace: twinInterface.Namespace,
-			Owner: []v1.OwnerReference{
-				{
-					APIVersion: twinInterface.APIVersion,
-					Kind:       twinInterface.Kind,
-					Name:       twinInterface.Name,
-					UID:        twinInterface.UID,
-				},
-			},
+			Owner:     t.getTwinInterfaceOwnerReference(twinInterface),
 			RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 				Name:      "rabbitmq",
 				Namespace: "ktwin",
@@ -254,7 +225,9 @@ func (t *eventStore) GetEventStoreBrokerBindings(twinInterface *dtdv0.TwinInterf
 				"x-knative-trigger": "event-store-trigger",
 				"x-match":           "all",
 			},
-			Labels: map[string]string{},
+			Labels: map[string]string{
+				"ktwin/twin-interface": twinInterface.Name,
+			},
 		})
 		eventStoreBindings = append(eventStoreBindings, virtualEventBinding)
 	}
@@ -262,14 +235,7 @@ func (t *eventStore) GetEventStoreBrokerBindings(twinInterface *dtdv0.TwinInterf
 	eventStoreEventingBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
 		Name:      strings.ToLower(twinInterface.Name) + "-event-store",
 		Namespace: twinInterface.Namespace,
-		Owner: []v1.OwnerReference{
-			{
-				APIVersion: twinInterface.APIVersion,
-				Kind:       twinInterface.Kind,
-				Name:       twinInterface.Name,
-				UID:        twinInterface.UID,
-			},
-		},
+		Owner:     t.getTwinInterfaceOwnerReference(twinInterface),
 		RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 			Name:      "rabbitmq",
 			Namespace: "ktwin",
@@ -282,7 +248,9 @@ func (t *eventStore) GetEventStoreBrokerBindings(twinInterface *dtdv0.TwinInterf
 			"x-knative-trigger": "event-store-trigger",
 			"x-match":           "all",
 		},
-		Labels: map[string]string{},
+		Labels: map[string]string{
+			"ktwin/twin-interface": twinInterface.Name,
+		},
 	})
 	eventStoreBindings = append(eventStoreBindings, eventStoreEventingBinding)
 
@@ -27,4 +27,5 @@ const (
 	RABBITMQ_VHOST                  string = "/"
 	CLOUD_EVENT_DISPATCHER_EXCHANGE string = "amq.topic"
 	MQTT_EXCHANGE                   string = "amq.topic"
+	RABBITMQ_MQTT_PORT              string = "1883"
 )
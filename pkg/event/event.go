@@ -6,9 +6,11 @@ import (
 	"strings"
 
 	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/third-party/knative"
 	"github.com/Open-Digital-Twin/ktwin-operator/pkg/third-party/rabbitmq"
 
 	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kEventing "knative.dev/eventing/pkg/apis/eventing/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -20,10 +22,15 @@ func NewTwinEvent() TwinEvent {
 
 type TwinEvent interface {
 	GetTwinInterfaceTrigger(twinInterface *dtdv0.TwinInterface) *kEventing.Trigger
+	GetTwinInterfaceTriggerNames(twinInterface *dtdv0.TwinInterface, twinInstanceNames []string) []string
 	GetTwinInterfaceCommandBindings(twinInterface *dtdv0.TwinInterface, brokerExchange rabbitmqv1beta1.Exchange, twinInterfaceQueue rabbitmqv1beta1.Queue) []rabbitmqv1beta1.Binding
 	GetVirtualCloudEventBrokerBinding(twinInterface *dtdv0.TwinInterface, brokerExchange rabbitmqv1beta1.Exchange) []rabbitmqv1beta1.Binding
 	GetRelationshipBrokerBindings(twinInterface *dtdv0.TwinInterface, brokerExchange rabbitmqv1beta1.Exchange, twinInterfaceQueue rabbitmqv1beta1.Queue) []rabbitmqv1beta1.Binding
 	GetMQQTDispatcherBindings(twinInterface *dtdv0.TwinInterface) []rabbitmqv1beta1.Binding
+	GetTwinInstanceTrigger(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) *kEventing.Trigger
+	GetTwinInstanceMQQTDispatcherBindings(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) []rabbitmqv1beta1.Binding
+	GetTwinInstanceVirtualCloudEventBrokerBinding(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface, brokerExchange rabbitmqv1beta1.Exchange) []rabbitmqv1beta1.Binding
+	GetTwinInstanceEndpointSettings(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface, broker kEventing.Broker, rabbitMQSecret corev1.Secret) *dtdv0.TwinInstanceEndpointSettings
 }
 
 type twinEvent struct{}
@@ -59,6 +66,10 @@ func (e *twinEvent) getTwinInterfaceTrigger(twinInterfaceName string) string {
 	return twinInterfaceName
 }
 
+func (e *twinEvent) getTwinInstanceTriggerName(twinInstanceName string) string {
+	return twinInstanceName
+}
+
 func (e *twinEvent) getRealToEventStoreTriggerName(twinInterfaceName string) string {
 	return twinInterfaceName + "-real-to-event-store"
 }
@@ -73,6 +84,33 @@ func (e *twinEvent) getTriggerLabels(twinInterfaceName string) map[string]string
 	}
 }
 
+// Routing key of the events addressed to a single TwinInstance: <twin interface>.<twin instance>, where the
+// TwinInterface is the version resolved for the TwinInstance
+func (e *twinEvent) getTwinInstanceRoutingKey(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) string {
+	return twinInterface.Name + "." + twinInstance.Name
+}
+
+// The TwinInstance resources are labelled with the resolved TwinInterface, whose cleanup deletes them
+func (e *twinEvent) getTwinInstanceLabels(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) map[string]string {
+	return map[string]string{
+		"ktwin/twin-interface":         twinInterface.Name,
+		"ktwin/twin-instance":          twinInstance.Name,
+		"eventing.knative.dev/trigger": twinInstance.Name,
+	}
+}
+
+func (e *twinEvent) getTwinInterfaceOwnerReference(twinInterface *dtdv0.TwinInterface) []v1.OwnerReference {
+	return []v1.OwnerReference{
+		*v1.NewControllerRef(twinInterface, dtdv0.GroupVersion.WithKind("TwinInterface")),
+	}
+}
+
+func (e *twinEvent) getTwinInstanceOwnerReference(twinInstance *dtdv0.TwinInstance) []v1.OwnerReference {
+	return []v1.OwnerReference{
+		*v1.NewControllerRef(twinInstance, dtdv0.GroupVersion.WithKind("TwinInstance")),
+	}
+}
+
 func (e *twinEvent) GetMQQTDispatcherBindings(
 	twinInterface *dtdv0.TwinInterface,
 ) []rabbitmqv1beta1.Binding {
@@ -81,14 +119,7 @@ func (e *twinEvent) GetMQQTDispatcherBindings(
 	rabbitMQVirtualBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
 		Name:      strings.ToLower(twinInterface.Name) + "-real-mqtt-dispatcher",
 		Namespace: twinInterface.Namespace,
-		Owner: []v1.OwnerReference{
-			{
-				APIVersion: twinInterface.APIVersion,
-				Kind:       twinInterface.Kind,
-				Name:       twinInterface.Name,
-				UID:        twinInterface.UID,
-			},
-		},
+		Owner:     e.getTwinInterfaceOwnerReference(twinInterface),
 		RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 			Name:      "rabbitmq",
 			Namespace: "ktwin",
@@ -105,19 +136,12 @@ func (e *twinEvent) GetMQQTDispatcherBindings(
 
 	rabbitMQBindings = append(rabbitMQBindings, rabbitMQVirtualBinding)
 
-	for _, twinInterfaceRelationship := range twinInterface.Spec.Relationships {
+	for _, twinInterfaceRelationship := range twinInterface.GetEffectiveSpec().Relationships {
 		if twinInterfaceRelationship.AggregateData {
 			rabbitMQVirtualBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
 				Name:      strings.ToLower(twinInterface.Name) + "-" + strings.ToLower(twinInterfaceRelationship.Name) + "-real-mqtt-dispatcher",
 				Namespace: twinInterface.Namespace,
-				Owner: []v1.OwnerReference{
-					{
-						APIVersion: twinInterface.APIVersion,
-						Kind:       twinInterface.Kind,
-						Name:       twinInterface.Name,
-						UID:        twinInterface.UID,
-					},
-				},
+				Owner:     e.getTwinInterfaceOwnerReference(twinInterface),
 				RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 					Name:      "rabbitmq",
 					Namespace: "ktwin",
@@ -156,14 +180,7 @@ func (e *twinEvent) GetVirtualCloudEventBrokerBinding(
 			"x-match":           "all",
 		},
 		RabbitMQVhost: "/",
-		Owner: []v1.OwnerReference{
-			{
-				APIVersion: twinInterface.APIVersion,
-				Kind:       twinInterface.Kind,
-				Name:       twinInterface.Name,
-				UID:        twinInterface.UID,
-			},
-		},
+		Owner:         e.getTwinInterfaceOwnerReference(twinInterface),
 		RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 			Name:      "rabbitmq",
 			Namespace: "ktwin",
@@ -183,7 +200,7 @@ func (e *twinEvent) GetRelationshipBrokerBindings(
 	twinInterfaceQueue rabbitmqv1beta1.Queue,
 ) []rabbitmqv1beta1.Binding {
 	rabbitMQBindings := []rabbitmqv1beta1.Binding{}
-	for _, twinInterfaceRelationship := range twinInterface.Spec.Relationships {
+	for _, twinInterfaceRelationship := range twinInterface.GetEffectiveSpec().Relationships {
 		if twinInterfaceRelationship.AggregateData {
 			realEventBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
 				Name:      strings.ToLower(twinInterface.Name) + "-" + strings.ToLower(twinInterfaceRelationship.Name) + "-real-dispatcher",
@@ -198,14 +215,7 @@ func (e *twinEvent) GetRelationshipBrokerBindings(
 					"x-match":           "all",
 				},
 				RabbitMQVhost: "/",
-				Owner: []v1.OwnerReference{
-					{
-						APIVersion: twinInterface.APIVersion,
-						Kind:       twinInterface.Kind,
-						Name:       twinInterface.Name,
-						UID:        twinInterface.UID,
-					},
-				},
+				Owner:         e.getTwinInterfaceOwnerReference(twinInterface),
 				RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 					Name:      "rabbitmq",
 					Namespace: "ktwin",
@@ -227,14 +237,7 @@ func (e *twinEvent) GetRelationshipBrokerBindings(
 					"x-match":           "all",
 				},
 				RabbitMQVhost: "/",
-				Owner: []v1.OwnerReference{
-					{
-						APIVersion: twinInterface.APIVersion,
-						Kind:       twinInterface.Kind,
-						Name:       twinInterface.Name,
-						UID:        twinInterface.UID,
-					},
-				},
+				Owner:         e.getTwinInterfaceOwnerReference(twinInterface),
 				RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 					Name:      "rabbitmq",
 					Namespace: "ktwin",
@@ -262,26 +265,19 @@ func (e *twinEvent) GetTwinInterfaceTrigger(twinInterface *dtdv0.TwinInterface)
 		twinInterfaceEventType := e.getEventTypeRealGenerated(twinInterface.Name)
 		var triggerAnnotations = make(map[string]string)
 
-		if twinInterface.Spec.Service != nil && twinInterface.Spec.Service.AutoScaling.Parallelism != nil {
-			triggerAnnotations["rabbitmq.eventing.knative.dev/parallelism"] = strconv.Itoa(*twinInterface.Spec.Service.AutoScaling.Parallelism)
+		if twinInterface.GetEffectiveSpec().Service != nil && twinInterface.GetEffectiveSpec().Service.AutoScaling.Parallelism != nil {
+			triggerAnnotations["rabbitmq.eventing.knative.dev/parallelism"] = strconv.Itoa(*twinInterface.GetEffectiveSpec().Service.AutoScaling.Parallelism)
 		}
 
 		twinInterfaceTrigger = e.createTrigger(TriggerParameters{
-			TriggerName:   e.getTwinInterfaceTrigger(twinInterface.Name),
-			Namespace:     twinInterface.Namespace,
-			BrokerName:    EVENT_BROKER_NAME,
-			EventType:     twinInterfaceEventType,
-			Subscriber:    virtualTwinService,
-			InterfaceName: twinInterface.Name,
-			OwnerReference: []v1.OwnerReference{
-				{
-					APIVersion: twinInterface.APIVersion,
-					Kind:       twinInterface.Kind,
-					Name:       twinInterface.Name,
-					UID:        twinInterface.UID,
-				},
-			},
-			Annotations: triggerAnnotations,
+			TriggerName:    e.getTwinInterfaceTrigger(twinInterface.Name),
+			Namespace:      twinInterface.Namespace,
+			BrokerName:     EVENT_BROKER_NAME,
+			EventType:      twinInterfaceEventType,
+			Subscriber:     virtualTwinService,
+			InterfaceName:  twinInterface.Name,
+			OwnerReference: e.getTwinInterfaceOwnerReference(twinInterface),
+			Annotations:    triggerAnnotations,
 		})
 
 	}
@@ -297,7 +293,7 @@ func (e *twinEvent) GetTwinInterfaceCommandBindings(
 	var twinInterfaceCommandBindings []rabbitmqv1beta1.Binding
 	// If TwinInstance has container associated, create the binding commands
 	if e.hasContainerInTwinInterface(twinInterface) {
-		for _, command := range twinInterface.Spec.Commands {
+		for _, command := range twinInterface.GetEffectiveSpec().Commands {
 			commandEventBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
 				Name:      strings.ToLower(twinInterface.Name) + "-" + strings.ToLower(command.Name) + "-command-dispatcher",
 				Namespace: twinInterface.Namespace,
@@ -311,14 +307,7 @@ func (e *twinEvent) GetTwinInterfaceCommandBindings(
 					"x-match":           "all",
 				},
 				RabbitMQVhost: "/",
-				Owner: []v1.OwnerReference{
-					{
-						APIVersion: twinInterface.APIVersion,
-						Kind:       twinInterface.Kind,
-						Name:       twinInterface.Name,
-						UID:        twinInterface.UID,
-					},
-				},
+				Owner:         e.getTwinInterfaceOwnerReference(twinInterface),
 				RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
 					Name:      "rabbitmq",
 					Namespace: "ktwin",
@@ -334,6 +323,123 @@ func (e *twinEvent) GetTwinInterfaceCommandBindings(
 	return twinInterfaceCommandBindings
 }
 
+// Return the names of the Triggers created for the TwinInterface and the informed TwinInstances, whether they still exist
+// or not. The broker labels the Queues of the Triggers with their names.
+func (e *twinEvent) GetTwinInterfaceTriggerNames(twinInterface *dtdv0.TwinInterface, twinInstanceNames []string) []string {
+	triggerNames := []string{e.getTwinInterfaceTrigger(twinInterface.Name)}
+
+	for _, twinInstanceName := range twinInstanceNames {
+		triggerNames = append(triggerNames, e.getTwinInstanceTriggerName(twinInstanceName))
+	}
+
+	return triggerNames
+}
+
+func (e *twinEvent) GetTwinInstanceTrigger(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) *kEventing.Trigger {
+	// Instance events are processed by the TwinInterface service, if there is any
+	if !e.hasContainerInTwinInterface(twinInterface) {
+		return nil
+	}
+
+	return knative.NewTrigger(knative.TriggerParameters{
+		TriggerName:     e.getTwinInstanceTriggerName(twinInstance.Name),
+		Namespace:       twinInstance.Namespace,
+		BrokerName:      EVENT_BROKER_NAME,
+		SubscriberName:  twinInterface.Name,
+		OwnerReferences: e.getTwinInstanceOwnerReference(twinInstance),
+		Attributes: map[string]string{
+			"type": e.getEventTypeRealGenerated(e.getTwinInstanceRoutingKey(twinInstance, twinInterface)),
+		},
+		Labels: map[string]string{
+			"ktwin/twin-interface": twinInterface.Name,
+			"ktwin/twin-instance":  twinInstance.Name,
+		},
+		Parallelism: twinInterface.GetEffectiveSpec().Service.AutoScaling.Parallelism,
+	})
+}
+
+func (e *twinEvent) GetTwinInstanceMQQTDispatcherBindings(twinInstance *dtdv0.TwinInstance, twinInterface *dtdv0.TwinInterface) []rabbitmqv1beta1.Binding {
+	rabbitMQRealBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
+		Name:      strings.ToLower(twinInstance.Name) + "-real-mqtt-dispatcher",
+		Namespace: twinInstance.Namespace,
+		Owner:     e.getTwinInstanceOwnerReference(twinInstance),
+		RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
+			Name:      "rabbitmq",
+			Namespace: "ktwin",
+		},
+		RabbitMQVhost: RABBITMQ_VHOST,
+		Source:        MQTT_EXCHANGE,
+		Destination:   MQTT_DISPATCHER_QUEUE,
+		Labels:        e.getTwinInstanceLabels(twinInstance, twinInterface),
+		RoutingKey:    e.getEventTypeRealGenerated(e.getTwinInstanceRoutingKey(twinInstance, twinInterface)),
+	})
+
+	return []rabbitmqv1beta1.Binding{rabbitMQRealBinding}
+}
+
+func (e *twinEvent) GetTwinInstanceVirtualCloudEventBrokerBinding(
+	twinInstance *dtdv0.TwinInstance,
+	twinInterface *dtdv0.TwinInterface,
+	brokerExchange rabbitmqv1beta1.Exchange,
+) []rabbitmqv1beta1.Binding {
+	virtualEventBinding, _ := rabbitmq.NewBinding(rabbitmq.BindingArgs{
+		Name:      strings.ToLower(twinInstance.Name) + "-virtual-cloud-event-dispatcher",
+		Namespace: twinInstance.Namespace,
+		Labels:    e.getTwinInstanceLabels(twinInstance, twinInterface),
+		Filters: map[string]string{
+			"type":              e.getEventTypeVirtualGenerated(e.getTwinInstanceRoutingKey(twinInstance, twinInterface)),
+			"x-knative-trigger": twinInstance.Name,
+			"x-match":           "all",
+		},
+		RabbitMQVhost: RABBITMQ_VHOST,
+		Owner:         e.getTwinInstanceOwnerReference(twinInstance),
+		RabbitmqClusterReference: &rabbitmqv1beta1.RabbitmqClusterReference{
+			Name:      "rabbitmq",
+			Namespace: "ktwin",
+		},
+		Source:      brokerExchange.Spec.Name,     // broker exchange
+		Destination: CLOUD_EVENT_DISPATCHER_QUEUE, // trigger queue
+	})
+
+	return []rabbitmqv1beta1.Binding{virtualEventBinding}
+}
+
+// Endpoints used by the real twin to publish events and to receive events generated by its virtual twin.
+// MQTT topics use "/" as separator, which RabbitMQ maps to "." in the routing keys.
+func (e *twinEvent) GetTwinInstanceEndpointSettings(
+	twinInstance *dtdv0.TwinInstance,
+	twinInterface *dtdv0.TwinInterface,
+	broker kEventing.Broker,
+	rabbitMQSecret corev1.Secret,
+) *dtdv0.TwinInstanceEndpointSettings {
+	routingKey := e.getTwinInstanceRoutingKey(twinInstance, twinInterface)
+	publisherTopic := e.getEventTypeRealGenerated(routingKey)
+	subscriberTopic := e.getEventTypeVirtualGenerated(routingKey)
+	rabbitMQHost := string(rabbitMQSecret.Data["host"])
+
+	endpointSettings := &dtdv0.TwinInstanceEndpointSettings{
+		MqttEndpoint: &dtdv0.TwinInstanceMqttEndpointSettings{
+			Url:             fmt.Sprintf("mqtt://%s:%s", rabbitMQHost, RABBITMQ_MQTT_PORT),
+			PublisherTopic:  strings.ReplaceAll(publisherTopic, ".", "/"),
+			SubscriberTopic: strings.ReplaceAll(subscriberTopic, ".", "/"),
+		},
+		AmqpEndpoint: &dtdv0.TwinInstanceAmqpEndpointSettings{
+			Url:             fmt.Sprintf("amqp://%s:%s", rabbitMQHost, string(rabbitMQSecret.Data["port"])),
+			PublisherTopic:  publisherTopic,
+			SubscriberTopic: subscriberTopic,
+		},
+	}
+
+	// The Broker address is only set when the Broker is ready
+	if broker.Status.Address != nil && broker.Status.Address.URL != nil {
+		endpointSettings.HttpEndpoint = &dtdv0.TwinInstanceHttpEndpointSettings{
+			Url: broker.Status.Address.URL.String(),
+		}
+	}
+
+	return endpointSettings
+}
+
 func (e *twinEvent) createTrigger(triggerParameters TriggerParameters) *kEventing.Trigger {
 	return &kEventing.Trigger{
 		TypeMeta: v1.TypeMeta{
@@ -366,5 +472,5 @@ func (e *twinEvent) createTrigger(triggerParameters TriggerParameters) *kEventin
 }
 
 func (*twinEvent) hasContainerInTwinInterface(twinInterface *dtdv0.TwinInterface) bool {
-	return twinInterface.Spec.Service != nil
+	return twinInterface.GetEffectiveSpec().Service != nil
 }
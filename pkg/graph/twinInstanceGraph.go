@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 
 	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
 )
@@ -16,17 +17,22 @@ type TwinInstanceEnvironmentSettings struct {
 	Name          string                             `json:"name,omitempty"`
 	Interface     string                             `json:"interface,omitempty"`
 	Relationships []TwinInstanceRelationshipSettings `json:"relationships,omitempty"`
+	// Number of instances generated for the TwinInterface, one when not informed
+	NumberOfInstances int `json:"numberOfInstances,omitempty"`
 }
 
 type TwinInstanceRelationshipSettings struct {
 	Name      string `json:"name,omitempty"`
 	Interface string `json:"interface,omitempty"`
 	Instance  string `json:"instance,omitempty"`
+	// Number of target instances each generated instance relates to
+	NumberOfInstances int `json:"numberOfInstances,omitempty"`
 }
 
 type TwinInstanceGraph interface {
 	AddVertex(twinInstance dtdv0.TwinInstance) (*TwinInstanceGraphVertex, error)
 	GetVertex(twinInstanceId string) *dtdv0.TwinInstance
+	GetTwinInstances() []dtdv0.TwinInstance
 	RemoveVertex(twinInstance dtdv0.TwinInstance) error
 	AddEdge(sourceTwinInstance dtdv0.TwinInstance, targetTwinInstance dtdv0.TwinInstance) error
 	RemoveEdge(sourceTwinInstance dtdv0.TwinInstance, targetTwinInstance dtdv0.TwinInstance) error
@@ -75,6 +81,22 @@ func (g *twinInstanceGraph) GetVertex(twinInstanceId string) *dtdv0.TwinInstance
 	return &g.Vertexes[twinInstanceId].TwinInstance
 }
 
+// Return the TwinInstances of the graph sorted by name, including the ones only known as relationship targets
+func (g *twinInstanceGraph) GetTwinInstances() []dtdv0.TwinInstance {
+	var vertexNames []string
+	for vertexName := range g.Vertexes {
+		vertexNames = append(vertexNames, vertexName)
+	}
+	sort.Strings(vertexNames)
+
+	var twinInstances []dtdv0.TwinInstance
+	for _, vertexName := range vertexNames {
+		twinInstances = append(twinInstances, g.Vertexes[vertexName].TwinInstance)
+	}
+
+	return twinInstances
+}
+
 func (g *twinInstanceGraph) AddVertex(twinInstance dtdv0.TwinInstance) (*TwinInstanceGraphVertex, error) {
 	vertex := g.Vertexes[twinInstance.Name]
 	if vertex != nil {
@@ -190,11 +212,12 @@ func (g *twinInstanceGraph) MarshalJson() ([]byte, error) {
 
 	var twinInstanceSettingsList []TwinInstanceEnvironmentSettings
 
-	for _, vertex := range g.Vertexes {
+	// The instances are sorted by name to keep the output stable across calls
+	for _, twinInstance := range g.GetTwinInstances() {
 
 		var relationshipSettingList []TwinInstanceRelationshipSettings
 
-		for _, relationship := range vertex.TwinInstance.Spec.TwinInstanceRelationships {
+		for _, relationship := range twinInstance.Spec.TwinInstanceRelationships {
 			relationshipSettingList = append(relationshipSettingList, TwinInstanceRelationshipSettings{
 				Name:      relationship.Name,
 				Interface: relationship.Interface,
@@ -203,8 +226,8 @@ func (g *twinInstanceGraph) MarshalJson() ([]byte, error) {
 		}
 
 		twinInstanceSettings := TwinInstanceEnvironmentSettings{
-			Name:          vertex.TwinInstance.Name,
-			Interface:     vertex.TwinInstance.Spec.Interface,
+			Name:          twinInstance.Name,
+			Interface:     twinInstance.Spec.Interface,
 			Relationships: relationshipSettingList,
 		}
 
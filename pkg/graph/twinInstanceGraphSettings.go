@@ -0,0 +1,294 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	ErrUnknownTwinInterface     = errors.New("TwinInterface not found")
+	ErrAmbiguousTwinInterface   = errors.New("TwinInterface reference matches more than one TwinInterface")
+	ErrDuplicateTwinInterface   = errors.New("TwinInterface is listed more than once")
+	ErrUnknownRelationship      = errors.New("TwinInterface has no relationship")
+	ErrUnknownTwinInstance      = errors.New("TwinInstance not found")
+	ErrInvalidNumberOfInstances = errors.New("Number of instances must not be negative")
+	ErrRelationshipMultiplicity = errors.New("Number of related instances is out of the relationship multiplicity")
+	ErrNotEnoughTwinInstances   = errors.New("Number of related instances exceeds the instances of the target TwinInterface")
+)
+
+// Load the settings of an instance graph file, which inform the number of instances of each TwinInterface and
+// the number of target instances of their relationships
+func LoadTwinGraphEnvironmentSettings(content []byte) (TwinGraphEnvironmentSettings, error) {
+	var settings TwinGraphEnvironmentSettings
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&settings); err != nil {
+		return TwinGraphEnvironmentSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// Return the name of the n-th TwinInstance generated for a TwinInterface, starting from zero
+func GetTwinInstanceName(twinInterfaceId string, index int) string {
+	return fmt.Sprintf("%s-%03d", twinInterfaceId, index+1)
+}
+
+// Generate the TwinInstances of the TwinInterfaces of the graph. Each TwinInterface has the number of instances of
+// the settings, or one when not listed. Each instance relates to the informed number of instances of the relationship
+// target, or to the minimum multiplicity of the relationship when not informed, and the relationships of all the
+// instances are distributed across the target instances in round robin.
+// TwinInterfaces are referenced by resource name, DTMI or the trailing segments of their DTMI, such as city:Pole.
+func NewTwinInstanceGraphFromSettings(settings TwinGraphEnvironmentSettings, twinInterfaceGraph TwinInterfaceGraph) (TwinInstanceGraph, []error) {
+	var errs []error
+	twinInterfaces := twinInterfaceGraph.GetTwinInterfaces()
+	interfaceSettings := map[string]TwinInstanceEnvironmentSettings{}
+	interfacePointers := map[string]string{}
+	numberOfInstances := map[string]int{}
+
+	for _, twinInterface := range twinInterfaces {
+		numberOfInstances[twinInterface.Spec.Id] = 1
+	}
+
+	for index, twinInstanceSettings := range settings.TwinInstances {
+		pointer := fmt.Sprintf("/twinInstances/%d", index)
+		twinInterfaceId, err := ResolveTwinInterface(twinInterfaces, twinInstanceSettings.Interface)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/interface: %w", pointer, err))
+			continue
+		}
+
+		if previousPointer, ok := interfacePointers[twinInterfaceId]; ok {
+			errs = append(errs, fmt.Errorf("%s/interface: %w %q, also in %s", pointer, ErrDuplicateTwinInterface, twinInterfaceId, previousPointer))
+			continue
+		}
+
+		if twinInstanceSettings.NumberOfInstances < 0 {
+			errs = append(errs, fmt.Errorf("%s/numberOfInstances: %w", pointer, ErrInvalidNumberOfInstances))
+			continue
+		}
+
+		interfaceSettings[twinInterfaceId] = twinInstanceSettings
+		interfacePointers[twinInterfaceId] = pointer
+
+		if twinInstanceSettings.NumberOfInstances > 0 {
+			numberOfInstances[twinInterfaceId] = twinInstanceSettings.NumberOfInstances
+		}
+	}
+
+	instanceGraph := NewEmptyTwinInstanceGraph()
+
+	for _, twinInterface := range twinInterfaces {
+		twinInterfaceId := twinInterface.Spec.Id
+		relationships, relationshipErrs := getTwinInstanceRelationships(twinInterface, twinInterfaces, twinInterfaceGraph, interfaceSettings[twinInterfaceId], interfacePointers[twinInterfaceId], numberOfInstances)
+		errs = append(errs, relationshipErrs...)
+
+		for index := 0; index < numberOfInstances[twinInterfaceId]; index++ {
+			instanceGraph.AddVertex(dtdv0.TwinInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: GetTwinInstanceName(twinInterfaceId, index),
+				},
+				Spec: dtdv0.TwinInstanceSpec{
+					Interface:                 twinInterfaceId,
+					TwinInstanceRelationships: relationships[index],
+				},
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	for _, twinInstance := range instanceGraph.GetTwinInstances() {
+		for _, relationship := range twinInstance.Spec.TwinInstanceRelationships {
+			instanceGraph.AddEdge(twinInstance, *instanceGraph.GetVertex(relationship.Instance))
+		}
+	}
+
+	return instanceGraph, nil
+}
+
+// Return the relationships of each instance of the TwinInterface, indexed by the instance index
+func getTwinInstanceRelationships(twinInterface dtdv0.TwinInterface, twinInterfaces []dtdv0.TwinInterface, twinInterfaceGraph TwinInterfaceGraph, twinInstanceSettings TwinInstanceEnvironmentSettings, pointer string, numberOfInstances map[string]int) ([][]dtdv0.TwinInstanceRelationship, []error) {
+	var errs []error
+	twinInterfaceId := twinInterface.Spec.Id
+	instanceRelationships := make([][]dtdv0.TwinInstanceRelationship, numberOfInstances[twinInterfaceId])
+
+	chain := twinInterfaceGraph.GetExtendsChain(twinInterfaceId)
+	if chain == nil {
+		chain = []dtdv0.TwinInterface{twinInterface}
+	}
+	twinRelationships := inheritance.GetEffectiveSpec(chain).Relationships
+
+	relationshipSettings := map[string]TwinInstanceRelationshipSettings{}
+	relationshipPointers := map[string]string{}
+
+	for index, settings := range twinInstanceSettings.Relationships {
+		relationshipPointer := fmt.Sprintf("%s/relationships/%d", pointer, index)
+
+		if !hasRelationship(twinRelationships, settings.Name) {
+			errs = append(errs, fmt.Errorf("%s/name: %w %q", relationshipPointer, ErrUnknownRelationship, settings.Name))
+			continue
+		}
+
+		relationshipSettings[settings.Name] = settings
+		relationshipPointers[settings.Name] = relationshipPointer
+	}
+
+	for _, twinRelationship := range twinRelationships {
+		settings, hasSettings := relationshipSettings[twinRelationship.Name]
+		relationshipPointer := relationshipPointers[twinRelationship.Name]
+		targetInterfaceId := twinRelationship.Interface
+
+		if settings.Interface != "" {
+			var err error
+			if targetInterfaceId, err = ResolveTwinInterface(twinInterfaces, settings.Interface); err != nil {
+				errs = append(errs, fmt.Errorf("%s/interface: %w", relationshipPointer, err))
+				continue
+			}
+		}
+
+		// All the instances relate to the same target instance
+		if settings.Instance != "" {
+			targetInterfaceId, index, ok := findTwinInstance(twinInterfaces, numberOfInstances, settings.Instance)
+
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s/instance: %w %q", relationshipPointer, ErrUnknownTwinInstance, settings.Instance))
+				continue
+			}
+
+			for instanceIndex := range instanceRelationships {
+				instanceRelationships[instanceIndex] = append(instanceRelationships[instanceIndex], dtdv0.TwinInstanceRelationship{
+					Name:      twinRelationship.Name,
+					Interface: targetInterfaceId,
+					Instance:  GetTwinInstanceName(targetInterfaceId, index),
+				})
+			}
+			continue
+		}
+
+		numberOfTargetInstances := numberOfInstances[targetInterfaceId]
+
+		if numberOfTargetInstances == 0 {
+			// The target of relationships not listed in the settings may be out of the generated TwinInterfaces
+			if hasSettings {
+				errs = append(errs, fmt.Errorf("%s: %w %q", relationshipPointer, ErrUnknownTwinInterface, targetInterfaceId))
+			}
+			continue
+		}
+
+		numberOfRelatedInstances := settings.NumberOfInstances
+
+		if numberOfRelatedInstances == 0 {
+			numberOfRelatedInstances = twinRelationship.MinMultiplicity
+			if numberOfRelatedInstances < 1 {
+				numberOfRelatedInstances = 1
+			}
+			if numberOfRelatedInstances > numberOfTargetInstances {
+				numberOfRelatedInstances = numberOfTargetInstances
+			}
+		} else if numberOfRelatedInstances < 0 {
+			errs = append(errs, fmt.Errorf("%s/numberOfInstances: %w", relationshipPointer, ErrInvalidNumberOfInstances))
+			continue
+		} else if numberOfRelatedInstances < twinRelationship.MinMultiplicity || (twinRelationship.MaxMultiplicity > 0 && numberOfRelatedInstances > twinRelationship.MaxMultiplicity) {
+			errs = append(errs, fmt.Errorf("%s/numberOfInstances: %w [%d, %d]", relationshipPointer, ErrRelationshipMultiplicity, twinRelationship.MinMultiplicity, twinRelationship.MaxMultiplicity))
+			continue
+		} else if numberOfRelatedInstances > numberOfTargetInstances {
+			errs = append(errs, fmt.Errorf("%s/numberOfInstances: %w %q (%d)", relationshipPointer, ErrNotEnoughTwinInstances, targetInterfaceId, numberOfTargetInstances))
+			continue
+		}
+
+		// Continue from the target instance where the previous instance stopped, so the target instances are
+		// evenly referenced and each instance relates to distinct target instances
+		for instanceIndex := range instanceRelationships {
+			for relatedIndex := 0; relatedIndex < numberOfRelatedInstances; relatedIndex++ {
+				targetIndex := (instanceIndex*numberOfRelatedInstances + relatedIndex) % numberOfTargetInstances
+				instanceRelationships[instanceIndex] = append(instanceRelationships[instanceIndex], dtdv0.TwinInstanceRelationship{
+					Name:      twinRelationship.Name,
+					Interface: targetInterfaceId,
+					Instance:  GetTwinInstanceName(targetInterfaceId, targetIndex),
+				})
+			}
+		}
+	}
+
+	return instanceRelationships, errs
+}
+
+// Resolve a TwinInterface reference into the TwinInterface id. The reference is the TwinInterface id,
+// its DTMI or the trailing segments of its DTMI, with or without the version. References without version match the
+// latest version of the model.
+func ResolveTwinInterface(twinInterfaces []dtdv0.TwinInterface, reference string) (string, error) {
+	normalizedReference := normalizeTwinInterfaceReference(reference)
+	var matches []dtdv0.TwinInterface
+
+	for _, twinInterface := range twinInterfaces {
+		if twinInterface.Spec.Id == normalizedReference {
+			return twinInterface.Spec.Id, nil
+		}
+
+		modelId := normalizeTwinInterfaceReference(twinInterface.Spec.ModelId)
+		if modelId == "" {
+			modelId = twinInterface.Spec.Id
+		}
+
+		if modelId == normalizedReference || strings.HasSuffix(modelId, "-"+normalizedReference) {
+			matches = append(matches, twinInterface)
+		}
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w %q", ErrUnknownTwinInterface, reference)
+	}
+
+	latest := matches[0]
+	for _, match := range matches[1:] {
+		if match.Spec.ModelId == "" || match.Spec.ModelId != latest.Spec.ModelId {
+			return "", fmt.Errorf("%w %q: %s, %s", ErrAmbiguousTwinInterface, reference, latest.Spec.Id, match.Spec.Id)
+		}
+
+		if match.Spec.Version > latest.Spec.Version {
+			latest = match
+		}
+	}
+
+	return latest.Spec.Id, nil
+}
+
+// Find the TwinInterface and index of a generated TwinInstance by its name
+func findTwinInstance(twinInterfaces []dtdv0.TwinInterface, numberOfInstances map[string]int, twinInstanceName string) (string, int, bool) {
+	for _, twinInterface := range twinInterfaces {
+		for index := 0; index < numberOfInstances[twinInterface.Spec.Id]; index++ {
+			if GetTwinInstanceName(twinInterface.Spec.Id, index) == twinInstanceName {
+				return twinInterface.Spec.Id, index, true
+			}
+		}
+	}
+
+	return "", 0, false
+}
+
+// Normalize a TwinInterface reference the same way DTMIs are converted into resource names
+func normalizeTwinInterfaceReference(reference string) string {
+	return strings.NewReplacer(":", "-", ";", "-", "_", "-", ".", "-").Replace(strings.ToLower(reference))
+}
+
+func hasRelationship(relationships []dtdv0.TwinRelationship, name string) bool {
+	for _, relationship := range relationships {
+		if relationship.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,301 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSettingsTwinInterface(id string, modelId string, version int, relationships ...dtdv0.TwinRelationship) dtdv0.TwinInterface {
+	return dtdv0.TwinInterface{
+		Spec: dtdv0.TwinInterfaceSpec{
+			Id:            id,
+			ModelId:       modelId,
+			Version:       version,
+			Relationships: relationships,
+		},
+	}
+}
+
+func newSettingsTwinInterfaceGraph(twinInterfaces ...dtdv0.TwinInterface) TwinInterfaceGraph {
+	twinInterfaceGraph := NewTwinInterfaceGraph()
+	for _, twinInterface := range twinInterfaces {
+		twinInterfaceGraph.AddVertex(twinInterface)
+	}
+	return twinInterfaceGraph
+}
+
+// Return the related instances of each TwinInstance of the graph
+func getRelatedInstances(twinInstanceGraph TwinInstanceGraph) map[string][]string {
+	relatedInstances := map[string][]string{}
+
+	for _, twinInstance := range twinInstanceGraph.GetTwinInstances() {
+		relatedInstances[twinInstance.Name] = []string{}
+		for _, relationship := range twinInstance.Spec.TwinInstanceRelationships {
+			relatedInstances[twinInstance.Name] = append(relatedInstances[twinInstance.Name], relationship.Instance)
+		}
+	}
+
+	return relatedInstances
+}
+
+var settingsTwinInterfaces = []dtdv0.TwinInterface{
+	newSettingsTwinInterface("dtmi-city-city-1", "dtmi:city:City", 1, dtdv0.TwinRelationship{Name: "refNeighborhood", Interface: "dtmi-city-neighborhood-1", MaxMultiplicity: 3}),
+	newSettingsTwinInterface("dtmi-city-neighborhood-1", "dtmi:city:Neighborhood", 1, dtdv0.TwinRelationship{Name: "refPole", Interface: "dtmi-city-pole-1"}),
+	newSettingsTwinInterface("dtmi-city-pole-1", "dtmi:city:Pole", 1),
+}
+
+func TestTwinInstanceGraphSettings_LoadTwinGraphEnvironmentSettings(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		content     string
+		expected    TwinGraphEnvironmentSettings
+		expectError bool
+	}{
+		{
+			name:    "Load number of instances",
+			content: `{"twinInstances": [{"interface": "city:City", "numberOfInstances": 2, "relationships": [{"name": "refNeighborhood", "numberOfInstances": 3}]}]}`,
+			expected: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{
+						Interface:         "city:City",
+						NumberOfInstances: 2,
+						Relationships:     []TwinInstanceRelationshipSettings{{Name: "refNeighborhood", NumberOfInstances: 3}},
+					},
+				},
+			},
+		},
+		{
+			name:        "Unknown field",
+			content:     `{"twinInstances": [{"interface": "city:City", "instances": 2}]}`,
+			expected:    TwinGraphEnvironmentSettings{},
+			expectError: true,
+		},
+		{
+			name:        "Invalid JSON",
+			content:     `{"twinInstances": [`,
+			expected:    TwinGraphEnvironmentSettings{},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := LoadTwinGraphEnvironmentSettings([]byte(tt.content))
+			assert.Equal(t, tt.expectError, err != nil)
+			assert.Equal(t, tt.expected, settings)
+		})
+	}
+}
+
+func TestTwinInstanceGraphSettings_NewTwinInstanceGraphFromSettings(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		twinInterfaces []dtdv0.TwinInterface
+		settings       TwinGraphEnvironmentSettings
+		expected       map[string][]string
+	}{
+		{
+			name:           "One instance of each TwinInterface without settings",
+			twinInterfaces: settingsTwinInterfaces,
+			settings:       TwinGraphEnvironmentSettings{},
+			expected: map[string][]string{
+				"dtmi-city-city-1-001":         {"dtmi-city-neighborhood-1-001"},
+				"dtmi-city-neighborhood-1-001": {"dtmi-city-pole-1-001"},
+				"dtmi-city-pole-1-001":         {},
+			},
+		},
+		{
+			name:           "Relationships distributed across the target instances",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "city:City", Relationships: []TwinInstanceRelationshipSettings{{Name: "refNeighborhood", NumberOfInstances: 2}}},
+					{Interface: "dtmi:city:Neighborhood;1", NumberOfInstances: 2, Relationships: []TwinInstanceRelationshipSettings{{Name: "refPole", NumberOfInstances: 2}}},
+					{Interface: "dtmi-city-pole-1", NumberOfInstances: 3},
+				},
+			},
+			expected: map[string][]string{
+				"dtmi-city-city-1-001":         {"dtmi-city-neighborhood-1-001", "dtmi-city-neighborhood-1-002"},
+				"dtmi-city-neighborhood-1-001": {"dtmi-city-pole-1-001", "dtmi-city-pole-1-002"},
+				"dtmi-city-neighborhood-1-002": {"dtmi-city-pole-1-003", "dtmi-city-pole-1-001"},
+				"dtmi-city-pole-1-001":         {},
+				"dtmi-city-pole-1-002":         {},
+				"dtmi-city-pole-1-003":         {},
+			},
+		},
+		{
+			name:           "Relationships to an informed instance",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "Neighborhood", NumberOfInstances: 2, Relationships: []TwinInstanceRelationshipSettings{{Name: "refPole", Instance: "dtmi-city-pole-1-002"}}},
+					{Interface: "Pole", NumberOfInstances: 2},
+				},
+			},
+			expected: map[string][]string{
+				"dtmi-city-city-1-001":         {"dtmi-city-neighborhood-1-001"},
+				"dtmi-city-neighborhood-1-001": {"dtmi-city-pole-1-002"},
+				"dtmi-city-neighborhood-1-002": {"dtmi-city-pole-1-002"},
+				"dtmi-city-pole-1-001":         {},
+				"dtmi-city-pole-1-002":         {},
+			},
+		},
+		{
+			name: "Relationship target informed in the settings",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newSettingsTwinInterface("dtmi-city-neighborhood-1", "dtmi:city:Neighborhood", 1, dtdv0.TwinRelationship{Name: "refDevice", Interface: "dtmi-city-device-1"}),
+				newSettingsTwinInterface("dtmi-city-streetlight-1", "dtmi:city:Streetlight", 1),
+			},
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "Neighborhood", Relationships: []TwinInstanceRelationshipSettings{{Name: "refDevice", Interface: "city:Streetlight"}}},
+				},
+			},
+			expected: map[string][]string{
+				"dtmi-city-neighborhood-1-001": {"dtmi-city-streetlight-1-001"},
+				"dtmi-city-streetlight-1-001":  {},
+			},
+		},
+		{
+			name: "Relationships with minimum multiplicity and target out of the graph",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newSettingsTwinInterface("dtmi-city-neighborhood-1", "dtmi:city:Neighborhood", 1,
+					dtdv0.TwinRelationship{Name: "refPole", Interface: "dtmi-city-pole-1", MinMultiplicity: 2},
+					dtdv0.TwinRelationship{Name: "refCity", Interface: "dtmi-city-city-1"},
+				),
+				newSettingsTwinInterface("dtmi-city-pole-1", "dtmi:city:Pole", 1),
+			},
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "Pole", NumberOfInstances: 3},
+				},
+			},
+			expected: map[string][]string{
+				"dtmi-city-neighborhood-1-001": {"dtmi-city-pole-1-001", "dtmi-city-pole-1-002"},
+				"dtmi-city-pole-1-001":         {},
+				"dtmi-city-pole-1-002":         {},
+				"dtmi-city-pole-1-003":         {},
+			},
+		},
+		{
+			name: "Reference without version resolves the latest version",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newSettingsTwinInterface("dtmi-city-pole-1", "dtmi:city:Pole", 1),
+				newSettingsTwinInterface("dtmi-city-pole-2", "dtmi:city:Pole", 2),
+			},
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "city:Pole", NumberOfInstances: 2},
+				},
+			},
+			expected: map[string][]string{
+				"dtmi-city-pole-1-001": {},
+				"dtmi-city-pole-2-001": {},
+				"dtmi-city-pole-2-002": {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twinInstanceGraph, errs := NewTwinInstanceGraphFromSettings(tt.settings, newSettingsTwinInterfaceGraph(tt.twinInterfaces...))
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.expected, getRelatedInstances(twinInstanceGraph))
+		})
+	}
+}
+
+func TestTwinInstanceGraphSettings_NewTwinInstanceGraphFromSettingsErrors(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		twinInterfaces []dtdv0.TwinInterface
+		settings       TwinGraphEnvironmentSettings
+		expected       []error
+	}{
+		{
+			name:           "Unknown TwinInterface",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{{Interface: "city:Streetlight"}},
+			},
+			expected: []error{ErrUnknownTwinInterface},
+		},
+		{
+			name: "Ambiguous TwinInterface",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newSettingsTwinInterface("dtmi-city-pole-1", "dtmi:city:Pole", 1),
+				newSettingsTwinInterface("dtmi-energy-pole-1", "dtmi:energy:Pole", 1),
+			},
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{{Interface: "Pole"}},
+			},
+			expected: []error{ErrAmbiguousTwinInterface},
+		},
+		{
+			name:           "TwinInterface listed more than once",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{{Interface: "city:Pole"}, {Interface: "dtmi-city-pole-1"}},
+			},
+			expected: []error{ErrDuplicateTwinInterface},
+		},
+		{
+			name:           "Negative number of instances",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{{Interface: "city:Pole", NumberOfInstances: -1}},
+			},
+			expected: []error{ErrInvalidNumberOfInstances},
+		},
+		{
+			name:           "Unknown relationship and target instance",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "city:City", Relationships: []TwinInstanceRelationshipSettings{{Name: "refPole"}}},
+					{Interface: "city:Neighborhood", Relationships: []TwinInstanceRelationshipSettings{{Name: "refPole", Instance: "dtmi-city-pole-1-002"}}},
+				},
+			},
+			expected: []error{ErrUnknownRelationship, ErrUnknownTwinInstance},
+		},
+		{
+			name:           "Number of related instances out of the multiplicity",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "city:City", Relationships: []TwinInstanceRelationshipSettings{{Name: "refNeighborhood", NumberOfInstances: 4}}},
+					{Interface: "city:Neighborhood", NumberOfInstances: 4},
+				},
+			},
+			expected: []error{ErrRelationshipMultiplicity},
+		},
+		{
+			name:           "Number of related instances exceeds the target instances",
+			twinInterfaces: settingsTwinInterfaces,
+			settings: TwinGraphEnvironmentSettings{
+				TwinInstances: []TwinInstanceEnvironmentSettings{
+					{Interface: "city:Neighborhood", Relationships: []TwinInstanceRelationshipSettings{{Name: "refPole", NumberOfInstances: 2}}},
+				},
+			},
+			expected: []error{ErrNotEnoughTwinInstances},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twinInstanceGraph, errs := NewTwinInstanceGraphFromSettings(tt.settings, newSettingsTwinInterfaceGraph(tt.twinInterfaces...))
+			assert.Nil(t, twinInstanceGraph)
+			assert.Equal(t, len(tt.expected), len(errs))
+
+			for index, err := range errs {
+				if index < len(tt.expected) {
+					assert.True(t, errors.Is(err, tt.expected[index]), err.Error())
+				}
+			}
+		})
+	}
+}
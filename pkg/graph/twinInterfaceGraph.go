@@ -3,8 +3,10 @@ package graph
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+	"github.com/Open-Digital-Twin/ktwin-operator/pkg/inheritance"
 )
 
 const (
@@ -19,6 +21,11 @@ type TwinInterfaceGraph interface {
 	RemoveVertex(twinInterface dtdv0.TwinInterface) error
 	AddEdge(sourceTwinInterface dtdv0.TwinInterface, targetTwinInterface dtdv0.TwinInterface) error
 	RemoveEdge(sourceTwinInterface dtdv0.TwinInterface, targetTwinInterface dtdv0.TwinInterface) error
+	IsTemporaryVertex(twinInterfaceId string) bool
+	GetExtendsCycle(twinInterfaceId string) []string
+	GetExtendsChain(twinInterfaceId string) []dtdv0.TwinInterface
+	GetTwinInterfaces() []dtdv0.TwinInterface
+	GetTopologicalOrder() []dtdv0.TwinInterface
 	PrintGraph()
 }
 
@@ -146,6 +153,129 @@ func (g *twinInterfaceGraph) RemoveEdge(sourceTwinInterface dtdv0.TwinInterface,
 	return nil
 }
 
+// Check if the vertex is only known as the target of a relationship, without the TwinInterface being added to the graph
+func (g *twinInterfaceGraph) IsTemporaryVertex(twinInterfaceId string) bool {
+	vertex := g.Vertexes[twinInterfaceId]
+	return vertex == nil || vertex.HasTemporaryInterface
+}
+
+// Walk the TwinInterfaces extended by the informed TwinInterface and return the TwinInterface ids that form
+// the first cycle found, ending with the repeated id. Returns nil if the inheritance has no cycles.
+func (g *twinInterfaceGraph) GetExtendsCycle(twinInterfaceId string) []string {
+	_, err := inheritance.GetExtendsChain(twinInterfaceId, g.getExtendedTwinInterface)
+
+	if cycleErr, ok := err.(*inheritance.InheritanceCycleError); ok {
+		return cycleErr.Cycle
+	}
+
+	return nil
+}
+
+// Return the informed TwinInterface followed by all the TwinInterfaces it extends, directly or not, in topological order.
+// TwinInterfaces not added to the graph are skipped and cycles are not followed.
+func (g *twinInterfaceGraph) GetExtendsChain(twinInterfaceId string) []dtdv0.TwinInterface {
+	chain, _ := inheritance.GetExtendsChain(twinInterfaceId, g.getExtendedTwinInterface)
+	return chain
+}
+
+// Return the TwinInterfaces added to the graph, sorted by id. TwinInterfaces only known as relationship targets are skipped.
+func (g *twinInterfaceGraph) GetTwinInterfaces() []dtdv0.TwinInterface {
+	var twinInterfaceIds []string
+	for twinInterfaceId := range g.Vertexes {
+		if !g.IsTemporaryVertex(twinInterfaceId) {
+			twinInterfaceIds = append(twinInterfaceIds, twinInterfaceId)
+		}
+	}
+	sort.Strings(twinInterfaceIds)
+
+	var twinInterfaces []dtdv0.TwinInterface
+	for _, twinInterfaceId := range twinInterfaceIds {
+		twinInterfaces = append(twinInterfaces, g.Vertexes[twinInterfaceId].TwinInterface)
+	}
+
+	return twinInterfaces
+}
+
+// Return the TwinInterfaces added to the graph, each one after the TwinInterfaces it extends and the ones of its
+// components, and after the targets of its relationships unless they form a cycle. Ties and cycles are broken by id,
+// so the order is the same across calls.
+func (g *twinInterfaceGraph) GetTopologicalOrder() []dtdv0.TwinInterface {
+	remainingTwinInterfaces := g.GetTwinInterfaces()
+	placedTwinInterfaces := map[string]bool{}
+	var sortedTwinInterfaces []dtdv0.TwinInterface
+
+	arePlaced := func(twinInterfaceIds []string) bool {
+		for _, twinInterfaceId := range twinInterfaceIds {
+			if !placedTwinInterfaces[twinInterfaceId] && !g.IsTemporaryVertex(twinInterfaceId) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for len(remainingTwinInterfaces) > 0 {
+		// Prefer the TwinInterfaces with all the dependencies placed, then the ones with the required dependencies
+		// placed, which breaks relationship cycles, and then the first one, which breaks inheritance cycles
+		nextIndex := NO_INDEX
+		for _, relationshipsRequired := range []bool{true, false} {
+			for index, twinInterface := range remainingTwinInterfaces {
+				requiredIds, relationshipIds := getTwinInterfaceDependencies(twinInterface)
+				if arePlaced(requiredIds) && (!relationshipsRequired || arePlaced(relationshipIds)) {
+					nextIndex = index
+					break
+				}
+			}
+			if nextIndex != NO_INDEX {
+				break
+			}
+		}
+		if nextIndex == NO_INDEX {
+			nextIndex = 0
+		}
+
+		nextTwinInterface := remainingTwinInterfaces[nextIndex]
+		placedTwinInterfaces[nextTwinInterface.Spec.Id] = true
+		sortedTwinInterfaces = append(sortedTwinInterfaces, nextTwinInterface)
+		remainingTwinInterfaces = append(remainingTwinInterfaces[:nextIndex], remainingTwinInterfaces[nextIndex+1:]...)
+	}
+
+	return sortedTwinInterfaces
+}
+
+// Return the ids of the TwinInterfaces extended by the TwinInterface or used by its components, and the ids of the
+// targets of its relationships, without the TwinInterface itself
+func getTwinInterfaceDependencies(twinInterface dtdv0.TwinInterface) ([]string, []string) {
+	var requiredIds, relationshipIds []string
+
+	for _, parentId := range twinInterface.Spec.GetExtends() {
+		if parentId != twinInterface.Spec.Id {
+			requiredIds = append(requiredIds, parentId)
+		}
+	}
+
+	for _, component := range twinInterface.Spec.Components {
+		if component.Interface != twinInterface.Spec.Id {
+			requiredIds = append(requiredIds, component.Interface)
+		}
+	}
+
+	for _, relationship := range twinInterface.Spec.Relationships {
+		if relationship.Interface != twinInterface.Spec.Id {
+			relationshipIds = append(relationshipIds, relationship.Interface)
+		}
+	}
+
+	return requiredIds, relationshipIds
+}
+
+func (g *twinInterfaceGraph) getExtendedTwinInterface(twinInterfaceId string) (*dtdv0.TwinInterface, error) {
+	if g.IsTemporaryVertex(twinInterfaceId) {
+		return nil, nil
+	}
+
+	return &g.Vertexes[twinInterfaceId].TwinInterface, nil
+}
+
 func (g *twinInterfaceGraph) PrintGraph() {
 
 	fmt.Println("\nGraph: ")
@@ -141,3 +141,239 @@ func TestTwinInterface_AddVertex(t *testing.T) {
 		})
 	}
 }
+
+func newExtendingTwinInterface(id string, extendsInterface string) dtdv0.TwinInterface {
+	return dtdv0.TwinInterface{
+		Spec: dtdv0.TwinInterfaceSpec{
+			Id:               id,
+			ExtendsInterface: extendsInterface,
+		},
+	}
+}
+
+func newMultipleExtendingTwinInterface(id string, extends ...string) dtdv0.TwinInterface {
+	return dtdv0.TwinInterface{
+		Spec: dtdv0.TwinInterfaceSpec{
+			Id:      id,
+			Extends: extends,
+		},
+	}
+}
+
+func TestTwinInterface_GetExtendsCycle(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		twinInterfaces []dtdv0.TwinInterface
+		twinInterface  string
+		expected       []string
+	}{
+		{
+			name: "No cycle in the inheritance chain",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("TwinInterface01", "TwinInterface02"),
+				newExtendingTwinInterface("TwinInterface02", ""),
+			},
+			twinInterface: "TwinInterface01",
+			expected:      nil,
+		},
+		{
+			name: "Parent not added to the graph",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("TwinInterface01", "TwinInterface02"),
+			},
+			twinInterface: "TwinInterface01",
+			expected:      nil,
+		},
+		{
+			name: "Interface extending itself",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("TwinInterface01", "TwinInterface01"),
+			},
+			twinInterface: "TwinInterface01",
+			expected:      []string{"TwinInterface01", "TwinInterface01"},
+		},
+		{
+			name: "Cycle in the parent interfaces",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("TwinInterface01", "TwinInterface02"),
+				newExtendingTwinInterface("TwinInterface02", "TwinInterface03"),
+				newExtendingTwinInterface("TwinInterface03", "TwinInterface02"),
+			},
+			twinInterface: "TwinInterface01",
+			expected:      []string{"TwinInterface02", "TwinInterface03", "TwinInterface02"},
+		},
+		{
+			name: "Cycle through the second parent",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newMultipleExtendingTwinInterface("TwinInterface01", "TwinInterface02", "TwinInterface03"),
+				newExtendingTwinInterface("TwinInterface02", ""),
+				newExtendingTwinInterface("TwinInterface03", "TwinInterface01"),
+			},
+			twinInterface: "TwinInterface01",
+			expected:      []string{"TwinInterface01", "TwinInterface03", "TwinInterface01"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewTwinInterfaceGraph()
+
+			for _, twinInterface := range tt.twinInterfaces {
+				graph.AddVertex(twinInterface)
+			}
+
+			assert.Equal(t, tt.expected, graph.GetExtendsCycle(tt.twinInterface))
+		})
+	}
+}
+
+func TestTwinInterface_IsTemporaryVertex(t *testing.T) {
+	t.Run("Should only report relationship targets not added to the graph", func(t *testing.T) {
+		graph := NewTwinInterfaceGraph()
+		graph.AddVertex(twinInterface01)
+		graph.AddEdge(twinInterface01, twinInterface02)
+
+		assert.False(t, graph.IsTemporaryVertex("TwinInterface01"))
+		assert.True(t, graph.IsTemporaryVertex("TwinInterface02"))
+		assert.True(t, graph.IsTemporaryVertex("TwinInterface03"))
+
+		graph.AddVertex(twinInterface02)
+		assert.False(t, graph.IsTemporaryVertex("TwinInterface02"))
+	})
+}
+
+func TestTwinInterface_GetExtendsChain(t *testing.T) {
+	t.Run("Should return the TwinInterface and its parents", func(t *testing.T) {
+		graph := NewTwinInterfaceGraph()
+		twinInterfaceChild := newExtendingTwinInterface("TwinInterface01", "TwinInterface02")
+		twinInterfaceParent := newExtendingTwinInterface("TwinInterface02", "TwinInterface03")
+		graph.AddVertex(twinInterfaceChild)
+		graph.AddVertex(twinInterfaceParent)
+
+		assert.Equal(t, []dtdv0.TwinInterface{twinInterfaceChild, twinInterfaceParent}, graph.GetExtendsChain("TwinInterface01"))
+		assert.Nil(t, graph.GetExtendsChain("TwinInterface03"))
+	})
+
+	t.Run("Should stop at cycles", func(t *testing.T) {
+		graph := NewTwinInterfaceGraph()
+		twinInterfaceChild := newExtendingTwinInterface("TwinInterface01", "TwinInterface02")
+		twinInterfaceParent := newExtendingTwinInterface("TwinInterface02", "TwinInterface01")
+		graph.AddVertex(twinInterfaceChild)
+		graph.AddVertex(twinInterfaceParent)
+
+		assert.Equal(t, []dtdv0.TwinInterface{twinInterfaceChild, twinInterfaceParent}, graph.GetExtendsChain("TwinInterface01"))
+	})
+
+	t.Run("Should return shared parents once", func(t *testing.T) {
+		graph := NewTwinInterfaceGraph()
+		twinInterfaceChild := newMultipleExtendingTwinInterface("TwinInterface01", "TwinInterface02", "TwinInterface03")
+		twinInterfaceLeft := newExtendingTwinInterface("TwinInterface02", "TwinInterface04")
+		twinInterfaceRight := newExtendingTwinInterface("TwinInterface03", "TwinInterface04")
+		twinInterfaceBase := newExtendingTwinInterface("TwinInterface04", "")
+		graph.AddVertex(twinInterfaceChild)
+		graph.AddVertex(twinInterfaceLeft)
+		graph.AddVertex(twinInterfaceRight)
+		graph.AddVertex(twinInterfaceBase)
+
+		assert.Equal(t, []dtdv0.TwinInterface{twinInterfaceChild, twinInterfaceLeft, twinInterfaceRight, twinInterfaceBase}, graph.GetExtendsChain("TwinInterface01"))
+		assert.Nil(t, graph.GetExtendsCycle("TwinInterface01"))
+	})
+}
+
+func newRelatedTwinInterface(id string, relationshipTargets ...string) dtdv0.TwinInterface {
+	twinInterface := dtdv0.TwinInterface{
+		Spec: dtdv0.TwinInterfaceSpec{
+			Id: id,
+		},
+	}
+
+	for _, relationshipTarget := range relationshipTargets {
+		twinInterface.Spec.Relationships = append(twinInterface.Spec.Relationships, dtdv0.TwinRelationship{
+			Name:      "has" + relationshipTarget,
+			Interface: relationshipTarget,
+		})
+	}
+
+	return twinInterface
+}
+
+func TestTwinInterface_GetTopologicalOrder(t *testing.T) {
+	componentTwinInterface := newRelatedTwinInterface("TwinInterface01")
+	componentTwinInterface.Spec.Components = []dtdv0.TwinComponent{{Name: "component", Interface: "TwinInterface05"}}
+
+	relatedParentTwinInterface := newRelatedTwinInterface("TwinInterface02", "TwinInterface01")
+	relatedParentTwinInterface.Spec.Extends = []string{"TwinInterface03"}
+
+	tests := []struct {
+		name           string
+		twinInterfaces []dtdv0.TwinInterface
+		expectedIds    []string
+	}{
+		{
+			name: "Parents before children",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("TwinInterface01", "TwinInterface02"),
+				newExtendingTwinInterface("TwinInterface02", "TwinInterface03"),
+				newExtendingTwinInterface("TwinInterface03", ""),
+			},
+			expectedIds: []string{"TwinInterface03", "TwinInterface02", "TwinInterface01"},
+		},
+		{
+			name: "Components and relationship targets first",
+			twinInterfaces: []dtdv0.TwinInterface{
+				componentTwinInterface,
+				newRelatedTwinInterface("TwinInterface02", "TwinInterface03", "TwinInterface04"),
+				newRelatedTwinInterface("TwinInterface03"),
+				newRelatedTwinInterface("TwinInterface04"),
+				newRelatedTwinInterface("TwinInterface05"),
+			},
+			expectedIds: []string{"TwinInterface03", "TwinInterface04", "TwinInterface02", "TwinInterface05", "TwinInterface01"},
+		},
+		{
+			name: "Relationship cycles are broken by id",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newRelatedTwinInterface("TwinInterface01", "TwinInterface02"),
+				newRelatedTwinInterface("TwinInterface02", "TwinInterface03"),
+				newRelatedTwinInterface("TwinInterface03", "TwinInterface01"),
+			},
+			expectedIds: []string{"TwinInterface01", "TwinInterface03", "TwinInterface02"},
+		},
+		{
+			name: "Inheritance comes before relationship cycles",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("TwinInterface01", "TwinInterface02"),
+				relatedParentTwinInterface,
+				newRelatedTwinInterface("TwinInterface03"),
+			},
+			expectedIds: []string{"TwinInterface03", "TwinInterface02", "TwinInterface01"},
+		},
+		{
+			name: "Inheritance cycles are broken by id",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("TwinInterface02", "TwinInterface01"),
+				newExtendingTwinInterface("TwinInterface01", "TwinInterface02"),
+				newExtendingTwinInterface("TwinInterface03", "TwinInterface01"),
+			},
+			expectedIds: []string{"TwinInterface01", "TwinInterface02", "TwinInterface03"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewTwinInterfaceGraph()
+			for _, twinInterface := range tt.twinInterfaces {
+				graph.AddVertex(twinInterface)
+				for _, relationship := range twinInterface.Spec.Relationships {
+					graph.AddEdge(twinInterface, dtdv0.TwinInterface{Spec: dtdv0.TwinInterfaceSpec{Id: relationship.Interface}})
+				}
+			}
+
+			var ids []string
+			for _, twinInterface := range graph.GetTopologicalOrder() {
+				ids = append(ids, twinInterface.Spec.Id)
+			}
+
+			assert.Equal(t, tt.expectedIds, ids)
+		})
+	}
+}
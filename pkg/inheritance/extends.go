@@ -0,0 +1,187 @@
+package inheritance
+
+import (
+	"fmt"
+	"strings"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+)
+
+// Return the TwinInterface with the informed name, or nil if it does not exist
+type TwinInterfaceGetter func(name string) (*dtdv0.TwinInterface, error)
+
+// Error returned when a TwinInterface extends itself, directly or not.
+// The cycle lists the TwinInterface names, ending with the repeated one.
+type InheritanceCycleError struct {
+	Cycle []string
+}
+
+func (e *InheritanceCycleError) Error() string {
+	return "inheritance cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// Content declared with the same name by two TwinInterfaces of an inheritance chain, when none of them extends the other
+type InheritanceConflict struct {
+	Name           string
+	TwinInterfaces []string
+}
+
+func (c InheritanceConflict) String() string {
+	return fmt.Sprintf("%s is inherited from both %s and %s", c.Name, c.TwinInterfaces[0], c.TwinInterfaces[1])
+}
+
+// Walk the inheritance DAG of a TwinInterface and return it followed by all the TwinInterfaces it extends, directly or not.
+// The TwinInterfaces are returned in topological order: every TwinInterface comes before the TwinInterfaces it extends,
+// parents keep their declaration order, and a TwinInterface reached through more than one path (diamond) is returned once.
+// Parents not found are skipped. The walk does not follow cycles; the first cycle or getter error found is returned
+// along with the resolved chain.
+func GetExtendsChain(name string, getTwinInterface TwinInterfaceGetter) ([]dtdv0.TwinInterface, error) {
+	walk := extendsWalk{
+		getTwinInterface: getTwinInterface,
+		visited:          map[string]bool{},
+		pathIndex:        map[string]int{},
+	}
+
+	walk.visit(name)
+
+	// Reverse the post-order to get the topological order
+	chain := make([]dtdv0.TwinInterface, 0, len(walk.postOrder))
+	for i := len(walk.postOrder) - 1; i >= 0; i-- {
+		chain = append(chain, walk.postOrder[i])
+	}
+
+	if len(chain) == 0 {
+		return nil, walk.err
+	}
+
+	return chain, walk.err
+}
+
+type extendsWalk struct {
+	getTwinInterface TwinInterfaceGetter
+	visited          map[string]bool
+	path             []string
+	pathIndex        map[string]int
+	postOrder        []dtdv0.TwinInterface
+	err              error
+}
+
+func (w *extendsWalk) visit(name string) {
+	if index, ok := w.pathIndex[name]; ok {
+		if w.err == nil {
+			cycle := append([]string{}, w.path[index:]...)
+			w.err = &InheritanceCycleError{Cycle: append(cycle, name)}
+		}
+		return
+	}
+
+	if w.visited[name] {
+		return
+	}
+	w.visited[name] = true
+
+	twinInterface, err := w.getTwinInterface(name)
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	if twinInterface == nil {
+		return
+	}
+
+	w.pathIndex[name] = len(w.path)
+	w.path = append(w.path, name)
+
+	// Parents are visited in reverse order, so they keep the declaration order once the post-order is reversed
+	parentNames := twinInterface.Spec.GetExtends()
+	for i := len(parentNames) - 1; i >= 0; i-- {
+		w.visit(parentNames[i])
+	}
+
+	w.path = w.path[:len(w.path)-1]
+	delete(w.pathIndex, name)
+	w.postOrder = append(w.postOrder, *twinInterface)
+}
+
+// Return the contents declared with the same name by TwinInterfaces of the chain that do not extend one another.
+// Following DTDL, properties, telemetries, commands, relationships and components share the same names. A TwinInterface
+// can still redeclare the contents of the TwinInterfaces it extends, and contents of a TwinInterface reached by more
+// than one path are not conflicts. The chain is expected as returned by GetExtendsChain, with TwinInterfaces
+// identified by name.
+func GetInheritanceConflicts(twinInterfaces []dtdv0.TwinInterface) []InheritanceConflict {
+	var conflicts []InheritanceConflict
+
+	ancestors := getAncestors(twinInterfaces)
+	declaringTwinInterfaces := map[string][]string{}
+	var contentNames []string
+
+	for _, twinInterface := range twinInterfaces {
+		for _, contentName := range getContentNames(twinInterface.Spec) {
+			if _, ok := declaringTwinInterfaces[contentName]; !ok {
+				contentNames = append(contentNames, contentName)
+			}
+			declaringTwinInterfaces[contentName] = append(declaringTwinInterfaces[contentName], twinInterface.Name)
+		}
+	}
+
+	for _, contentName := range contentNames {
+		if conflict := findConflict(contentName, declaringTwinInterfaces[contentName], ancestors); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+
+	return conflicts
+}
+
+func findConflict(contentName string, twinInterfaceNames []string, ancestors map[string]map[string]bool) *InheritanceConflict {
+	for i, first := range twinInterfaceNames {
+		for _, second := range twinInterfaceNames[i+1:] {
+			if first != second && !ancestors[first][second] && !ancestors[second][first] {
+				return &InheritanceConflict{Name: contentName, TwinInterfaces: []string{first, second}}
+			}
+		}
+	}
+	return nil
+}
+
+// Map each TwinInterface of a topologically ordered chain to the names of all the TwinInterfaces it extends
+func getAncestors(twinInterfaces []dtdv0.TwinInterface) map[string]map[string]bool {
+	ancestors := map[string]map[string]bool{}
+
+	for i := len(twinInterfaces) - 1; i >= 0; i-- {
+		twinInterface := twinInterfaces[i]
+		twinInterfaceAncestors := map[string]bool{}
+
+		for _, parentName := range twinInterface.Spec.GetExtends() {
+			twinInterfaceAncestors[parentName] = true
+			for ancestorName := range ancestors[parentName] {
+				twinInterfaceAncestors[ancestorName] = true
+			}
+		}
+
+		ancestors[twinInterface.Name] = twinInterfaceAncestors
+	}
+
+	return ancestors
+}
+
+func getContentNames(spec dtdv0.TwinInterfaceSpec) []string {
+	var contentNames []string
+
+	for _, property := range spec.Properties {
+		contentNames = append(contentNames, property.Name)
+	}
+	for _, telemetry := range spec.Telemetries {
+		contentNames = append(contentNames, telemetry.Name)
+	}
+	for _, command := range spec.Commands {
+		contentNames = append(contentNames, command.Name)
+	}
+	for _, relationship := range spec.Relationships {
+		contentNames = append(contentNames, relationship.Name)
+	}
+	for _, component := range spec.Components {
+		contentNames = append(contentNames, component.Name)
+	}
+
+	return contentNames
+}
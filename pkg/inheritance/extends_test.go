@@ -0,0 +1,182 @@
+package inheritance
+
+import (
+	"errors"
+	"testing"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newExtendingTwinInterface(name string, spec dtdv0.TwinInterfaceSpec) dtdv0.TwinInterface {
+	return dtdv0.TwinInterface{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+}
+
+func newTwinInterfaceGetter(twinInterfaces ...dtdv0.TwinInterface) TwinInterfaceGetter {
+	return func(name string) (*dtdv0.TwinInterface, error) {
+		for i := range twinInterfaces {
+			if twinInterfaces[i].Name == name {
+				return &twinInterfaces[i], nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+func getNames(twinInterfaces []dtdv0.TwinInterface) []string {
+	var names []string
+	for _, twinInterface := range twinInterfaces {
+		names = append(names, twinInterface.Name)
+	}
+	return names
+}
+
+func TestInheritance_GetExtendsChain(t *testing.T) {
+
+	errNotFound := errors.New("not found")
+
+	tests := []struct {
+		name           string
+		twinInterfaces []dtdv0.TwinInterface
+		getterError    error
+		expectedNames  []string
+		expectedError  error
+	}{
+		{
+			name: "TwinInterface not found",
+		},
+		{
+			name: "Single inheritance",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{ExtendsInterface: "parent"}),
+				newExtendingTwinInterface("parent", dtdv0.TwinInterfaceSpec{Extends: []string{"grandparent"}}),
+				newExtendingTwinInterface("grandparent", dtdv0.TwinInterfaceSpec{}),
+			},
+			expectedNames: []string{"child", "parent", "grandparent"},
+		},
+		{
+			name: "Multiple inheritance with a diamond",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{Extends: []string{"left", "right"}}),
+				newExtendingTwinInterface("left", dtdv0.TwinInterfaceSpec{Extends: []string{"base"}}),
+				newExtendingTwinInterface("right", dtdv0.TwinInterfaceSpec{Extends: []string{"base"}}),
+				newExtendingTwinInterface("base", dtdv0.TwinInterfaceSpec{}),
+			},
+			expectedNames: []string{"child", "left", "right", "base"},
+		},
+		{
+			name: "Deprecated ExtendsInterface listed before Extends",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{ExtendsInterface: "right", Extends: []string{"left"}}),
+				newExtendingTwinInterface("left", dtdv0.TwinInterfaceSpec{}),
+				newExtendingTwinInterface("right", dtdv0.TwinInterfaceSpec{}),
+			},
+			expectedNames: []string{"child", "right", "left"},
+		},
+		{
+			name: "Parent not found",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{Extends: []string{"missing", "parent"}}),
+				newExtendingTwinInterface("parent", dtdv0.TwinInterfaceSpec{}),
+			},
+			expectedNames: []string{"child", "parent"},
+		},
+		{
+			name: "Cycle through the second parent",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{Extends: []string{"parent", "other"}}),
+				newExtendingTwinInterface("parent", dtdv0.TwinInterfaceSpec{}),
+				newExtendingTwinInterface("other", dtdv0.TwinInterfaceSpec{Extends: []string{"child"}}),
+			},
+			expectedNames: []string{"child", "parent", "other"},
+			expectedError: &InheritanceCycleError{Cycle: []string{"child", "other", "child"}},
+		},
+		{
+			name: "Getter error",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{}),
+			},
+			getterError:   errNotFound,
+			expectedNames: []string{"child"},
+			expectedError: errNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getTwinInterface := newTwinInterfaceGetter(tt.twinInterfaces...)
+			chain, err := GetExtendsChain("child", func(name string) (*dtdv0.TwinInterface, error) {
+				twinInterface, _ := getTwinInterface(name)
+				return twinInterface, tt.getterError
+			})
+
+			assert.Equal(t, tt.expectedNames, getNames(chain))
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestInheritance_GetInheritanceConflicts(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		twinInterfaces []dtdv0.TwinInterface
+		expected       []InheritanceConflict
+	}{
+		{
+			name: "Content redeclared by a child",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{
+					Extends:    []string{"parent"},
+					Properties: []dtdv0.TwinProperty{{Name: "name"}},
+				}),
+				newExtendingTwinInterface("parent", dtdv0.TwinInterfaceSpec{
+					Properties: []dtdv0.TwinProperty{{Name: "name"}},
+				}),
+			},
+		},
+		{
+			name: "Content inherited through a diamond",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{Extends: []string{"left", "right"}}),
+				newExtendingTwinInterface("left", dtdv0.TwinInterfaceSpec{Extends: []string{"base"}}),
+				newExtendingTwinInterface("right", dtdv0.TwinInterfaceSpec{
+					Extends:     []string{"base"},
+					Telemetries: []dtdv0.TwinTelemetry{{Name: "name"}},
+				}),
+				newExtendingTwinInterface("base", dtdv0.TwinInterfaceSpec{
+					Properties: []dtdv0.TwinProperty{{Name: "name"}},
+				}),
+			},
+		},
+		{
+			name: "Contents declared by different parents",
+			twinInterfaces: []dtdv0.TwinInterface{
+				newExtendingTwinInterface("child", dtdv0.TwinInterfaceSpec{Extends: []string{"left", "right"}}),
+				newExtendingTwinInterface("left", dtdv0.TwinInterfaceSpec{
+					Properties: []dtdv0.TwinProperty{{Name: "name"}},
+					Commands:   []dtdv0.TwinCommand{{Name: "reset"}},
+				}),
+				newExtendingTwinInterface("right", dtdv0.TwinInterfaceSpec{
+					Relationships: []dtdv0.TwinRelationship{{Name: "name"}},
+					Components:    []dtdv0.TwinComponent{{Name: "reset"}},
+				}),
+			},
+			expected: []InheritanceConflict{
+				{Name: "name", TwinInterfaces: []string{"left", "right"}},
+				{Name: "reset", TwinInterfaces: []string{"left", "right"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GetInheritanceConflicts(tt.twinInterfaces))
+		})
+	}
+}
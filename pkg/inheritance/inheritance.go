@@ -0,0 +1,142 @@
+package inheritance
+
+import (
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+)
+
+const COMPONENT_NAME_SEPARATOR = "."
+
+// Merge the specs of a TwinInterface inheritance chain into the effective spec of the first TwinInterface.
+// The chain starts with the TwinInterface and is followed by its parents in topological order, as returned by GetExtendsChain.
+// Properties, telemetries, commands and relationships declared in a TwinInterface override the inherited ones with
+// the same name. The service is inherited when the TwinInterface does not declare one and the event store persistence
+// is enabled when any TwinInterface of the chain enables it.
+func GetEffectiveSpec(twinInterfaces []dtdv0.TwinInterface) dtdv0.TwinInterfaceSpec {
+	if len(twinInterfaces) == 0 {
+		return dtdv0.TwinInterfaceSpec{}
+	}
+
+	effectiveSpec := *twinInterfaces[0].Spec.DeepCopy()
+
+	for _, parentTwinInterface := range twinInterfaces[1:] {
+		parentSpec := parentTwinInterface.Spec.DeepCopy()
+
+		for _, property := range parentSpec.Properties {
+			if !hasProperty(effectiveSpec.Properties, property.Name) {
+				effectiveSpec.Properties = append(effectiveSpec.Properties, property)
+			}
+		}
+
+		for _, telemetry := range parentSpec.Telemetries {
+			if !hasTelemetry(effectiveSpec.Telemetries, telemetry.Name) {
+				effectiveSpec.Telemetries = append(effectiveSpec.Telemetries, telemetry)
+			}
+		}
+
+		for _, command := range parentSpec.Commands {
+			if !hasCommand(effectiveSpec.Commands, command.Name) {
+				effectiveSpec.Commands = append(effectiveSpec.Commands, command)
+			}
+		}
+
+		for _, relationship := range parentSpec.Relationships {
+			if !hasRelationship(effectiveSpec.Relationships, relationship.Name) {
+				effectiveSpec.Relationships = append(effectiveSpec.Relationships, relationship)
+			}
+		}
+
+		for _, component := range parentSpec.Components {
+			if !hasComponent(effectiveSpec.Components, component.Name) {
+				effectiveSpec.Components = append(effectiveSpec.Components, component)
+			}
+		}
+
+		if effectiveSpec.Service == nil {
+			effectiveSpec.Service = parentSpec.Service
+		}
+
+		effectiveSpec.EventStore.PersistRealEvent = effectiveSpec.EventStore.PersistRealEvent || parentSpec.EventStore.PersistRealEvent
+		effectiveSpec.EventStore.PersistVirtualEvent = effectiveSpec.EventStore.PersistVirtualEvent || parentSpec.EventStore.PersistVirtualEvent
+	}
+
+	return effectiveSpec
+}
+
+// Add the properties, telemetries and commands of the TwinInterfaces used as components to the effective spec.
+// componentSpecs maps each component name to the effective spec of its TwinInterface. The names of the added
+// definitions are qualified with the component name, so they do not conflict with the TwinInterface own definitions.
+func AddComponentContents(effectiveSpec dtdv0.TwinInterfaceSpec, componentSpecs map[string]dtdv0.TwinInterfaceSpec) dtdv0.TwinInterfaceSpec {
+	for _, component := range effectiveSpec.Components {
+		componentSpec, ok := componentSpecs[component.Name]
+		if !ok {
+			continue
+		}
+
+		for _, property := range componentSpec.DeepCopy().Properties {
+			property.Name = GetComponentQualifiedName(component.Name, property.Name)
+			effectiveSpec.Properties = append(effectiveSpec.Properties, property)
+		}
+
+		for _, telemetry := range componentSpec.DeepCopy().Telemetries {
+			telemetry.Name = GetComponentQualifiedName(component.Name, telemetry.Name)
+			effectiveSpec.Telemetries = append(effectiveSpec.Telemetries, telemetry)
+		}
+
+		for _, command := range componentSpec.DeepCopy().Commands {
+			command.Name = GetComponentQualifiedName(component.Name, command.Name)
+			effectiveSpec.Commands = append(effectiveSpec.Commands, command)
+		}
+	}
+
+	return effectiveSpec
+}
+
+// Name of a property, telemetry or command declared in the TwinInterface of a component, such as airQuality.co2
+func GetComponentQualifiedName(componentName string, name string) string {
+	return componentName + COMPONENT_NAME_SEPARATOR + name
+}
+
+func hasProperty(properties []dtdv0.TwinProperty, name string) bool {
+	for _, property := range properties {
+		if property.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTelemetry(telemetries []dtdv0.TwinTelemetry, name string) bool {
+	for _, telemetry := range telemetries {
+		if telemetry.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCommand(commands []dtdv0.TwinCommand, name string) bool {
+	for _, command := range commands {
+		if command.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRelationship(relationships []dtdv0.TwinRelationship, name string) bool {
+	for _, relationship := range relationships {
+		if relationship.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasComponent(components []dtdv0.TwinComponent, name string) bool {
+	for _, component := range components {
+		if component.Name == name {
+			return true
+		}
+	}
+	return false
+}
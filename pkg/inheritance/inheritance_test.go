@@ -0,0 +1,146 @@
+package inheritance
+
+import (
+	"testing"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInheritance_GetEffectiveSpec(t *testing.T) {
+
+	parentService := &dtdv0.TwinInterfaceService{
+		AutoScaling: dtdv0.TwinInterfaceAutoScaling{
+			Metric: dtdv0.CONCURRENCY,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		twinInterfaces []dtdv0.TwinInterface
+		expected       dtdv0.TwinInterfaceSpec
+	}{
+		{
+			name:           "Empty chain",
+			twinInterfaces: nil,
+			expected:       dtdv0.TwinInterfaceSpec{},
+		},
+		{
+			name: "TwinInterface without parents",
+			twinInterfaces: []dtdv0.TwinInterface{
+				{
+					Spec: dtdv0.TwinInterfaceSpec{
+						Id:         "child",
+						Properties: []dtdv0.TwinProperty{{Name: "name"}},
+					},
+				},
+			},
+			expected: dtdv0.TwinInterfaceSpec{
+				Id:         "child",
+				Properties: []dtdv0.TwinProperty{{Name: "name"}},
+			},
+		},
+		{
+			name: "TwinInterface inheriting from parents",
+			twinInterfaces: []dtdv0.TwinInterface{
+				{
+					Spec: dtdv0.TwinInterfaceSpec{
+						Id:               "child",
+						ExtendsInterface: "parent",
+						Properties:       []dtdv0.TwinProperty{{Name: "name", Writeable: true}},
+						Relationships:    []dtdv0.TwinRelationship{{Name: "refCity", Interface: "city"}},
+						EventStore:       dtdv0.TwinInterfaceEventStore{PersistVirtualEvent: true},
+					},
+				},
+				{
+					Spec: dtdv0.TwinInterfaceSpec{
+						Id:               "parent",
+						ExtendsInterface: "grandparent",
+						Properties:       []dtdv0.TwinProperty{{Name: "name"}, {Name: "area"}},
+						Telemetries:      []dtdv0.TwinTelemetry{{Name: "temperature"}},
+						Components:       []dtdv0.TwinComponent{{Name: "sensor", Interface: "sensor"}},
+						Service:          parentService,
+					},
+				},
+				{
+					Spec: dtdv0.TwinInterfaceSpec{
+						Id:            "grandparent",
+						Commands:      []dtdv0.TwinCommand{{Name: "reset"}},
+						Relationships: []dtdv0.TwinRelationship{{Name: "refCity", Interface: "place"}, {Name: "refPole", Interface: "pole"}},
+						EventStore:    dtdv0.TwinInterfaceEventStore{PersistRealEvent: true},
+					},
+				},
+			},
+			expected: dtdv0.TwinInterfaceSpec{
+				Id:               "child",
+				ExtendsInterface: "parent",
+				Properties:       []dtdv0.TwinProperty{{Name: "name", Writeable: true}, {Name: "area"}},
+				Telemetries:      []dtdv0.TwinTelemetry{{Name: "temperature"}},
+				Commands:         []dtdv0.TwinCommand{{Name: "reset"}},
+				Relationships:    []dtdv0.TwinRelationship{{Name: "refCity", Interface: "city"}, {Name: "refPole", Interface: "pole"}},
+				Components:       []dtdv0.TwinComponent{{Name: "sensor", Interface: "sensor"}},
+				EventStore:       dtdv0.TwinInterfaceEventStore{PersistRealEvent: true, PersistVirtualEvent: true},
+				Service:          parentService,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GetEffectiveSpec(tt.twinInterfaces))
+		})
+	}
+}
+
+func TestInheritance_AddComponentContents(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		effectiveSpec  dtdv0.TwinInterfaceSpec
+		componentSpecs map[string]dtdv0.TwinInterfaceSpec
+		expected       dtdv0.TwinInterfaceSpec
+	}{
+		{
+			name: "TwinInterface without components",
+			effectiveSpec: dtdv0.TwinInterfaceSpec{
+				Properties: []dtdv0.TwinProperty{{Name: "name"}},
+			},
+			expected: dtdv0.TwinInterfaceSpec{
+				Properties: []dtdv0.TwinProperty{{Name: "name"}},
+			},
+		},
+		{
+			name: "TwinInterface with components",
+			effectiveSpec: dtdv0.TwinInterfaceSpec{
+				Properties: []dtdv0.TwinProperty{{Name: "name"}},
+				Components: []dtdv0.TwinComponent{
+					{Name: "airQualitySensor", Interface: "airqualitysensor"},
+					{Name: "noiseSensor", Interface: "noisesensor"},
+				},
+			},
+			componentSpecs: map[string]dtdv0.TwinInterfaceSpec{
+				"airQualitySensor": {
+					Properties:  []dtdv0.TwinProperty{{Name: "name"}},
+					Telemetries: []dtdv0.TwinTelemetry{{Name: "co2"}},
+					Commands:    []dtdv0.TwinCommand{{Name: "reset"}},
+				},
+			},
+			expected: dtdv0.TwinInterfaceSpec{
+				Properties:  []dtdv0.TwinProperty{{Name: "name"}, {Name: "airQualitySensor.name"}},
+				Telemetries: []dtdv0.TwinTelemetry{{Name: "airQualitySensor.co2"}},
+				Commands:    []dtdv0.TwinCommand{{Name: "airQualitySensor.reset"}},
+				Components: []dtdv0.TwinComponent{
+					{Name: "airQualitySensor", Interface: "airqualitysensor"},
+					{Name: "noiseSensor", Interface: "noisesensor"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AddComponentContents(tt.effectiveSpec, tt.componentSpecs))
+		})
+	}
+}
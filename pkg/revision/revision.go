@@ -0,0 +1,66 @@
+package revision
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+)
+
+const LATEST_VERSION = "latest"
+
+var ErrVersionNotFound = errors.New("TwinInterface version not found")
+
+// Return the TwinInterfaces of the versions of the model of the TwinInterface, sorted by version.
+// A TwinInterface without model is the only version of its model.
+func GetModelVersions(twinInterface dtdv0.TwinInterface, twinInterfaces []dtdv0.TwinInterface) []dtdv0.TwinInterface {
+	if twinInterface.Spec.ModelId == "" {
+		return []dtdv0.TwinInterface{twinInterface}
+	}
+
+	versions := []dtdv0.TwinInterface{twinInterface}
+	for _, version := range twinInterfaces {
+		if version.Spec.ModelId == twinInterface.Spec.ModelId && version.Name != twinInterface.Name {
+			versions = append(versions, version)
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Spec.Version < versions[j].Spec.Version
+	})
+
+	return versions
+}
+
+// Resolve the TwinInterface that processes the events of a TwinInstance, from the TwinInterface referenced by the
+// TwinInstance and the version informed in its interfaceVersion: the referenced TwinInterface itself, when no
+// version is informed, the TwinInterface of the model with the informed version, or the one with the highest version,
+// for latest. TwinInterfaces being deleted are not resolved from a version.
+func ResolveTwinInterface(twinInterface dtdv0.TwinInterface, interfaceVersion string, twinInterfaces []dtdv0.TwinInterface) (*dtdv0.TwinInterface, error) {
+	if interfaceVersion == "" {
+		return &twinInterface, nil
+	}
+
+	var versions []dtdv0.TwinInterface
+	for _, version := range GetModelVersions(twinInterface, twinInterfaces) {
+		if version.DeletionTimestamp == nil {
+			versions = append(versions, version)
+		}
+	}
+
+	if interfaceVersion == LATEST_VERSION && len(versions) > 0 {
+		return &versions[len(versions)-1], nil
+	}
+
+	if versionNumber, err := strconv.Atoi(interfaceVersion); err == nil {
+		for i := range versions {
+			if versions[i].Spec.Version == versionNumber {
+				return &versions[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: version %s of TwinInterface %s", ErrVersionNotFound, interfaceVersion, twinInterface.Name)
+}
@@ -0,0 +1,89 @@
+package revision
+
+import (
+	"testing"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newVersionedTwinInterface(name string, modelId string, version int) dtdv0.TwinInterface {
+	return dtdv0.TwinInterface{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       dtdv0.TwinInterfaceSpec{ModelId: modelId, Version: version},
+	}
+}
+
+func TestRevision_ResolveTwinInterface(t *testing.T) {
+
+	deletedTwinInterface := newVersionedTwinInterface("dtmi-city-pole-3", "dtmi:city:Pole", 3)
+	deletedTwinInterface.DeletionTimestamp = &metav1.Time{}
+
+	twinInterfaces := []dtdv0.TwinInterface{
+		newVersionedTwinInterface("dtmi-city-pole-2", "dtmi:city:Pole", 2),
+		newVersionedTwinInterface("dtmi-city-pole-1", "dtmi:city:Pole", 1),
+		newVersionedTwinInterface("dtmi-city-street-4", "dtmi:city:Street", 4),
+		newVersionedTwinInterface("city", "", 0),
+		deletedTwinInterface,
+	}
+
+	tests := []struct {
+		name             string
+		twinInterface    dtdv0.TwinInterface
+		interfaceVersion string
+		expectedName     string
+		expectedError    error
+	}{
+		{
+			name:          "Version not informed",
+			twinInterface: twinInterfaces[1],
+			expectedName:  "dtmi-city-pole-1",
+		},
+		{
+			name:             "Latest version",
+			twinInterface:    twinInterfaces[1],
+			interfaceVersion: "latest",
+			expectedName:     "dtmi-city-pole-2",
+		},
+		{
+			name:             "Informed version",
+			twinInterface:    twinInterfaces[0],
+			interfaceVersion: "1",
+			expectedName:     "dtmi-city-pole-1",
+		},
+		{
+			name:             "Version being deleted",
+			twinInterface:    twinInterfaces[0],
+			interfaceVersion: "3",
+			expectedError:    ErrVersionNotFound,
+		},
+		{
+			name:             "Version of another model",
+			twinInterface:    twinInterfaces[0],
+			interfaceVersion: "4",
+			expectedError:    ErrVersionNotFound,
+		},
+		{
+			name:             "Latest version of a TwinInterface without model",
+			twinInterface:    twinInterfaces[3],
+			interfaceVersion: "latest",
+			expectedName:     "city",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twinInterface, err := ResolveTwinInterface(tt.twinInterface, tt.interfaceVersion, twinInterfaces)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, twinInterface)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedName, twinInterface.Name)
+			}
+		})
+	}
+}
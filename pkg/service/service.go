@@ -1,6 +1,8 @@
 package service
 
 import (
+	"encoding/json"
+	"net/url"
 	"reflect"
 	"strconv"
 
@@ -25,6 +27,19 @@ type KtwinRelationshipSettings struct {
 	Instance  string `json:"instance"`
 }
 
+// Semantic types and units of the TwinInterface properties and telemetries, so services know the unit of the
+// values they receive. Contents without semantic types and units are not listed.
+type KtwinContentSettings struct {
+	Properties  []KtwinContentSemanticSettings `json:"properties,omitempty"`
+	Telemetries []KtwinContentSemanticSettings `json:"telemetries,omitempty"`
+}
+
+type KtwinContentSemanticSettings struct {
+	Name          string   `json:"name"`
+	SemanticTypes []string `json:"semanticTypes,omitempty"`
+	Unit          string   `json:"unit,omitempty"`
+}
+
 type TwinServiceParameters struct {
 	TwinInterface     *dtdv0.TwinInterface
 	Broker            keventing.Broker
@@ -37,8 +52,6 @@ func NewTwinService() TwinService {
 
 type TwinService interface {
 	GetService(twinServiceParameters TwinServiceParameters) *kserving.Service
-	MergeTwinService(currentService *kserving.Service, newService *kserving.Service) *kserving.Service
-	CompareTwinService(currentService *kserving.Service, newService *kserving.Service) bool
 	GetServiceDeletionCriteria(namespacedName types.NamespacedName) map[string]string
 }
 
@@ -46,18 +59,33 @@ type twinService struct{}
 
 func (e *twinService) getServiceLabels(twinInterfaceName string) map[string]string {
 	return map[string]string{
-		"ktwin/twininterface": twinInterfaceName,
+		"ktwin/twininterface":  twinInterfaceName,
+		"ktwin/twin-interface": twinInterfaceName,
 	}
 }
 
+func (e *twinService) getTwinInterfaceOwnerReference(twinInterface *dtdv0.TwinInterface) []v1.OwnerReference {
+	return []v1.OwnerReference{
+		*v1.NewControllerRef(twinInterface, dtdv0.GroupVersion.WithKind("TwinInterface")),
+	}
+}
+
+// Services created before the ktwin/twin-interface label was introduced only carry the ktwin/twininterface label,
+// which is still set on all services, so both are matched by it
 func (e *twinService) GetServiceDeletionCriteria(namespacedName types.NamespacedName) map[string]string {
-	return e.getServiceLabels(namespacedName.Name)
+	return map[string]string{
+		"ktwin/twininterface": namespacedName.Name,
+	}
 }
 
-func (e *twinService) getTwinInterfaceContainers(twinServiceParameters TwinServiceParameters) []corev1.Container {
+func (t *twinService) getTwinInterfaceContainers(twinServiceParameters TwinServiceParameters) []corev1.Container {
 	var containers []corev1.Container
 
-	brokerUrl := twinServiceParameters.Broker.Status.Address.URL.URL()
+	// The Broker address is only set when the Broker is ready
+	brokerUrl := &url.URL{}
+	if twinServiceParameters.Broker.Status.Address != nil {
+		brokerUrl = twinServiceParameters.Broker.Status.Address.URL.URL()
+	}
 	eventStoreUrl := twinServiceParameters.EventStoreService.Status.URL.URL()
 
 	environmentVariables := []corev1.EnvVar{
@@ -75,7 +103,14 @@ func (e *twinService) getTwinInterfaceContainers(twinServiceParameters TwinServi
 		},
 	}
 
-	for _, container := range twinServiceParameters.TwinInterface.Spec.Service.Template.Spec.Containers {
+	if contentSettings := t.getContentSettings(twinServiceParameters.TwinInterface); contentSettings != "" {
+		environmentVariables = append(environmentVariables, corev1.EnvVar{
+			Name:  "KTWIN_CONTENT_SETTINGS",
+			Value: contentSettings,
+		})
+	}
+
+	for _, container := range twinServiceParameters.TwinInterface.GetEffectiveSpec().Service.Template.Spec.Containers {
 		container.Env = append(container.Env, environmentVariables...)
 		containers = append(containers, container)
 	}
@@ -83,14 +118,52 @@ func (e *twinService) getTwinInterfaceContainers(twinServiceParameters TwinServi
 	return containers
 }
 
+// Return the JSON encoded KtwinContentSettings of the TwinInterface, or an empty string if no content has
+// semantic types or units
+func (t *twinService) getContentSettings(twinInterface *dtdv0.TwinInterface) string {
+	contentSettings := KtwinContentSettings{}
+	effectiveSpec := twinInterface.GetEffectiveSpec()
+
+	for _, property := range effectiveSpec.Properties {
+		if len(property.SemanticTypes) > 0 || property.Unit != "" {
+			contentSettings.Properties = append(contentSettings.Properties, KtwinContentSemanticSettings{
+				Name:          property.Name,
+				SemanticTypes: property.SemanticTypes,
+				Unit:          property.Unit,
+			})
+		}
+	}
+
+	for _, telemetry := range effectiveSpec.Telemetries {
+		if len(telemetry.SemanticTypes) > 0 || telemetry.Unit != "" {
+			contentSettings.Telemetries = append(contentSettings.Telemetries, KtwinContentSemanticSettings{
+				Name:          telemetry.Name,
+				SemanticTypes: telemetry.SemanticTypes,
+				Unit:          telemetry.Unit,
+			})
+		}
+	}
+
+	if len(contentSettings.Properties) == 0 && len(contentSettings.Telemetries) == 0 {
+		return ""
+	}
+
+	contentSettingsJSON, err := json.Marshal(contentSettings)
+	if err != nil {
+		return ""
+	}
+
+	return string(contentSettingsJSON)
+}
+
 func (t *twinService) GetService(twinServiceParameters TwinServiceParameters) *kserving.Service {
 	twinInterface := twinServiceParameters.TwinInterface
 	twinInterfaceName := twinInterface.ObjectMeta.Name
 	containers := t.getTwinInterfaceContainers(twinServiceParameters)
 	var autoScalingAnnotations map[string]string = make(map[string]string)
 
-	if !reflect.DeepEqual(twinInterface.Spec.Service.AutoScaling, dtdv0.TwinInterfaceAutoScaling{}) {
-		autoScaling := twinInterface.Spec.Service.AutoScaling
+	if !reflect.DeepEqual(twinInterface.GetEffectiveSpec().Service.AutoScaling, dtdv0.TwinInterfaceAutoScaling{}) {
+		autoScaling := twinInterface.GetEffectiveSpec().Service.AutoScaling
 		autoScalingAnnotations = make(map[string]string)
 		if autoScaling.MaxScale != nil {
 			autoScalingAnnotations["autoscaling.knative.dev/maxScale"] = strconv.Itoa(*autoScaling.MaxScale)
@@ -123,17 +196,10 @@ func (t *twinService) GetService(twinServiceParameters TwinServiceParameters) *k
 			APIVersion: "serving.knative.dev/v1",
 		},
 		ObjectMeta: v1.ObjectMeta{
-			Name:      twinInterface.ObjectMeta.Name,
-			Namespace: twinInterface.ObjectMeta.Namespace,
-			Labels:    t.getServiceLabels(twinInterfaceName),
-			OwnerReferences: []v1.OwnerReference{
-				{
-					APIVersion: twinInterface.APIVersion,
-					Kind:       twinInterface.Kind,
-					Name:       twinInterface.Name,
-					UID:        twinInterface.UID,
-				},
-			},
+			Name:            twinInterface.ObjectMeta.Name,
+			Namespace:       twinInterface.ObjectMeta.Namespace,
+			Labels:          t.getServiceLabels(twinInterfaceName),
+			OwnerReferences: t.getTwinInterfaceOwnerReference(twinInterface),
 		},
 		Spec: kserving.ServiceSpec{
 			ConfigurationSpec: kserving.ConfigurationSpec{
@@ -156,41 +222,3 @@ func (t *twinService) GetService(twinServiceParameters TwinServiceParameters) *k
 	}
 	return service
 }
-
-func (t *twinService) MergeTwinService(currentService *kserving.Service, newService *kserving.Service) *kserving.Service {
-	currentService.Spec.ConfigurationSpec = newService.Spec.ConfigurationSpec
-	return currentService
-}
-
-// Compare Twin Services.
-// If no changes between current and new, return true.
-// If some change was identified between current and new, return false.
-func (t *twinService) CompareTwinService(currentService *kserving.Service, newService *kserving.Service) bool {
-	newAnnotations := newService.Spec.ConfigurationSpec.Template.ObjectMeta.Annotations
-	currentAnnotations := currentService.Spec.ConfigurationSpec.Template.ObjectMeta.Annotations
-
-	if !reflect.DeepEqual(currentAnnotations, newAnnotations) {
-		return false
-	}
-
-	newContainers := newService.Spec.ConfigurationSpec.Template.Spec.Containers
-	currentContainers := currentService.Spec.ConfigurationSpec.Template.Spec.Containers
-
-	if len(newContainers) != len(currentContainers) {
-		return false
-	}
-
-	for i, newContainer := range newContainers {
-		currentContainer := currentContainers[i]
-
-		if newContainer.Image != currentContainer.Image {
-			return false
-		}
-
-		if !reflect.DeepEqual(newContainer.Resources, currentContainer.Resources) {
-			return false
-		}
-	}
-
-	return true
-}
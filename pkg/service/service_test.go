@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+
+	dtdv0 "github.com/Open-Digital-Twin/ktwin-operator/api/dtd/v0"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	keventing "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	kserving "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func newTwinServiceParameters(spec dtdv0.TwinInterfaceSpec) TwinServiceParameters {
+	spec.Service = &dtdv0.TwinInterfaceService{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "city", Image: "ktwin/city:0.1"}}},
+		},
+	}
+
+	broker := keventing.Broker{}
+	broker.Status.Address = &duckv1.Addressable{URL: apis.HTTP("broker.ktwin.svc.cluster.local")}
+
+	eventStoreService := kserving.Service{}
+	eventStoreService.Status.URL = apis.HTTP("event-store.ktwin.svc.cluster.local")
+
+	return TwinServiceParameters{
+		TwinInterface: &dtdv0.TwinInterface{
+			ObjectMeta: metav1.ObjectMeta{Name: "city", Namespace: "ktwin"},
+			Spec:       spec,
+		},
+		Broker:            broker,
+		EventStoreService: eventStoreService,
+	}
+}
+
+func getEnvVar(service *kserving.Service, name string) *corev1.EnvVar {
+	for _, envVar := range service.Spec.Template.Spec.Containers[0].Env {
+		if envVar.Name == name {
+			return &envVar
+		}
+	}
+	return nil
+}
+
+func TestTwinService_GetService(t *testing.T) {
+
+	tests := []struct {
+		name                    string
+		spec                    dtdv0.TwinInterfaceSpec
+		expectedContentSettings *corev1.EnvVar
+	}{
+		{
+			name: "TwinInterface without semantic types",
+			spec: dtdv0.TwinInterfaceSpec{
+				Telemetries: []dtdv0.TwinTelemetry{{Name: "temperature"}},
+			},
+		},
+		{
+			name: "TwinInterface with semantic types and units",
+			spec: dtdv0.TwinInterfaceSpec{
+				Properties: []dtdv0.TwinProperty{
+					{Name: "name"},
+					{Name: "area", SemanticTypes: []string{"Area"}, Unit: "squareMetre"},
+				},
+				Telemetries: []dtdv0.TwinTelemetry{
+					{Name: "temperature", SemanticTypes: []string{"Temperature"}, Unit: "degreeCelsius"},
+				},
+			},
+			expectedContentSettings: &corev1.EnvVar{
+				Name:  "KTWIN_CONTENT_SETTINGS",
+				Value: `{"properties":[{"name":"area","semanticTypes":["Area"],"unit":"squareMetre"}],"telemetries":[{"name":"temperature","semanticTypes":["Temperature"],"unit":"degreeCelsius"}]}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTwinService().GetService(newTwinServiceParameters(tt.spec))
+
+			assert.Equal(t, "http://broker.ktwin.svc.cluster.local", getEnvVar(service, "KTWIN_BROKER").Value)
+			assert.Equal(t, tt.expectedContentSettings, getEnvVar(service, "KTWIN_CONTENT_SETTINGS"))
+		})
+	}
+}
+
+func TestTwinService_GetService_BrokerNotReady(t *testing.T) {
+	twinServiceParameters := newTwinServiceParameters(dtdv0.TwinInterfaceSpec{})
+	twinServiceParameters.Broker.Status.Address = nil
+
+	service := NewTwinService().GetService(twinServiceParameters)
+
+	assert.Equal(t, "", getEnvVar(service, "KTWIN_BROKER").Value)
+	assert.Equal(t, "http://event-store.ktwin.svc.cluster.local", getEnvVar(service, "KTWIN_EVENT_STORE").Value)
+}
+
+func TestTwinService_GetServiceDeletionCriteria(t *testing.T) {
+	tests := []struct {
+		name          string
+		labels        map[string]string
+		expectedMatch bool
+	}{
+		{
+			name:          "Service with current labels",
+			labels:        NewTwinService().GetService(newTwinServiceParameters(dtdv0.TwinInterfaceSpec{})).Labels,
+			expectedMatch: true,
+		},
+		{
+			name:          "Service created before the ktwin/twin-interface label",
+			labels:        map[string]string{"ktwin/twininterface": "city"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Service of another TwinInterface",
+			labels:        map[string]string{"ktwin/twininterface": "city-pole", "ktwin/twin-interface": "city-pole"},
+			expectedMatch: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := NewTwinService().GetServiceDeletionCriteria(types.NamespacedName{Namespace: "ktwin", Name: "city"})
+			assert.Equal(t, tt.expectedMatch, labels.SelectorFromSet(criteria).Matches(labels.Set(tt.labels)))
+		})
+	}
+}
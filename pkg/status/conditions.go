@@ -0,0 +1,169 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kEventing "knative.dev/eventing/pkg/apis/eventing/v1"
+	kserving "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// Condition types reported by the KTWIN resources
+const (
+	CONDITION_READY             string = "Ready"
+	CONDITION_SERVICE_READY     string = "ServiceReady"
+	CONDITION_TRIGGER_READY     string = "TriggerReady"
+	CONDITION_BINDINGS_READY    string = "BindingsReady"
+	CONDITION_EVENT_STORE_BOUND string = "EventStoreBound"
+)
+
+// Condition reasons
+const (
+	REASON_READY           string = "Ready"
+	REASON_NOT_READY       string = "NotReady"
+	REASON_NOT_FOUND       string = "NotFound"
+	REASON_NOT_REQUIRED    string = "NotRequired"
+	REASON_RECONCILE_ERROR string = "ReconcileError"
+	REASON_TERMINATING     string = "TwinInterfaceTerminating"
+)
+
+// Reasons of the Kubernetes Events recorded by the controllers for the resources they create
+const (
+	EVENT_REASON_CREATED          string = "Created"
+	EVENT_REASON_UPDATED          string = "Updated"
+	EVENT_REASON_APPLY_FAILED     string = "ApplyFailed"
+	EVENT_REASON_DELETE_FAILED    string = "DeleteFailed"
+	EVENT_REASON_PRUNED           string = "Pruned"
+	EVENT_REASON_PRUNE_DRY_RUN    string = "PruneDryRun"
+	EVENT_REASON_RECONCILE_FAILED string = "ReconcileFailed"
+)
+
+func newCondition(conditionType string, ready bool, reason string, message string, generation int64) metav1.Condition {
+	conditionStatus := metav1.ConditionFalse
+	if ready {
+		conditionStatus = metav1.ConditionTrue
+	}
+
+	return metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	}
+}
+
+// Condition for a child resource that is not needed by the parent resource
+func NewNotRequiredCondition(conditionType string, message string, generation int64) metav1.Condition {
+	return newCondition(conditionType, true, REASON_NOT_REQUIRED, message, generation)
+}
+
+// Condition for an error that happened while reconciling the resources behind the condition
+func NewErrorCondition(conditionType string, err error, generation int64) metav1.Condition {
+	return newCondition(conditionType, false, REASON_RECONCILE_ERROR, err.Error(), generation)
+}
+
+// Condition for a child resource that is not created because the TwinInterface it belongs to is being deleted
+func NewTwinInterfaceTerminatingCondition(conditionType string, twinInterfaceName string, generation int64) metav1.Condition {
+	return newCondition(conditionType, false, REASON_TERMINATING, fmt.Sprintf("TwinInterface %s is being deleted", twinInterfaceName), generation)
+}
+
+// Condition computed from the Ready condition of a Knative Service
+func NewServiceReadyCondition(service *kserving.Service, generation int64) metav1.Condition {
+	if service == nil {
+		return newCondition(CONDITION_SERVICE_READY, false, REASON_NOT_FOUND, "Service not found", generation)
+	}
+
+	if !service.IsReady() {
+		return newCondition(CONDITION_SERVICE_READY, false, REASON_NOT_READY, fmt.Sprintf("Service %s is not ready", service.Name), generation)
+	}
+
+	return newCondition(CONDITION_SERVICE_READY, true, REASON_READY, fmt.Sprintf("Service %s is ready", service.Name), generation)
+}
+
+// Condition computed from the Ready condition of a Knative Trigger
+func NewTriggerReadyCondition(trigger *kEventing.Trigger, generation int64) metav1.Condition {
+	if trigger == nil {
+		return newCondition(CONDITION_TRIGGER_READY, false, REASON_NOT_FOUND, "Trigger not found", generation)
+	}
+
+	if !trigger.Status.IsReady() {
+		return newCondition(CONDITION_TRIGGER_READY, false, REASON_NOT_READY, fmt.Sprintf("Trigger %s is not ready", trigger.Name), generation)
+	}
+
+	return newCondition(CONDITION_TRIGGER_READY, true, REASON_READY, fmt.Sprintf("Trigger %s is ready", trigger.Name), generation)
+}
+
+// Condition computed from the Ready condition of the expected RabbitMQ Bindings.
+// The expected bindings not present in the existing bindings are reported as missing.
+func NewBindingsReadyCondition(conditionType string, expectedBindings []string, existingBindings []rabbitmqv1beta1.Binding, generation int64) metav1.Condition {
+	existingBindingsMap := make(map[string]rabbitmqv1beta1.Binding)
+	for _, binding := range existingBindings {
+		existingBindingsMap[binding.Name] = binding
+	}
+
+	var missingBindings []string
+	var notReadyBindings []string
+
+	for _, bindingName := range expectedBindings {
+		binding, ok := existingBindingsMap[bindingName]
+		if !ok {
+			missingBindings = append(missingBindings, bindingName)
+		} else if !IsBindingReady(binding) {
+			notReadyBindings = append(notReadyBindings, bindingName)
+		}
+	}
+
+	if len(missingBindings) > 0 {
+		return newCondition(conditionType, false, REASON_NOT_FOUND, fmt.Sprintf("Bindings not found: %s", strings.Join(missingBindings, ", ")), generation)
+	}
+
+	if len(notReadyBindings) > 0 {
+		return newCondition(conditionType, false, REASON_NOT_READY, fmt.Sprintf("Bindings not ready: %s", strings.Join(notReadyBindings, ", ")), generation)
+	}
+
+	return newCondition(conditionType, true, REASON_READY, fmt.Sprintf("%d bindings are ready", len(expectedBindings)), generation)
+}
+
+// Check if the RabbitMQ topology operator reported the Binding as Ready
+func IsBindingReady(binding rabbitmqv1beta1.Binding) bool {
+	for _, condition := range binding.Status.Conditions {
+		if condition.Type == rabbitmqv1beta1.ConditionType(CONDITION_READY) {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Ready condition summarizing the given conditions, it is only True when all of them are True
+func NewReadyCondition(conditions []metav1.Condition, generation int64) metav1.Condition {
+	var notReadyConditions []string
+
+	for _, condition := range conditions {
+		if condition.Type == CONDITION_READY {
+			continue
+		}
+		if condition.Status != metav1.ConditionTrue {
+			notReadyConditions = append(notReadyConditions, condition.Type)
+		}
+	}
+
+	if len(notReadyConditions) > 0 {
+		return newCondition(CONDITION_READY, false, REASON_NOT_READY, fmt.Sprintf("Conditions not ready: %s", strings.Join(notReadyConditions, ", ")), generation)
+	}
+
+	return newCondition(CONDITION_READY, true, REASON_READY, "All conditions are ready", generation)
+}
+
+// Set the conditions in the existing list of conditions, followed by the summarizing Ready condition.
+// Conditions not changing its status keep the original transition time.
+func SetConditions(existingConditions *[]metav1.Condition, generation int64, conditions ...metav1.Condition) {
+	for _, condition := range conditions {
+		meta.SetStatusCondition(existingConditions, condition)
+	}
+	meta.SetStatusCondition(existingConditions, NewReadyCondition(*existingConditions, generation))
+}
@@ -0,0 +1,102 @@
+package status
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/messaging-topology-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBinding(name string, ready corev1.ConditionStatus) rabbitmqv1beta1.Binding {
+	return rabbitmqv1beta1.Binding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: rabbitmqv1beta1.BindingStatus{
+			Conditions: []rabbitmqv1beta1.Condition{
+				{
+					Type:   rabbitmqv1beta1.ConditionType(CONDITION_READY),
+					Status: ready,
+				},
+			},
+		},
+	}
+}
+
+func TestStatus_NewBindingsReadyCondition(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		expectedBindings []string
+		existingBindings []rabbitmqv1beta1.Binding
+		expectedStatus   metav1.ConditionStatus
+		expectedReason   string
+	}{
+		{
+			name:             "All bindings ready",
+			expectedBindings: []string{"binding01", "binding02"},
+			existingBindings: []rabbitmqv1beta1.Binding{
+				newBinding("binding01", corev1.ConditionTrue),
+				newBinding("binding02", corev1.ConditionTrue),
+				newBinding("binding03", corev1.ConditionFalse),
+			},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: REASON_READY,
+		},
+		{
+			name:             "Binding not found",
+			expectedBindings: []string{"binding01", "binding02"},
+			existingBindings: []rabbitmqv1beta1.Binding{
+				newBinding("binding01", corev1.ConditionTrue),
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: REASON_NOT_FOUND,
+		},
+		{
+			name:             "Binding not ready",
+			expectedBindings: []string{"binding01", "binding02"},
+			existingBindings: []rabbitmqv1beta1.Binding{
+				newBinding("binding01", corev1.ConditionTrue),
+				newBinding("binding02", corev1.ConditionUnknown),
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: REASON_NOT_READY,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := NewBindingsReadyCondition(CONDITION_BINDINGS_READY, tt.expectedBindings, tt.existingBindings, 1)
+			assert.Equal(t, CONDITION_BINDINGS_READY, condition.Type)
+			assert.Equal(t, tt.expectedStatus, condition.Status)
+			assert.Equal(t, tt.expectedReason, condition.Reason)
+			assert.Equal(t, int64(1), condition.ObservedGeneration)
+		})
+	}
+}
+
+func TestStatus_SetConditions(t *testing.T) {
+	t.Run("Should be Ready only when all conditions are True", func(t *testing.T) {
+		var conditions []metav1.Condition
+
+		SetConditions(&conditions, 1,
+			NewNotRequiredCondition(CONDITION_SERVICE_READY, "No service", 1),
+			NewTriggerReadyCondition(nil, 1),
+		)
+
+		assert.Len(t, conditions, 3)
+		assert.Equal(t, metav1.ConditionFalse, conditions[2].Status)
+		assert.Equal(t, CONDITION_READY, conditions[2].Type)
+
+		SetConditions(&conditions, 2,
+			NewNotRequiredCondition(CONDITION_TRIGGER_READY, "No trigger", 2),
+		)
+
+		assert.Len(t, conditions, 3)
+		assert.Equal(t, metav1.ConditionTrue, conditions[2].Status)
+		assert.Equal(t, int64(2), conditions[2].ObservedGeneration)
+	})
+}
@@ -40,6 +40,10 @@ func NewBinding(args BindingArgs) (rabbitmqv1beta1.Binding, error) {
 	}
 
 	binding := rabbitmqv1beta1.Binding{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Binding",
+			APIVersion: "rabbitmq.com/v1beta1",
+		},
 		ObjectMeta: v1.ObjectMeta{
 			Name:            args.Name,
 			Namespace:       args.Namespace,
@@ -34,6 +34,10 @@ func NewExchange(args *ExchangeArgs) *rabbitmqv1beta1.Exchange {
 	}
 
 	return &rabbitmqv1beta1.Exchange{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Exchange",
+			APIVersion: "rabbitmq.com/v1beta1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       args.Namespace,
 			Name:            args.Name,
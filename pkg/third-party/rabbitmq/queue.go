@@ -25,6 +25,10 @@ func NewQueue(args *QueueArgs) *rabbitmqv1beta1.Queue {
 	}
 
 	return &rabbitmqv1beta1.Queue{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Queue",
+			APIVersion: "rabbitmq.com/v1beta1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       args.Namespace,
 			Name:            args.Name,